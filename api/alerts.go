@@ -0,0 +1,69 @@
+package api
+
+import (
+	"github.com/logfire-sh/cli/pkg/cmd/alerts/models"
+)
+
+func (c *Client) ListAlerts(teamId string) ([]models.CreateAlertBody, error) {
+	var response models.ListAlertsResponse
+	if err := c.REST("GET", "api/team/"+teamId+"/alert", nil, &response); err != nil {
+		return nil, withMessage(err, "failed to list alerts")
+	}
+	return response.Data, nil
+}
+
+// CreateAlert creates an alert on a view. integrationIds select the alert integrations to notify.
+func (c *Client) CreateAlert(teamId, name, viewId string, numberOfRecords, withinSeconds uint32, integrationIds []string) error {
+	data, err := c.alertRequest(teamId, name, viewId, numberOfRecords, withinSeconds, integrationIds)
+	if err != nil {
+		return err
+	}
+	return withMessage(c.REST("POST", "api/team/"+teamId+"/alert", data, nil), "failed to create alert")
+}
+
+func (c *Client) UpdateAlert(teamId, alertId, name, viewId string, numberOfRecords, withinSeconds uint32, integrationIds []string) error {
+	data, err := c.alertRequest(teamId, name, viewId, numberOfRecords, withinSeconds, integrationIds)
+	if err != nil {
+		return err
+	}
+	return withMessage(c.REST("PUT", "api/team/"+teamId+"/alert/"+alertId, data, nil), "failed to update alert")
+}
+
+func (c *Client) alertRequest(teamId, name, viewId string, numberOfRecords, withinSeconds uint32, integrationIds []string) (models.CreateAlertRequest, error) {
+	integrations, err := c.ListAlertIntegrations(teamId)
+	if err != nil {
+		return models.CreateAlertRequest{}, err
+	}
+
+	var selected []models.AlertIntegrationBody
+	for _, integration := range integrations {
+		for _, id := range integrationIds {
+			if id == integration.ModelId {
+				selected = append(selected, integration)
+			}
+		}
+	}
+
+	return models.CreateAlertRequest{
+		Name:            name,
+		ViewId:          viewId,
+		NumberOfRecords: numberOfRecords,
+		WithinSeconds:   withinSeconds,
+		Integrations:    selected,
+	}, nil
+}
+
+func (c *Client) DeleteAlerts(teamId string, alertIds []string) error {
+	err := c.REST("DELETE", "api/team/"+teamId+"/alert", models.DeleteAlertRequest{AlertIds: alertIds}, nil)
+	return withMessage(err, "failed to delete alerts")
+}
+
+// PauseAlerts pauses the alerts, or resumes them when pause is false.
+func (c *Client) PauseAlerts(teamId string, alertIds []string, pause bool) error {
+	data := models.PauseAlertRequest{AlertIds: alertIds, AlertPause: pause}
+	err := c.REST("POST", "api/team/"+teamId+"/alertpause", data, nil)
+	if pause {
+		return withMessage(err, "failed to pause alerts")
+	}
+	return withMessage(err, "failed to unpause alerts")
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/logfire-sh/cli/internal/config"
+	"github.com/logfire-sh/cli/pkg/cmd/login/models"
+)
+
+// ErrNoRefreshToken is returned when the access token expired and no refresh token was saved at login.
+var ErrNoRefreshToken = errors.New("session expired, please run `logfire login` again")
+
+// ErrEnvTokenExpired is returned when the access token set with LOGFIRE_TOKEN is rejected, as
+// only a saved token can be refreshed.
+var ErrEnvTokenExpired = errors.New("the access token set with LOGFIRE_TOKEN has expired, set a new one or unset it to use the saved login")
+
+// refreshMu makes concurrent HTTP and gRPC calls that hit an expired token share a single refresh.
+var refreshMu sync.Mutex
+
+// RefreshToken exchanges the saved refresh token for a new access token and writes both to the config.
+// staleToken is the token that was rejected; when the config already holds a different one,
+// another call refreshed it in the meantime and that token is returned without a request.
+func RefreshToken(cfg config.Config, staleToken string) (string, error) {
+	refreshMu.Lock()
+	defer refreshMu.Unlock()
+
+	if token := cfg.Get().Token; token != staleToken {
+		return token, nil
+	}
+	// A token set through LOGFIRE_TOKEN takes precedence over the saved one, which a refresh
+	// would only replace in the config file.
+	if cfg.TokenOverridden() {
+		return "", ErrEnvTokenExpired
+	}
+
+	refreshToken := cfg.Get().RefreshToken
+	if refreshToken == "" {
+		return "", ErrNoRefreshToken
+	}
+
+	// The refresh request must not go through AuthTransport, which would try to refresh again.
+	client := NewClient(&http.Client{Timeout: 10 * time.Second}, cfg.Get().EndPoint, "")
+
+	var response models.Response
+	err := client.REST("POST", "api/auth/refresh", map[string]string{"refreshToken": refreshToken}, &response)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		// Name the failed request, so that an expired session is told apart from a refresh
+		// the API does not support.
+		return "", fmt.Errorf("%w (%s %s answered %d)", ErrNoRefreshToken, apiErr.Method, apiErr.URL, apiErr.StatusCode)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh the access token: %w", err)
+	}
+	if response.BearerToken.AccessToken == "" {
+		return "", ErrNoRefreshToken
+	}
+
+	err = cfg.UpdateConfig(func(c *config.AuthConfig) {
+		c.Token = response.BearerToken.AccessToken
+		if response.BearerToken.RefreshToken != "" {
+			c.RefreshToken = response.BearerToken.RefreshToken
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return cfg.Get().Token, nil
+}
+
+// AuthTransport retries a request once with a refreshed access token when the API answers 401,
+// and fails with the error of the refresh when there is no new token. Requests without an
+// Authorization header are passed through untouched.
+type AuthTransport struct {
+	Base   http.RoundTripper
+	Config config.Config
+}
+
+func (t *AuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base().RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	staleToken := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if staleToken == "" || (req.Body != nil && req.GetBody == nil) {
+		return resp, nil
+	}
+
+	token, refreshErr := RefreshToken(t.Config, staleToken)
+	if refreshErr != nil {
+		resp.Body.Close()
+		return nil, &tokenRefreshError{refreshErr}
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		retry.Body, err = req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+	}
+	retry.Header.Set("Authorization", "Bearer "+token)
+
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	return t.base().RoundTrip(retry)
+}
+
+// tokenRefreshError is the failed refresh of AuthTransport, which Client reports as it is rather
+// than as a connection failure.
+type tokenRefreshError struct {
+	err error
+}
+
+func (e *tokenRefreshError) Error() string { return e.err.Error() }
+
+func (e *tokenRefreshError) Unwrap() error { return e.err }
+
+func (t *AuthTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
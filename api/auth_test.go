@@ -0,0 +1,136 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/logfire-sh/cli/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthTransportRefreshesExpiredToken(t *testing.T) {
+	refreshes := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/auth/refresh":
+			refreshes++
+			io.WriteString(w, `{"isSuccessful":true,"bearerToken":{"accessToken":"new-token","refreshToken":"new-refresh"}}`)
+		case "/api/team":
+			body, _ := io.ReadAll(r.Body)
+			if r.Header.Get("Authorization") != "Bearer new-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write(body)
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), ".logfire")
+	cfg, err := config.Load(config.Options{Path: path})
+	require.NoError(t, err)
+	require.NoError(t, cfg.UpdateConfig(func(c *config.AuthConfig) {
+		c.EndPoint = server.URL + "/"
+		c.Token = "old-token"
+		c.RefreshToken = "old-refresh"
+	}))
+
+	client := &http.Client{Transport: &AuthTransport{Config: cfg}}
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequest("POST", server.URL+"/api/team", bytes.NewBufferString("payload"))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer old-token")
+
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "payload", string(body))
+	}
+	assert.Equal(t, 1, refreshes)
+
+	stored, err := config.Load(config.Options{Path: path})
+	require.NoError(t, err)
+	assert.Equal(t, "new-token", stored.Get().Token)
+	assert.Equal(t, "new-refresh", stored.Get().RefreshToken)
+}
+
+func TestRefreshTokenWithEnvToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/team" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), ".logfire")
+	saved, err := config.Load(config.Options{Path: path})
+	require.NoError(t, err)
+	require.NoError(t, saved.UpdateConfig(func(c *config.AuthConfig) {
+		c.EndPoint = server.URL + "/"
+		c.Token = "saved-token"
+		c.RefreshToken = "saved-refresh"
+	}))
+
+	cfg, err := config.Load(config.Options{Path: path, Overrides: config.Overrides{Token: "env-token"}})
+	require.NoError(t, err)
+	_, err = RefreshToken(cfg, "env-token")
+	assert.ErrorIs(t, err, ErrEnvTokenExpired)
+
+	req, err := http.NewRequest("GET", server.URL+"/api/team", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer env-token")
+	_, err = (&http.Client{Transport: &AuthTransport{Config: cfg}}).Do(req)
+	assert.ErrorIs(t, err, ErrEnvTokenExpired)
+
+	stored, err := config.Load(config.Options{Path: path})
+	require.NoError(t, err)
+	assert.Equal(t, "saved-token", stored.Get().Token)
+	assert.Equal(t, "saved-refresh", stored.Get().RefreshToken)
+}
+
+func TestRefreshTokenWithoutRefreshToken(t *testing.T) {
+	cfg, err := config.Load(config.Options{Path: filepath.Join(t.TempDir(), ".logfire")})
+	require.NoError(t, err)
+
+	_, err = RefreshToken(cfg, cfg.Get().Token)
+	assert.ErrorIs(t, err, ErrNoRefreshToken)
+}
+
+func TestAuthTransportReportsFailedRefresh(t *testing.T) {
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/api/auth/refresh":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	cfg, err := config.Load(config.Options{Path: filepath.Join(t.TempDir(), ".logfire")})
+	require.NoError(t, err)
+	require.NoError(t, cfg.UpdateConfig(func(c *config.AuthConfig) {
+		c.EndPoint = server.URL + "/"
+		c.Token = "old-token"
+		c.RefreshToken = "old-refresh"
+	}))
+
+	client := NewClientFromConfig(&http.Client{Transport: &AuthTransport{Config: cfg}}, cfg)
+	err = client.REST("GET", "api/team", nil, nil)
+
+	assert.ErrorIs(t, err, ErrNoRefreshToken)
+	assert.False(t, IsConnectionError(err))
+	assert.EqualError(t, err, "session expired, please run `logfire login` again (POST "+server.URL+"/api/auth/refresh answered 404)")
+	assert.Equal(t, map[string]int{"/api/team": 1, "/api/auth/refresh": 1}, requests)
+}
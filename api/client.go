@@ -0,0 +1,267 @@
+// Package api is the REST client of the Logfire API. Every request goes through Client.REST,
+// which takes care of authentication, timeouts, retries and turning failures into typed errors.
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/logfire-sh/cli/internal/config"
+	"github.com/logfire-sh/cli/internal/debug"
+)
+
+const (
+	userAgent   = "Logfire-cli"
+	maxAttempts = 3
+)
+
+// retryDelay is the wait before the first retry; it doubles with every further attempt.
+var retryDelay = 500 * time.Millisecond
+
+// Client sends requests to the Logfire API. The endpoint and token are read from the config
+// on every request, so a token refreshed by AuthTransport is picked up by later calls.
+type Client struct {
+	http     *http.Client
+	cfg      config.Config
+	endpoint string
+	token    string
+	ctx      context.Context
+	gzip     bool
+}
+
+// NewClientFromConfig returns a client for the endpoint and credentials of cfg.
+// A nil httpClient uses http.DefaultClient, traced when --debug is set.
+func NewClientFromConfig(httpClient *http.Client, cfg config.Config) *Client {
+	return &Client{http: httpClient, cfg: cfg}
+}
+
+// NewClient returns a client for a fixed endpoint. An empty token sends unauthenticated requests.
+func NewClient(httpClient *http.Client, endpoint, token string) *Client {
+	return &Client{http: httpClient, endpoint: endpoint, token: token}
+}
+
+// WithContext returns a copy of c whose requests are cancelled with ctx.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	clone := *c
+	clone.ctx = ctx
+	return &clone
+}
+
+// WithTimeout returns a copy of c whose requests may take up to timeout.
+func (c *Client) WithTimeout(timeout time.Duration) *Client {
+	clone := *c
+	httpClient := *c.httpClient()
+	httpClient.Timeout = timeout
+	clone.http = &httpClient
+	return &clone
+}
+
+// WithGzip returns a copy of c that compresses request bodies.
+func (c *Client) WithGzip() *Client {
+	clone := *c
+	clone.gzip = true
+	return &clone
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.http == nil {
+		return debug.HTTPClient(http.DefaultClient)
+	}
+	return c.http
+}
+
+func (c *Client) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+func (c *Client) credentials() (endpoint, token string) {
+	if c.cfg != nil {
+		return c.cfg.Get().EndPoint, c.cfg.Get().Token
+	}
+	return c.endpoint, c.token
+}
+
+// REST sends body as JSON to path, relative to the API endpoint, and decodes the response into data.
+// data may be nil, a *string for the raw response, or any value encoding/json can decode into.
+// Responses with an error status or "isSuccessful": false are returned as *APIError.
+func (c *Client) REST(method, path string, body, data interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		if c.gzip {
+			payload, err = compress(payload)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	endpoint, token := c.credentials()
+	url := endpoint + strings.TrimPrefix(path, "/")
+	if path == "" {
+		url = endpoint
+	}
+
+	respBody, statusCode, err := c.send(method, url, token, payload)
+	if err != nil {
+		return err
+	}
+
+	return decode(method, url, statusCode, respBody, data)
+}
+
+// send performs the request, retrying idempotent methods on network errors and temporary server errors.
+func (c *Client) send(method, url, token string, payload []byte) ([]byte, int, error) {
+	ctx := c.context()
+	delay := retryDelay
+
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(payload))
+		if err != nil {
+			return nil, 0, err
+		}
+		req.Header.Set("User-Agent", userAgent)
+		if payload != nil {
+			req.Header.Set("Content-Type", "application/json")
+			if c.gzip {
+				req.Header.Set("Content-Encoding", "gzip")
+			}
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := c.httpClient().Do(req)
+		var refreshErr *tokenRefreshError
+		if errors.As(err, &refreshErr) {
+			return nil, 0, refreshErr.err
+		}
+
+		var respBody []byte
+		statusCode := 0
+		if err == nil {
+			statusCode = resp.StatusCode
+			respBody, err = io.ReadAll(resp.Body)
+			if closeErr := resp.Body.Close(); err == nil {
+				err = closeErr
+			}
+		}
+
+		if attempt < maxAttempts && retryable(method, statusCode, err) && ctx.Err() == nil {
+			select {
+			case <-ctx.Done():
+			case <-time.After(delay):
+			}
+			delay *= 2
+			continue
+		}
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, 0, ctx.Err()
+			}
+			return nil, 0, &ConnectionError{Err: err}
+		}
+		return respBody, statusCode, nil
+	}
+}
+
+func compress(payload []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(payload); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func retryable(method string, statusCode int, err error) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+
+	if err != nil {
+		// A host that does not resolve will not resolve on the next attempt either.
+		var dnsErr *net.DNSError
+		return !errors.As(err, &dnsErr)
+	}
+
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// envelope is the part of every API response that reports success.
+type envelope struct {
+	IsSuccessful *bool    `json:"isSuccessful"`
+	Message      messages `json:"message"`
+}
+
+// messages accepts both the list and the single string forms the API uses for "message".
+type messages []string
+
+func (m *messages) UnmarshalJSON(b []byte) error {
+	var list []string
+	if err := json.Unmarshal(b, &list); err == nil {
+		*m = list
+		return nil
+	}
+
+	var single string
+	if err := json.Unmarshal(b, &single); err == nil && single != "" {
+		*m = []string{single}
+	}
+	return nil
+}
+
+func decode(method, url string, statusCode int, body []byte, data interface{}) error {
+	var env envelope
+	isObject := bytes.HasPrefix(bytes.TrimSpace(body), []byte("{"))
+	if isObject {
+		_ = json.Unmarshal(body, &env)
+	}
+
+	if statusCode >= 400 || (env.IsSuccessful != nil && !*env.IsSuccessful) {
+		apiErr := &APIError{StatusCode: statusCode, Method: method, URL: url, Messages: env.Message}
+		if !isObject && len(bytes.TrimSpace(body)) > 0 {
+			apiErr.Messages = []string{string(bytes.TrimSpace(body))}
+		}
+		return apiErr
+	}
+
+	switch out := data.(type) {
+	case nil:
+		return nil
+	case *string:
+		*out = string(body)
+		return nil
+	default:
+		if err := json.Unmarshal(body, data); err != nil {
+			return fmt.Errorf("unexpected response from %s %s: %w", method, url, err)
+		}
+		return nil
+	}
+}
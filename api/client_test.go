@@ -0,0 +1,127 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientREST(t *testing.T) {
+	retryDelay = time.Millisecond
+
+	tests := []struct {
+		name      string
+		method    string
+		status    int
+		body      string
+		wantErr   string
+		wantCalls int
+	}{
+		{
+			name:      "success",
+			method:    "GET",
+			status:    http.StatusOK,
+			body:      `{"isSuccessful":true,"data":{"name":"api"}}`,
+			wantCalls: 1,
+		},
+		{
+			name:      "unsuccessful with message list",
+			method:    "POST",
+			status:    http.StatusOK,
+			body:      `{"isSuccessful":false,"message":["team not found","try again"]}`,
+			wantErr:   "team not found; try again",
+			wantCalls: 1,
+		},
+		{
+			name:      "unsuccessful with message string",
+			method:    "PUT",
+			status:    http.StatusOK,
+			body:      `{"isSuccessful":false,"message":"invalid UUID"}`,
+			wantErr:   "invalid UUID",
+			wantCalls: 1,
+		},
+		{
+			name:      "unauthorized",
+			method:    "GET",
+			status:    http.StatusUnauthorized,
+			wantErr:   "not authorized, please run `logfire login`",
+			wantCalls: 1,
+		},
+		{
+			name:      "GET is retried",
+			method:    "GET",
+			status:    http.StatusServiceUnavailable,
+			wantErr:   "failed with status 503",
+			wantCalls: maxAttempts,
+		},
+		{
+			name:      "POST is not retried",
+			method:    "POST",
+			status:    http.StatusServiceUnavailable,
+			wantErr:   "failed with status 503",
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				assert.Equal(t, "/api/team", r.URL.Path)
+				assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.body)
+			}))
+			defer server.Close()
+
+			var data struct {
+				Data struct {
+					Name string `json:"name"`
+				} `json:"data"`
+			}
+			err := NewClient(nil, server.URL+"/", "token").REST(tt.method, "api/team", nil, &data)
+
+			assert.Equal(t, tt.wantCalls, calls)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				assert.Equal(t, "api", data.Data.Name)
+				return
+			}
+
+			var apiErr *APIError
+			require.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, tt.status, apiErr.StatusCode)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestClientRESTRawResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "Hello From Auth!!!")
+	}))
+	defer server.Close()
+
+	var body string
+	err := NewClient(nil, server.URL+"/", "").REST("GET", "api/auth", nil, &body)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello From Auth!!!", body)
+}
+
+func TestClientRESTConnectionError(t *testing.T) {
+	retryDelay = time.Millisecond
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	endpoint := server.URL + "/"
+	server.Close()
+
+	err := NewClient(nil, endpoint, "").REST("GET", "api/team", nil, nil)
+	assert.True(t, IsConnectionError(err))
+	assert.EqualError(t, err, "connection failed (server down or no internet)")
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned when the API answers with an error status or reports the request as unsuccessful.
+type APIError struct {
+	StatusCode int
+	Method     string
+	URL        string
+	// Messages are the reasons given by the server, if any.
+	Messages []string
+}
+
+func (e *APIError) Error() string {
+	if len(e.Messages) > 0 {
+		return strings.Join(e.Messages, "; ")
+	}
+
+	switch e.StatusCode {
+	case http.StatusUnauthorized:
+		return "not authorized, please run `logfire login`"
+	case http.StatusForbidden:
+		return "permission denied"
+	case http.StatusNotFound:
+		return "not found"
+	}
+
+	if e.StatusCode >= 400 {
+		return fmt.Sprintf("%s %s failed with status %d", e.Method, e.URL, e.StatusCode)
+	}
+	return fmt.Sprintf("%s %s was not successful", e.Method, e.URL)
+}
+
+// ConnectionError is returned when the API could not be reached at all.
+type ConnectionError struct {
+	Err error
+}
+
+func (e *ConnectionError) Error() string {
+	return "connection failed (server down or no internet)"
+}
+
+func (e *ConnectionError) Unwrap() error {
+	return e.Err
+}
+
+// IsConnectionError reports whether err means the API could not be reached.
+func IsConnectionError(err error) bool {
+	var connErr *ConnectionError
+	return errors.As(err, &connErr)
+}
+
+// withMessage gives an *APIError that carries no server message a description of the failed operation.
+func withMessage(err error, message string) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && len(apiErr.Messages) == 0 && apiErr.StatusCode < 400 {
+		apiErr.Messages = []string{message}
+	}
+	return err
+}
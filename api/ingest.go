@@ -0,0 +1,26 @@
+package api
+
+import (
+	"time"
+)
+
+type LogMessage struct {
+	Dt      string `json:"dt"`
+	Message string `json:"message"`
+}
+
+// SendTestLog sends a single greeting record. The client must be created with NewClient
+// for the ingestion endpoint and the token of the receiving source.
+func (c *Client) SendTestLog() error {
+	logMessage := []LogMessage{{
+		Dt:      time.Now().UTC().Format("2006-01-02 15:04:05"),
+		Message: "Hello from Logfire!",
+	}}
+	return c.WithTimeout(5*time.Second).REST("POST", "", logMessage, nil)
+}
+
+// SendLogs posts a batch of records, each encoding to a JSON object with at least "dt" and "message".
+// The client must be created with NewClient for the ingestion endpoint and the token of the receiving source.
+func (c *Client) SendLogs(records interface{}) error {
+	return withMessage(c.WithGzip().REST("POST", "", records, nil), "failed to send logs")
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	alertModels "github.com/logfire-sh/cli/pkg/cmd/alerts/models"
+	"github.com/logfire-sh/cli/pkg/cmd/integrations/models"
+)
+
+// ListAlertIntegrations returns the integrations that can be attached to alerts.
+func (c *Client) ListAlertIntegrations(teamId string) ([]alertModels.AlertIntegrationBody, error) {
+	var response alertModels.ListAlertIntegrationsResponse
+	if err := c.REST("GET", "api/team/"+teamId+"/alertintegrations", nil, &response); err != nil {
+		return nil, withMessage(err, "failed to list alert integrations")
+	}
+	return response.Data, nil
+}
+
+func (c *Client) ListIntegrations(teamId string) ([]models.IntegrationBody, error) {
+	var response models.ListIntegrationResponse
+	if err := c.REST("GET", "api/team/"+teamId+"/integration", nil, &response); err != nil {
+		return nil, withMessage(err, "failed to list integrations")
+	}
+	return response.Data, nil
+}
+
+// CreateIntegration creates an integration of integrationType (one of models.IntegrationMap) delivering to address.
+func (c *Client) CreateIntegration(teamId, name, description, address, integrationType string) error {
+	data := models.CreateIntegrationRequest{
+		Name:            name,
+		IntegrationType: 2,
+		AlertType:       models.IntegrationMap[integrationType],
+		Description:     description,
+		Id:              address,
+	}
+	return withMessage(c.REST("POST", "api/team/"+teamId+"/integration", data, nil), "failed to create integration")
+}
+
+func (c *Client) UpdateIntegration(teamId, integrationId, name, description string) error {
+	data := models.UpdateIntegrationRequest{Name: name, Description: description}
+	err := c.REST("PUT", "api/team/"+teamId+"/integration/"+integrationId, data, nil)
+	return withMessage(err, "failed to update integration")
+}
+
+func (c *Client) DeleteIntegration(teamId, integrationId string) error {
+	err := c.REST("DELETE", "api/team/"+teamId+"/integration/"+integrationId, nil, nil)
+	return withMessage(err, "failed to delete integration")
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/logfire-sh/cli/internal/config"
+	loginModels "github.com/logfire-sh/cli/pkg/cmd/login/models"
+	resetPasswordModels "github.com/logfire-sh/cli/pkg/cmd/reset_password/models"
+	signupModels "github.com/logfire-sh/cli/pkg/cmd/signup/models"
+	updateProfileModels "github.com/logfire-sh/cli/pkg/cmd/update_profile/models"
+)
+
+const (
+	authTypeToken    = 1
+	authTypePassword = 2
+)
+
+// SignInWithToken signs in with the token sent by email through SendMagicLink.
+func (c *Client) SignInWithToken(token string) (*loginModels.Response, error) {
+	return c.signIn(loginModels.SigninRequest{AuthType: authTypeToken, Credential: strings.TrimSpace(token)})
+}
+
+func (c *Client) SignInWithPassword(email, password string) (*loginModels.Response, error) {
+	return c.signIn(loginModels.SigninRequest{Email: email, AuthType: authTypePassword, Credential: password})
+}
+
+func (c *Client) signIn(request loginModels.SigninRequest) (*loginModels.Response, error) {
+	var response loginModels.Response
+	if err := c.REST("POST", "api/auth/signin", request, &response); err != nil {
+		return nil, withMessage(err, "failed to sign in")
+	}
+	return &response, nil
+}
+
+// SaveSession stores the user and tokens of a successful sign in in the selected config context.
+func SaveSession(cfg config.Config, response *loginModels.Response) error {
+	return cfg.UpdateConfig(func(c *config.AuthConfig) {
+		c.Username = response.UserBody.Email
+		c.Role = response.UserBody.Role
+		c.Token = response.BearerToken.AccessToken
+		c.ProfileID = response.UserBody.ProfileID
+		c.RefreshToken = response.BearerToken.RefreshToken
+		c.AccountId = response.UserBody.AccountID
+		if response.TeamBody.Id != "" {
+			c.TeamId = response.TeamBody.Id
+		}
+	})
+}
+
+// SignOut revokes the session of the client's token.
+func (c *Client) SignOut(refreshToken string) error {
+	_, token := c.credentials()
+	data := map[string]string{
+		"AccessToken":  token,
+		"RefreshToken": refreshToken,
+	}
+	return withMessage(c.REST("POST", "api/auth/signout", data, nil), "failed to sign out")
+}
+
+func (c *Client) SendMagicLink(email string) error {
+	err := c.REST("POST", "api/auth/magiclink", signupModels.SignupRequest{Email: email}, nil)
+	return withMessage(err, "failed to send the sign in link")
+}
+
+// Signup registers email and returns the confirmation message of the server.
+func (c *Client) Signup(email string) (string, error) {
+	var response signupModels.SignupResponse
+	if err := c.REST("POST", "api/auth/signup", signupModels.SignupRequest{Email: email}, &response); err != nil {
+		return "", withMessage(err, "failed to sign up")
+	}
+	if len(response.Message) > 0 {
+		return response.Message[0], nil
+	}
+	return "", nil
+}
+
+func (c *Client) Onboard(profileId, firstName, lastName string) error {
+	data := signupModels.OnboardRequest{FirstName: firstName, LastName: lastName}
+	return withMessage(c.REST("PUT", "api/profile/"+profileId+"/onboard", data, nil), "failed to complete onboarding")
+}
+
+// SetPassword sets the first password of a profile that signed up with a magic link.
+func (c *Client) SetPassword(profileId, password string) error {
+	data := resetPasswordModels.ResetPasswordRequest{Password: password}
+	return withMessage(c.REST("POST", "api/profile/"+profileId+"/set-password", data, nil), "failed to set password")
+}
+
+func (c *Client) ResetPassword(profileId, password string) error {
+	data := resetPasswordModels.ResetPasswordRequest{Password: password}
+	return withMessage(c.REST("PUT", "api/profile/"+profileId+"/set-password", data, nil), "failed to change password")
+}
+
+func (c *Client) UpdateProfile(profileId, firstName, lastName, role string) error {
+	data := updateProfileModels.UpdateProfileRequest{FirstName: firstName, LastName: lastName, Role: role}
+	return withMessage(c.REST("PUT", "api/profile/"+profileId, data, nil), "failed to update profile")
+}
+
+// UpdateFlags makes teamId the default team of the profile.
+func (c *Client) UpdateFlags(profileId, teamId string) error {
+	data := updateProfileModels.UpdateFlagRequest{TeamId: teamId}
+	return withMessage(c.REST("PUT", "api/profile/"+profileId+"/update-flags", data, nil), "failed to update settings")
+}
+
+func (c *Client) DeleteProfile(profileId string) error {
+	return withMessage(c.REST("DELETE", "api/profile/"+profileId, nil, nil), "failed to delete profile")
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/logfire-sh/cli/pkg/cmd/sources/models"
+)
+
+func (c *Client) ListSources(teamId string) ([]models.Source, error) {
+	var response models.SourcesResponse
+	if err := c.REST("GET", "api/team/"+teamId+"/source", nil, &response); err != nil {
+		return nil, withMessage(err, "failed to list sources")
+	}
+	return response.Data, nil
+}
+
+func (c *Client) GetSource(teamId, sourceId string) (models.Source, error) {
+	var response models.SourceResponse
+	if err := c.REST("GET", "api/team/"+teamId+"/source/"+sourceId, nil, &response); err != nil {
+		return models.Source{}, withMessage(err, "failed to get source")
+	}
+	return response.Data, nil
+}
+
+// CreateSource creates a source for platform, one of the keys of models.PlatformMap.
+func (c *Client) CreateSource(teamId, name, platform string) (models.Source, error) {
+	sourceType, exists := models.PlatformMap[strings.ToLower(platform)]
+	if !exists {
+		return models.Source{}, errors.New("invalid platform")
+	}
+
+	var response models.SourceCreateResponse
+	data := models.SourceCreate{Name: name, SourceType: sourceType}
+	if err := c.REST("POST", "api/team/"+teamId+"/source", data, &response); err != nil {
+		return models.Source{}, withMessage(err, "failed to create source")
+	}
+	return response.Data, nil
+}
+
+func (c *Client) UpdateSource(teamId, sourceId, name string) (models.Source, error) {
+	var response models.SourceCreateResponse
+	err := c.REST("PUT", "api/team/"+teamId+"/source/"+sourceId, models.SourceCreate{Name: name}, &response)
+	if err != nil {
+		return models.Source{}, withMessage(err, "source update failed")
+	}
+	return response.Data, nil
+}
+
+func (c *Client) DeleteSource(teamId, sourceId string) error {
+	return withMessage(c.REST("DELETE", "api/team/"+teamId+"/source/"+sourceId, nil, nil), "failed to delete source")
+}
+
+// GetSchema returns the fields of the given sources, one map of field name to type per field.
+func (c *Client) GetSchema(teamId string, sourceIds []string) ([]map[string]string, error) {
+	var schema []map[string]string
+	if err := c.REST("GET", "api/team/"+teamId+"/schema?"+strings.Join(sourceIds, "&"), nil, &schema); err != nil {
+		return nil, withMessage(err, "failed to get schema")
+	}
+	return schema, nil
+}
+
+// GetSourceConfiguration returns the platform specific instructions for shipping logs to a source.
+func (c *Client) GetSourceConfiguration(teamId, sourceId string) (interface{}, error) {
+	var response models.ConfigurationResponse
+	if err := c.REST("GET", "api/team/"+teamId+"/source/"+sourceId+"/configuration", nil, &response); err != nil {
+		return nil, withMessage(err, "failed to get source configuration")
+	}
+	return response.Data, nil
+}
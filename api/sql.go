@@ -0,0 +1,31 @@
+package api
+
+import (
+	"strings"
+	"time"
+
+	"github.com/logfire-sh/cli/pkg/cmd/sql/models"
+)
+
+// recommendTimeout leaves room for the recommendations, which are generated on request.
+const recommendTimeout = 120 * time.Second
+
+// GetRecommendations returns SQL queries suggested for role.
+func (c *Client) GetRecommendations(teamId, role string) (models.RecommendResponse, error) {
+	var response models.RecommendResponse
+	path := "ai/teams/" + teamId + "/sql-recommend?role=" + strings.ReplaceAll(role, " ", "-")
+	if err := c.WithTimeout(recommendTimeout).REST("GET", path, nil, &response); err != nil {
+		return models.RecommendResponse{}, withMessage(err, "failed to get recommendations")
+	}
+	return response, nil
+}
+
+// GetFilterRecommendations returns tail filters suggested for role.
+func (c *Client) GetFilterRecommendations(teamId, role string) (models.RecommendFilterResponse, error) {
+	var response models.RecommendFilterResponse
+	path := "ai/teams/" + teamId + "/filter-recommend?role=" + strings.ReplaceAll(role, " ", "-")
+	if err := c.WithTimeout(recommendTimeout).REST("GET", path, nil, &response); err != nil {
+		return models.RecommendFilterResponse{}, withMessage(err, "failed to get filter recommendations")
+	}
+	return response, nil
+}
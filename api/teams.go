@@ -0,0 +1,61 @@
+package api
+
+import (
+	"github.com/logfire-sh/cli/pkg/cmd/teams/models"
+)
+
+func (c *Client) ListTeams() ([]models.Team, error) {
+	var response models.AllTeamResponse
+	if err := c.REST("GET", "api/team", nil, &response); err != nil {
+		return nil, withMessage(err, "failed to list teams")
+	}
+	return response.Data, nil
+}
+
+func (c *Client) CreateTeam(name string) (models.Team, error) {
+	var response models.CreateTeamResponse
+	err := c.REST("POST", "api/team", models.CreateTeamRequest{Name: name}, &response)
+	if err != nil {
+		return models.Team{}, withMessage(err, "failed to create team")
+	}
+	return response.Data, nil
+}
+
+func (c *Client) UpdateTeam(teamId, name string) (models.Team, error) {
+	var response models.CreateTeamResponse
+	err := c.REST("PUT", "api/team/"+teamId, models.CreateTeamRequest{Name: name}, &response)
+	if err != nil {
+		return models.Team{}, withMessage(err, "failed to update team")
+	}
+	return response.Data, nil
+}
+
+func (c *Client) DeleteTeam(teamId string) error {
+	return withMessage(c.REST("DELETE", "api/team/"+teamId, nil, nil), "failed to delete team")
+}
+
+func (c *Client) ListMembers(teamId string) ([]models.TeamMemberRes, error) {
+	var response models.AllTeamMemberResponse
+	if err := c.REST("GET", "api/team/"+teamId+"/members", nil, &response); err != nil {
+		return nil, withMessage(err, "failed to list members")
+	}
+	return response.Data.TeamMembers, nil
+}
+
+func (c *Client) InviteMembers(teamId string, emails []string) error {
+	err := c.REST("POST", "api/team/"+teamId+"/invites", models.TeamInviteReq{Email: emails}, nil)
+	return withMessage(err, "failed to invite members")
+}
+
+func (c *Client) RemoveMember(teamId, memberId string) error {
+	err := c.REST("DELETE", "api/team/"+teamId+"/members", models.RemoveMemberReq{MemberId: memberId}, nil)
+	return withMessage(err, "failed to remove member from team")
+}
+
+func (c *Client) UpdateMember(teamId, memberId string, role int) error {
+	data := models.UpdateMemberReq{
+		RemoveMemberReq: models.RemoveMemberReq{MemberId: memberId},
+		Role:            role,
+	}
+	return withMessage(c.REST("PUT", "api/team/"+teamId+"/members", data, nil), "failed to update member role")
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	sourceModels "github.com/logfire-sh/cli/pkg/cmd/sources/models"
+	"github.com/logfire-sh/cli/pkg/cmd/views/models"
+	"github.com/logfire-sh/cli/pkg/cmdutil/filters"
+)
+
+func (c *Client) ListViews(teamId string) ([]models.ViewResponseBody, error) {
+	var response models.ListViewResponse
+	if err := c.REST("GET", "api/team/"+teamId+"/view", nil, &response); err != nil {
+		return nil, withMessage(err, "failed to list views")
+	}
+	return response.Views, nil
+}
+
+func (c *Client) GetView(teamId, viewId string) (models.ViewResponseBody, error) {
+	var response models.ViewResponse
+	if err := c.REST("GET", "api/team/"+teamId+"/view/"+viewId, nil, &response); err != nil {
+		return models.ViewResponseBody{}, withMessage(err, "failed to get view")
+	}
+	return response.Data, nil
+}
+
+// CreateView saves the filters of a tail session as a view. startDate and endDate may be zero.
+func (c *Client) CreateView(teamId string, sourceFilter []sourceModels.Source, query *filters.Query,
+	startDate, endDate time.Time, viewName string) error {
+	data := models.ViewResponseBody{
+		SourcesFilter: sourceFilter,
+		DateFilter:    models.DateInterval{StartDate: startDate, EndDate: endDate},
+		Name:          viewName,
+	}
+	if query != nil {
+		data.TextFilter = query.SearchQueries
+		for _, filter := range query.FieldFilters {
+			data.SearchFilter = append(data.SearchFilter, models.SearchObj{
+				Key:       filter.FieldName,
+				Value:     filter.FieldValue,
+				Condition: filter.Operator.String(),
+			})
+		}
+	}
+	return withMessage(c.REST("POST", "api/team/"+teamId+"/view", data, nil), "failed to create view")
+}
+
+func (c *Client) DeleteView(teamId, viewId string) error {
+	return withMessage(c.REST("DELETE", "api/team/"+teamId+"/view/"+viewId, nil, nil), "failed to delete view")
+}
+
+// ViewQuery returns the field and text filters saved in view. Conditions may be operator names
+// such as "EQUALS" or the symbols of the filter language.
+func ViewQuery(view models.ViewResponseBody) (*filters.Query, error) {
+	query := &filters.Query{SearchQueries: view.TextFilter}
+	for _, search := range view.SearchFilter {
+		filter, err := filters.FieldFilter(search.Key, search.Value, search.Condition)
+		if err != nil {
+			return nil, fmt.Errorf("view %s: %w", view.Name, err)
+		}
+		query.Merge(filter)
+	}
+	return query, nil
+}
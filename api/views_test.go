@@ -0,0 +1,51 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/logfire-sh/cli/pkg/cmd/views/models"
+	"github.com/logfire-sh/cli/pkg/cmdutil/filters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateViewQuery(t *testing.T) {
+	var saved models.ViewResponseBody
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/team/team/view", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&saved))
+		w.Write([]byte(`{"isSuccessful":true}`))
+	}))
+	defer server.Close()
+
+	query, err := filters.ParseQuery(`level=error status>=500 -path:/health "disk full"`)
+	require.NoError(t, err)
+	require.NoError(t, NewClient(nil, server.URL+"/", "token").CreateView("team", nil, query, time.Time{}, time.Time{}, "errors"))
+
+	assert.Equal(t, "errors", saved.Name)
+	assert.Equal(t, []string{"disk full"}, saved.TextFilter)
+	assert.Equal(t, models.SearchObj{Key: "status", Value: "500", Condition: "GREATER_THAN_EQUALS"}, saved.SearchFilter[1])
+
+	loaded, err := ViewQuery(saved)
+	require.NoError(t, err)
+	assert.Equal(t, query.String(), loaded.String())
+}
+
+func TestViewQuery(t *testing.T) {
+	query, err := ViewQuery(models.ViewResponseBody{
+		SearchFilter: []models.SearchObj{{Key: "level", Value: "info", Condition: "!="}, {Key: "msg", Value: "x", Condition: "contains"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "level!=info msg:x", query.String())
+
+	query, err = ViewQuery(models.ViewResponseBody{})
+	require.NoError(t, err)
+	assert.True(t, query.IsEmpty())
+
+	_, err = ViewQuery(models.ViewResponseBody{Name: "bad", SearchFilter: []models.SearchObj{{Key: "level", Value: "info", Condition: "~"}}})
+	assert.EqualError(t, err, `view bad: unknown condition "~"`)
+}
@@ -3,25 +3,34 @@ package gui
 import (
 	"fmt"
 	"log"
-	"net/http"
 	"strings"
 
 	"github.com/epiclabs-io/winman"
 	"github.com/gdamore/tcell/v2"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
+	"github.com/logfire-sh/cli/internal/theme"
 	sourceModel "github.com/logfire-sh/cli/pkg/cmd/sources/models"
 	filterModel "github.com/logfire-sh/cli/pkg/cmd/sql/models"
 	"github.com/logfire-sh/cli/pkg/cmd/views/models"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
+	pb "github.com/logfire-sh/cli/services/flink-service"
 	"github.com/rivo/tview"
 )
 
 type Display struct {
 	*tview.Grid
-	View                  *tview.TextView
-	input                 *tview.InputField
-	BottomHelp            *tview.InputField
-	TopHelp               *tview.InputField
+	View       *tview.TextView
+	Inspector  *Inspector
+	body       *tview.Flex
+	input      *tview.InputField
+	BottomHelp *tview.InputField
+	TopHelp    *tview.InputField
+	// Volume draws the number of records of the livetail over time.
+	Volume *tview.TextView
+	// Connection shows the state of the livetail, its record rate and its filters.
+	Connection *tview.TextView
+	// Status tells whether the records are paused and how many arrived since.
+	Status                *tview.TextView
 	List                  *tview.List
 	Window                *winman.WindowBase
 	App                   *tview.Application
@@ -52,32 +61,17 @@ var schemaList []string
 
 var viewList []string
 
-func NewDisplay(cfg config.Config) *Display {
-	// Create a new theme object.
-	theme := Theme{}
-
-	selectedTheme := cfg.Get().Theme
-
-	if selectedTheme == "dark" {
-		theme.BackgroundColor = tcell.ColorBlack
-		theme.PlaceholderColor = tcell.ColorGray
-		theme.TextColor = tcell.ColorWhite
-		theme.CaretColor = tcell.ColorWhite
-
-		theme.AutocompleteBackgroundColor = tcell.ColorGray
-		theme.AutocompleteForegroundColor = tcell.Color231
-		theme.AutocompleteSelectedForegroundColor = tcell.ColorSlateGray
-		theme.AutocompleteSelectedBackgroundColor = tcell.ColorGray
-	} else {
-		theme.BackgroundColor = tcell.Color231
-		theme.PlaceholderColor = tcell.ColorGray
-		theme.TextColor = tcell.ColorBlack
-		theme.CaretColor = tcell.ColorBlack
-
-		theme.AutocompleteBackgroundColor = tcell.ColorGray
-		theme.AutocompleteForegroundColor = tcell.Color231
-		theme.AutocompleteSelectedForegroundColor = tcell.ColorSlateGray
-		theme.AutocompleteSelectedBackgroundColor = tcell.ColorGray
+// NewDisplay lays out the GUI with the colours of palette.
+func NewDisplay(cfg config.Config, palette *theme.Palette) *Display {
+	colors := Theme{
+		BackgroundColor:                     palette.UI.Background.TCell(),
+		PlaceholderColor:                    palette.UI.Placeholder.TCell(),
+		TextColor:                           palette.UI.Text.TCell(),
+		CaretColor:                          palette.UI.Caret.TCell(),
+		AutocompleteBackgroundColor:         palette.UI.AutocompleteBackground.TCell(),
+		AutocompleteForegroundColor:         palette.UI.AutocompleteText.TCell(),
+		AutocompleteSelectedForegroundColor: palette.UI.AutocompleteSelectedText.TCell(),
+		AutocompleteSelectedBackgroundColor: palette.UI.AutocompleteSelectedBackground.TCell(),
 	}
 
 	//var sourcesTask []Task
@@ -86,9 +80,9 @@ func NewDisplay(cfg config.Config) *Display {
 
 	app := tview.NewApplication()
 
-	client := &http.Client{}
+	client := api.NewClientFromConfig(nil, cfg)
 
-	sourcesList, err := APICalls.GetAllSources(client, cfg.Get().Token, cfg.Get().EndPoint, cfg.Get().TeamId)
+	sourcesList, err := client.ListSources(cfg.Get().TeamId)
 	if err != nil {
 		log.Fatalln(fmt.Sprint(err))
 	}
@@ -98,12 +92,12 @@ func NewDisplay(cfg config.Config) *Display {
 		sourceIds = append(sourceIds, source.ID)
 	}
 
-	schemaMap, err := APICalls.GetSchema(cfg.Get().Token, cfg.Get().EndPoint, cfg.Get().TeamId, sourceIds)
+	schemaMap, err := client.GetSchema(cfg.Get().TeamId, sourceIds)
 	if err != nil {
 		log.Fatalln(fmt.Sprint(err))
 	}
 
-	views, err := APICalls.ListView(cfg.Get().Token, cfg.Get().EndPoint, cfg.Get().TeamId)
+	views, err := client.ListViews(cfg.Get().TeamId)
 	if err != nil {
 		log.Fatalln(fmt.Sprint(err))
 	}
@@ -131,8 +125,7 @@ func NewDisplay(cfg config.Config) *Display {
 		}
 	}
 
-	filterRecommendations, err := APICalls.GetFilterRecommendations(cfg.Get().Token, cfg.Get().EndPoint,
-		cfg.Get().TeamId,
+	filterRecommendations, err := client.GetFilterRecommendations(cfg.Get().TeamId,
 		cfg.Get().Role)
 	if err != nil {
 		log.Fatalln(fmt.Sprint(err))
@@ -159,7 +152,7 @@ func NewDisplay(cfg config.Config) *Display {
 		SetLabel("> ").
 		SetFieldWidth(0).
 		SetAcceptanceFunc(tview.InputFieldMaxLength(200)).
-		SetFieldStyle(tcell.StyleDefault).SetLabelColor(theme.CaretColor).SetFieldTextColor(theme.TextColor)
+		SetFieldStyle(tcell.StyleDefault).SetLabelColor(colors.CaretColor).SetFieldTextColor(colors.TextColor)
 
 	// Set up autocomplete function.
 	var typedText string
@@ -171,7 +164,7 @@ func NewDisplay(cfg config.Config) *Display {
 		typedText = currentText
 
 		if strings.HasPrefix(typedText, "field-filter=") {
-			field := strings.TrimPrefix(typedText, "field-filter=")
+			_, field := splitLastTerm(strings.TrimPrefix(typedText, "field-filter="))
 			if contains(schemaList, field) {
 				// If a field is already selected, suggest the appropriate operators
 				fieldType, exists := fieldTypeMap[field]
@@ -256,28 +249,28 @@ func NewDisplay(cfg config.Config) *Display {
 
 				inputField.SetText(updatedText)
 			} else if strings.HasPrefix(typedText, "field-filter=") {
-				// If the current text is "field-filter=", show the schemaList suggestions
-				if typedText == "field-filter=" {
-					inputField.SetText("field-filter=" + text)
-				} else {
+				// Only the last term of the expression is completed
+				terms, field := splitLastTerm(strings.TrimPrefix(typedText, "field-filter="))
+				if field == "" {
+					inputField.SetText("field-filter=" + terms + text)
+				} else if fieldType, exists := fieldTypeMap[field]; exists {
 					// If a field is already selected, suggest the appropriate operators
-					field := strings.TrimPrefix(typedText, "field-filter=")
-					if fieldType, exists := fieldTypeMap[field]; exists {
-						switch fieldType {
-						case "string":
-							if contains(stringOptions, text) {
-								inputField.SetText("field-filter=" + field + text)
-							}
-						case "int":
-							if contains(integerOptions, text) {
-								inputField.SetText("field-filter=" + field + text)
-							}
-						case "bool":
-							if contains(booleanOptions, text) {
-								inputField.SetText("field-filter=" + field + text)
-							}
+					switch fieldType {
+					case "string":
+						if contains(stringOptions, text) {
+							inputField.SetText("field-filter=" + terms + field + text)
+						}
+					case "int":
+						if contains(integerOptions, text) {
+							inputField.SetText("field-filter=" + terms + field + text)
+						}
+					case "bool":
+						if contains(booleanOptions, text) {
+							inputField.SetText("field-filter=" + terms + field + text)
 						}
 					}
+				} else {
+					inputField.SetText("field-filter=" + terms + text)
 				}
 			} else if strings.HasPrefix(typedText, "view=") {
 				inputField.SetText("view=" + text)
@@ -288,50 +281,74 @@ func NewDisplay(cfg config.Config) *Display {
 		return source == tview.AutocompletedTab || source == tview.AutocompletedClick
 	})
 
-	inputField.SetAutocompleteStyles(theme.AutocompleteBackgroundColor, tcell.StyleDefault.Foreground(theme.AutocompleteForegroundColor), tcell.StyleDefault.Background(theme.AutocompleteSelectedForegroundColor).Foreground(theme.AutocompleteSelectedBackgroundColor))
+	inputField.SetAutocompleteStyles(colors.AutocompleteBackgroundColor, tcell.StyleDefault.Foreground(colors.AutocompleteForegroundColor), tcell.StyleDefault.Background(colors.AutocompleteSelectedForegroundColor).Foreground(colors.AutocompleteSelectedBackgroundColor))
 
-	inputField.SetBackgroundColor(theme.BackgroundColor)
+	inputField.SetBackgroundColor(colors.BackgroundColor)
 
 	// BottomHelp
 	BottomHelp := tview.NewInputField().
 		SetFieldWidth(0).
 		SetFieldStyle(tcell.StyleDefault).
-		SetPlaceholder("  3.source [source=source-name,source-name,source-name...] 4.start-date [start-date=now-2d] 5.end-date [end-date=now] 6.field-filter [field-filter=level=info] 7.save-view [save-view=name]").
-		SetPlaceholderTextColor(theme.PlaceholderColor)
+		SetPlaceholderTextColor(colors.PlaceholderColor)
 
 	BottomHelp.SetDisabled(true)
-	BottomHelp.SetBackgroundColor(theme.BackgroundColor)
+	BottomHelp.SetBackgroundColor(colors.BackgroundColor)
 
 	//TopHelp
 	TopHelp := tview.NewInputField().
 		SetFieldWidth(0).
 		SetFieldStyle(tcell.StyleDefault).
-		SetPlaceholder("  Stream > Livetail | 1. livetail 2. view 9.QUIT [q | quit | exit]").
-		SetPlaceholderTextColor(theme.PlaceholderColor)
+		SetPlaceholderTextColor(colors.PlaceholderColor)
 
 	TopHelp.SetDisabled(true)
-	TopHelp.SetBackgroundColor(theme.BackgroundColor)
+	TopHelp.SetBackgroundColor(colors.BackgroundColor)
 
 	textView := tview.NewTextView().
 		SetDynamicColors(true).
 		SetRegions(true).
 		SetWordWrap(true)
 
-	textView.SetBackgroundColor(theme.BackgroundColor)
+	textView.SetBackgroundColor(colors.BackgroundColor)
+
+	// The records fill the body, next to the inspector once a record is opened.
+	body := tview.NewFlex().AddItem(textView, 0, 3, false)
 
 	// Create the Grid and add items to it.
-	grid := tview.NewGrid().SetRows(1, -1, 1, 1).SetColumns(-1)
+	grid := tview.NewGrid().SetRows(1, 1, -1, 1, 1, 1).SetColumns(-1)
+	// The volume of the records above them
+	volume := tview.NewTextView().
+		SetDynamicColors(true)
+	volume.SetBackgroundColor(colors.BackgroundColor)
+	volume.SetTextColor(colors.PlaceholderColor)
+	// The status bar below the records
+	connection := tview.NewTextView().
+		SetDynamicColors(true)
+	connection.SetBackgroundColor(colors.BackgroundColor)
+	connection.SetTextColor(colors.PlaceholderColor)
+	status := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignRight)
+	status.SetBackgroundColor(colors.BackgroundColor)
+	status.SetTextColor(colors.TextColor)
+	statusBar := tview.NewFlex().AddItem(connection, 0, 1, false).AddItem(status, 32, 0, false)
+
 	grid.AddItem(TopHelp, 0, 0, 1, 1, 0, 0, false)
-	grid.AddItem(textView, 1, 0, 1, 1, 0, 0, false)
-	grid.AddItem(inputField, 2, 0, 1, 1, 0, 0, true)
-	grid.AddItem(BottomHelp, 3, 0, 1, 1, 0, 0, false).SetBackgroundColor(theme.BackgroundColor)
+	grid.AddItem(volume, 1, 0, 1, 1, 0, 0, false)
+	grid.AddItem(body, 2, 0, 1, 1, 0, 0, false)
+	grid.AddItem(statusBar, 3, 0, 1, 1, 0, 0, false)
+	grid.AddItem(inputField, 4, 0, 1, 1, 0, 0, true)
+	grid.AddItem(BottomHelp, 5, 0, 1, 1, 0, 0, false).SetBackgroundColor(colors.BackgroundColor)
 
 	return &Display{
 		Grid:                  grid,
 		View:                  textView,
+		body:                  body,
 		input:                 inputField,
 		BottomHelp:            BottomHelp,
 		TopHelp:               TopHelp,
+		Volume:                volume,
+		Connection:            connection,
+		Status:                status,
 		App:                   app,
 		SourceList:            sourcesList,
 		ViewsList:             views,
@@ -339,6 +356,24 @@ func NewDisplay(cfg config.Config) *Display {
 	}
 }
 
+// OpenInspector shows record in the inspector, next to the records.
+func (d *Display) OpenInspector(record *pb.FilteredRecord) {
+	d.Inspector.Show(record)
+	if d.body.GetItemCount() == 1 {
+		d.body.AddItem(d.Inspector, 0, 2, true)
+	}
+}
+
+// CloseInspector gives the whole body back to the records.
+func (d *Display) CloseInspector() {
+	d.body.RemoveItem(d.Inspector)
+}
+
+// InspectorOpen reports whether the inspector is shown.
+func (d *Display) InspectorOpen() bool {
+	return d.body.GetItemCount() > 1
+}
+
 // Helper function to check if a slice contains a string
 func contains(slice []string, item string) bool {
 	for _, a := range slice {
@@ -348,3 +383,14 @@ func contains(slice []string, item string) bool {
 	}
 	return false
 }
+
+// splitLastTerm splits a filter expression before its last term, keeping a leading - with the
+// earlier terms so that completions only replace the field name being typed.
+func splitLastTerm(expression string) (terms, last string) {
+	i := strings.LastIndexAny(expression, " \t") + 1
+	terms, last = expression[:i], expression[i:]
+	if strings.HasPrefix(last, "-") {
+		terms, last = terms+"-", last[1:]
+	}
+	return terms, last
+}
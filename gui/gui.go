@@ -4,17 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
+	"github.com/logfire-sh/cli/internal/theme"
 	"github.com/logfire-sh/cli/livetail"
 	"github.com/logfire-sh/cli/pkg/cmd/factory"
 	"github.com/logfire-sh/cli/pkg/cmd/sources/models"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	"github.com/logfire-sh/cli/pkg/cmdutil/filters"
 
 	"github.com/gdamore/tcell/v2"
@@ -26,24 +27,28 @@ type UI struct {
 	Display *Display
 	logs    string
 
+	view recordsView
+
 	f *cmdutil.Factory
 
 	Config config.Config
+	// Palette colours the records and the GUI, and Keymap binds its keys and commands.
+	Palette *theme.Palette
+	Keymap  *Keymap
 
 	Livetail *livetail.Livetail
 
-	StartDateTimeFilter       time.Time
-	EndDateTimeFilter         time.Time
-	SourceFilter              []string
-	SearchFilter              []string
-	FieldBasedFilterName      string
-	FieldBasedFilterValue     string
-	FieldBasedFilterCondition string
+	StartDateTimeFilter time.Time
+	EndDateTimeFilter   time.Time
+	SourceFilter        []string
+	Filter              *filters.Query
 
-	StartDateUnParsed string
-	EndDateUnParsed   string
+	// Ctx ends the display and the livetail sessions on quit.
+	Ctx    context.Context
+	cancel context.CancelFunc
 
-	Ctx context.Context
+	// volumeRefresh asks watchVolume to read the volume line again.
+	volumeRefresh chan struct{}
 }
 
 type LivetailStatus struct {
@@ -54,17 +59,24 @@ var livetailStatus = &LivetailStatus{
 	LivetailEnabled: false,
 }
 
-func NewUI(cfg config.Config) *UI {
+func NewUI(cfg config.Config, palette *theme.Palette, keymap *Keymap) *UI {
 
-	displayInstance := NewDisplay(cfg)
+	displayInstance := NewDisplay(cfg, palette)
 	ui := &UI{
 		Config:  cfg,
+		Palette: palette,
+		Keymap:  keymap,
 		Display: displayInstance,
 		app:     displayInstance.App,
+
+		view:          recordsView{selected: -1},
+		volumeRefresh: make(chan struct{}, 1),
 	}
+	ui.Display.Inspector = NewInspector(palette, ui.Display.View.GetBackgroundColor(), ui.addFilterTerm, ui.closeInspector)
 	ui.app.EnableMouse(true)
 	ui.SetDisplayCapture()
 	ui.Display.Livetail = true
+	ui.showHelp()
 
 	ui.f = factory.New()
 
@@ -77,12 +89,10 @@ func NewUI(cfg config.Config) *UI {
 
 	// go checkWaitingForLogs(ui, ui.Livetail)
 
-	ui.Ctx = context.Background()
-
-	ui.Livetail.CreateConnection()
-
-	time.Sleep(200 * time.Millisecond)
+	ui.Ctx, ui.cancel = context.WithCancel(context.Background())
 
+	go display(ui, ui.Livetail)
+	go ui.watchVolume()
 	RunLivetail(ui, livetailStatus)
 	return ui
 }
@@ -96,44 +106,26 @@ func display(u *UI, l *livetail.Livetail) {
 			return
 		default:
 			u.app.QueueUpdateDraw(func() {
-				// Case 1: If l.Logs is empty, show "Waiting for logs..." with progress dots
-				if len(l.Logs) == 0 {
+				// A stopped livetail leaves the view as it is, such as the list of views.
+				if !livetailStatus.LivetailEnabled {
+					u.updateStatus()
+					return
+				}
+
+				// If no record arrived yet, show "Waiting for logs..." with progress dots
+				if _, next := l.Records.Bounds(); next == 0 {
 					numDots = (numDots + 1) % 4
-					var waitingMessage string
-					if u.Config.Get().Theme == "dark" {
-						waitingMessage = "[white]" + "Waiting for logs" + strings.Repeat(".", numDots)
-					} else {
-						waitingMessage = "[black]" + "Waiting for logs" + strings.Repeat(".", numDots)
-					}
+					waitingMessage := u.Palette.UI.Text.Tag() + "Waiting for logs" + strings.Repeat(".", numDots)
 					// Pad the message with spaces to keep it a constant length
 					paddedMessage := fmt.Sprintf("%-40s", waitingMessage)
 					u.Display.View.SetTextAlign(tview.AlignCenter)
 					u.Display.View.SetText(paddedMessage)
-				} else {
-					// Reset the numDots counter
-					numDots = 0
-
-					// Case 4: If l.Logs is not empty and only contains "Waiting for logs...", do nothing
-					if l.Logs == "Waiting for logs..." {
-						return
-					}
-
-					// Case 2: If l.Logs is not empty and contains "Waiting for logs..." along with other text, remove "Waiting for logs..." and keep the rest.
-					if strings.Contains(l.Logs, "Waiting for logs") {
-						updatedLogs := strings.ReplaceAll(l.Logs, "Waiting for logs...", "")
-						updatedLogs = strings.ReplaceAll(updatedLogs, "Waiting for logs..", "")
-						updatedLogs = strings.ReplaceAll(updatedLogs, "Waiting for logs.", "")
-						updatedLogs = strings.ReplaceAll(updatedLogs, "Waiting for logs", "")
-						u.logs = strings.TrimSpace(updatedLogs) // remove extra spaces if any
-						u.Display.View.SetText(u.logs)
-						u.Display.View.SetTextAlign(tview.AlignLeft)
-					} else {
-						// Case 3: If l.Logs is not empty and doesn't contain "Waiting for logs...", do nothing.
-						u.logs = l.Logs
-						u.Display.View.SetText(l.Logs)
-						u.Display.View.SetTextAlign(tview.AlignLeft)
-					}
+					u.resetView()
+					return
 				}
+
+				numDots = 0
+				u.render()
 			})
 
 			time.Sleep(500 * time.Millisecond)
@@ -142,22 +134,223 @@ func display(u *UI, l *livetail.Livetail) {
 
 }
 
-func splitFieldFilterValue(input string) (field, operator, value string) {
-	filterValue := strings.SplitN(input, "field-filter=", 2)[1]
+// recordsView tracks which records of the buffer of the livetail the view shows, by sequence
+// number.
+type recordsView struct {
+	// first and next are the sequence numbers of the first record written to the view and of the
+	// record after the last.
+	first, next uint64
+	// selected is the record under the cursor, -1 while the view follows the new records.
+	selected int64
+	// paused stops writing records to the view while the livetail goes on.
+	paused bool
+	// since is the next record of the buffer when the view stopped following, to count the new ones.
+	since uint64
+}
+
+// resetView forgets the records written to the view, whose text is replaced.
+func (u *UI) resetView() {
+	u.view.first, u.view.next, u.view.since = 0, 0, 0
+	u.view.selected = -1
+	u.Display.View.Highlight()
+	u.updateStatus()
+}
+
+// render appends the records not shown yet to the view, each in its own region named by its
+// sequence number, so that the record under the cursor can be highlighted. Nothing is written
+// while paused.
+func (u *UI) render() {
+	buffer := u.Livetail.Records
+	first, next := buffer.Bounds()
+	if next < u.view.next {
+		// The buffer was reset for a new livetail.
+		u.resetView()
+	}
+	if u.view.paused || next == u.view.next {
+		u.updateStatus()
+		return
+	}
+
+	// The view keeps the records dropped by the buffer until it holds a tenth more records than
+	// the buffer, and is then written again from the buffer, so that it stays bounded too.
+	rebuild := u.view.next-u.view.first >= livetail.BufferCapacity+livetail.BufferCapacity/10
+	if rebuild || u.view.next == u.view.first {
+		u.Display.View.Clear()
+		u.Display.View.SetTextAlign(tview.AlignLeft)
+		u.view.first, u.view.next = first, first
+		if u.view.selected >= 0 && uint64(u.view.selected) < first {
+			u.view.selected = -1
+		}
+	}
+
+	records, from := buffer.Since(u.view.next)
+	var lines strings.Builder
+	if from > u.view.next {
+		// Records were dropped from the buffer while paused, before they could be shown.
+		fmt.Fprintf(&lines, "%s… %d records dropped[-::-]\n", u.Palette.UI.Placeholder.Tag(), from-u.view.next)
+	}
+	for i, record := range records {
+		fmt.Fprintf(&lines, "[\"%d\"]%s[\"\"]\n", from+uint64(i), livetail.FormatRecord(record, u.Palette))
+	}
+	_, _ = u.Display.View.Write([]byte(lines.String()))
+	u.view.next = from + uint64(len(records))
+
+	switch {
+	case u.view.selected < 0:
+		u.Display.View.Highlight()
+		u.Display.View.ScrollToEnd()
+	case rebuild:
+		u.Display.View.Highlight(strconv.FormatInt(u.view.selected, 10)).ScrollToHighlight()
+	}
+	u.updateStatus()
+}
+
+// updateStatus shows the state of the livetail in the status bar, and whether the view is paused
+// and how many records arrived since it stopped following.
+func (u *UI) updateStatus() {
+	u.Display.Connection.SetText(" " + connectionStatus(u.Livetail.Status(), tview.Escape(u.filtersStatus()), u.Palette))
+
+	_, next := u.Livetail.Records.Bounds()
+
+	var status string
+	switch {
+	case u.view.paused:
+		status = fmt.Sprintf("[::r] PAUSED [::-] %d new records ", next-u.view.next)
+	case u.view.selected >= 0 && next > u.view.since:
+		status = fmt.Sprintf("%d new records ↓ ", next-u.view.since)
+	}
+	u.Display.Status.SetText(status)
+}
+
+// connectionStatus formats the state of a livetail session, its record rate and its filters,
+// followed by its last error, which may be cut by the width of the terminal.
+func connectionStatus(status livetail.Status, filters string, palette *theme.Palette) string {
+	var state string
+	switch status.State {
+	case livetail.StateConnecting:
+		state = palette.UI.Connecting.Tag() + "○ connecting[-::-]"
+	case livetail.StateConnected:
+		state = palette.UI.Connected.Tag() + "● connected[-::-]"
+	case livetail.StateReconnecting:
+		state = fmt.Sprintf("%s◌ reconnecting (%d)[-::-]", palette.UI.Connecting.Tag(), status.Reconnects)
+	case livetail.StateFailed:
+		state = palette.UI.Error.Tag() + "✕ failed[-::-]"
+	default:
+		return "■ stopped | " + filters
+	}
 
-	// Regular expression to match the operators
-	re := regexp.MustCompile(`(=|!=|:|!:|>|<|>=|<=)`)
+	line := fmt.Sprintf("%s | %.1f records/s | %s", state, status.Rate, filters)
+	if status.Err != nil && status.State != livetail.StateConnected {
+		line += " | " + palette.UI.Error.Tag() + tview.Escape(strings.SplitN(status.Err.Error(), "\n", 2)[0]) + "[-::-]"
+	}
+	return line
+}
 
-	// Split the filterValue using the regex
-	parts := re.Split(filterValue, -1)
-	if len(parts) < 2 {
-		return filterValue, "", "" // Only the field is present
+// filtersStatus lists the filters of the livetail as the commands that set them.
+func (u *UI) filtersStatus() string {
+	var active []string
+	if len(u.SourceFilter) > 0 {
+		var names []string
+		for _, id := range u.SourceFilter {
+			name := id
+			for _, source := range u.Display.SourceList {
+				if source.ID == id {
+					name = source.Name
+				}
+			}
+			names = append(names, name)
+		}
+		active = append(active, "source="+strings.Join(names, ","))
+	}
+	if !u.StartDateTimeFilter.IsZero() {
+		active = append(active, "start-date="+u.StartDateTimeFilter.Local().Format(time.RFC3339))
 	}
+	if !u.EndDateTimeFilter.IsZero() {
+		active = append(active, "end-date="+u.EndDateTimeFilter.Local().Format(time.RFC3339))
+	}
+	if !u.Filter.IsEmpty() {
+		active = append(active, "field-filter="+u.Filter.String())
+	}
+	if len(active) == 0 {
+		return "no filters"
+	}
+	return strings.Join(active, " ")
+}
 
-	// Extract the operator using regex
-	op := re.FindString(filterValue)
+// togglePause stops or resumes writing the records to the view. The records received while
+// paused are written on resume, as far as the buffer kept them.
+func (u *UI) togglePause() {
+	u.view.paused = !u.view.paused
+	if !u.view.paused {
+		u.render()
+	}
+	u.updateStatus()
+}
+
+// moveCursor moves the cursor by delta records. The cursor starts on the last record, and moving
+// it past the last record follows the new records again.
+func (u *UI) moveCursor(delta int) {
+	if u.view.next == u.view.first || u.view.selected < 0 && delta > 0 {
+		return
+	}
+	if u.view.selected < 0 {
+		u.view.selected = int64(u.view.next)
+		_, u.view.since = u.Livetail.Records.Bounds()
+	}
+
+	u.view.selected += int64(delta)
+	if u.view.selected < int64(u.view.first) {
+		u.view.selected = int64(u.view.first)
+	}
+	if u.view.selected >= int64(u.view.next) {
+		u.clearCursor()
+		return
+	}
+	u.Display.View.Highlight(strconv.FormatInt(u.view.selected, 10)).ScrollToHighlight()
+	u.updateStatus()
+}
+
+// clearCursor removes the cursor and follows the new records.
+func (u *UI) clearCursor() {
+	u.view.selected = -1
+	u.Display.View.Highlight()
+	u.Display.View.ScrollToEnd()
+	u.updateStatus()
+}
+
+// inspect opens the record under the cursor in the inspector.
+func (u *UI) inspect() {
+	if u.view.selected < 0 {
+		return
+	}
+	record, ok := u.Livetail.Records.Get(uint64(u.view.selected))
+	if !ok {
+		u.showError(errors.New("the record was dropped from the buffer"))
+		return
+	}
+	u.Display.OpenInspector(record)
+	u.app.SetFocus(u.Display.Inspector)
+}
 
-	return parts[0], op, parts[1]
+func (u *UI) closeInspector() {
+	u.Display.CloseInspector()
+	u.app.SetFocus(u.Display.input)
+}
+
+// addFilterTerm prepares a field-filter command adding term to the current filter, to be run
+// with Enter.
+func (u *UI) addFilterTerm(term string) {
+	u.closeInspector()
+	if !u.Display.Livetail {
+		u.showError(fmt.Errorf("field filters apply to livetail, switch to it with %s", strings.TrimSuffix(u.Keymap.label(CommandLivetail), ".")))
+		return
+	}
+
+	expression := term
+	if !u.Filter.IsEmpty() {
+		expression = u.Filter.String() + " " + term
+	}
+	u.Display.input.SetText("field-filter=" + expression)
 }
 
 var sourceNamesList []string
@@ -165,11 +358,41 @@ var sourceIds []string
 
 func (u *UI) runQuitCmd() {
 	StopLivetail(u, livetailStatus)
+	u.cancel()
 	u.app.Stop()
 }
 
 func (u *UI) SetDisplayCapture() {
 	u.Display.input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		// With an empty input, the keys of the keymap move the cursor over the records and pause them.
+		if u.Display.input.GetText() == "" {
+			switch u.Keymap.Action(event) {
+			case ActionUp:
+				u.moveCursor(-1)
+				return nil
+			case ActionDown:
+				u.moveCursor(1)
+				return nil
+			case ActionPageUp:
+				u.moveCursor(-10)
+				return nil
+			case ActionPageDown:
+				u.moveCursor(10)
+				return nil
+			case ActionPause:
+				u.togglePause()
+				return nil
+			case ActionFollow:
+				u.clearCursor()
+				return nil
+			case ActionInspect:
+				if u.view.selected >= 0 {
+					u.inspect()
+					return nil
+				}
+			}
+		}
+
 		switch event.Key() {
 		case tcell.KeyEnter:
 			input := u.Display.input.GetText()
@@ -193,40 +416,48 @@ func (u *UI) SetDisplayCapture() {
 
 						u.SourceFilter = sourceIds
 
-						time.Sleep(500 * time.Millisecond)
-
 						RunLivetail(u, livetailStatus)
 
 					} else if strings.Split(input, "=")[0] == "start-date" {
-						StopLivetail(u, livetailStatus)
+						date, err := filters.ParseTime(strings.SplitN(input, "=", 2)[1], time.Now())
+						if err != nil {
+							u.Display.input.SetText(input)
+							u.showError(err)
+							break
+						}
 
-						u.StartDateTimeFilter = filters.ShortDateTimeToGoDate(strings.Split(input, "=")[1])
-						u.StartDateUnParsed = strings.Split(input, "=")[1]
+						StopLivetail(u, livetailStatus)
 
-						time.Sleep(500 * time.Millisecond)
+						u.StartDateTimeFilter = date
 
 						RunLivetail(u, livetailStatus)
 
 					} else if strings.Split(input, "=")[0] == "end-date" {
-						StopLivetail(u, livetailStatus)
+						date, err := filters.ParseTime(strings.SplitN(input, "=", 2)[1], time.Now())
+						if err != nil {
+							u.Display.input.SetText(input)
+							u.showError(err)
+							break
+						}
 
-						u.EndDateTimeFilter = filters.ShortDateTimeToGoDate(strings.Split(input, "=")[1])
-						u.EndDateUnParsed = strings.Split(input, "=")[1]
+						StopLivetail(u, livetailStatus)
 
-						time.Sleep(500 * time.Millisecond)
+						u.EndDateTimeFilter = date
 
 						RunLivetail(u, livetailStatus)
 
 					} else if strings.Split(input, "=")[0] == "field-filter" {
-						StopLivetail(u, livetailStatus)
-
-						field, operator, value := splitFieldFilterValue(input)
+						query, err := filters.ParseQuery(strings.SplitN(input, "=", 2)[1])
+						if err != nil {
+							// Keep the expression so that it can be corrected
+							u.Display.input.SetText(input)
+							u.showError(err)
+							break
+						}
 
-						u.FieldBasedFilterName = field
-						u.FieldBasedFilterCondition = operator
-						u.FieldBasedFilterValue = value
+						StopLivetail(u, livetailStatus)
 
-						time.Sleep(500 * time.Millisecond)
+						u.Filter = query
 
 						RunLivetail(u, livetailStatus)
 
@@ -243,14 +474,14 @@ func (u *UI) SetDisplayCapture() {
 							}
 						}
 
-						err := APICalls.CreateView(u.Config.Get().Token, u.Config.Get().EndPoint, u.Config.Get().TeamId, selectedSource, []string{}, u.FieldBasedFilterName, u.FieldBasedFilterValue, u.FieldBasedFilterCondition, u.StartDateUnParsed, u.EndDateUnParsed, name)
+						err := api.NewClientFromConfig(nil, u.Config).CreateView(u.Config.Get().TeamId, selectedSource, u.Filter, u.StartDateTimeFilter, u.EndDateTimeFilter, name)
 						if err != nil {
-							u.Display.input.SetFieldTextColor(tcell.ColorRed)
+							u.Display.input.SetFieldTextColor(u.Palette.UI.Error.TCell())
 							input = "Failed to create view"
 
 							go func() {
 								time.Sleep(1000 * time.Millisecond)
-								u.Display.input.SetFieldTextColor(tcell.ColorWhite)
+								u.Display.input.SetFieldTextColor(u.Palette.UI.Text.TCell())
 								input = ""
 							}()
 						}
@@ -262,6 +493,13 @@ func (u *UI) SetDisplayCapture() {
 
 						for _, view := range u.Display.ViewsList {
 							if view.Name == name {
+								// A view whose filters cannot be read is not started without them.
+								query, err := api.ViewQuery(view)
+								if err != nil {
+									u.showError(err)
+									return nil
+								}
+
 								u.StartDateTimeFilter = view.DateFilter.StartDate
 								u.EndDateTimeFilter = view.DateFilter.EndDate
 
@@ -270,41 +508,33 @@ func (u *UI) SetDisplayCapture() {
 										u.SourceFilter = append(u.SourceFilter, source.ID)
 									}
 								}
-
-								u.SearchFilter = view.TextFilter
-								u.FieldBasedFilterName = view.SearchFilter[0].Key
-								u.FieldBasedFilterValue = view.SearchFilter[0].Value
-								u.FieldBasedFilterCondition = view.SearchFilter[0].Condition
+								u.Filter = query
 							}
 						}
 
-						if livetailStatus.LivetailEnabled {
-							StopLivetail(u, livetailStatus)
-
-							time.Sleep(200 * time.Millisecond)
-						}
-
+						StopLivetail(u, livetailStatus)
 						RunLivetail(u, livetailStatus)
 					}
 				}
 				return nil
 			}
 
+			command := u.Keymap.Command(input, u.Display.Livetail)
+			if input == "start" || input == "stop" {
+				command = input
+			}
+
 			switch u.Display.Livetail {
 			case true:
-				switch input {
+				switch command {
 				case "start":
 					RunLivetail(u, livetailStatus)
 				case "stop":
 					StopLivetail(u, livetailStatus)
-				case "q":
-					u.runQuitCmd()
-				case "quit":
+				case CommandQuit:
 					u.runQuitCmd()
-				case "exit":
-					u.runQuitCmd()
-				case "1":
-				case "2":
+				case CommandLivetail:
+				case CommandView:
 					StopLivetail(u, livetailStatus)
 
 					ResetFilters(u)
@@ -316,73 +546,45 @@ func (u *UI) SetDisplayCapture() {
 					u.Display.input.Autocomplete()
 
 					u.Display.Livetail = false
-					u.Display.TopHelp.SetPlaceholder("  Stream > View | 1. livetail 2. view 9.QUIT [q | quit | exit]")
-					u.Display.BottomHelp.SetPlaceholder("  3.view [view=view-name]")
-				case "3":
+					u.showHelp()
+				case CommandSource:
 					u.Display.input.SetText("source=")
 					u.Display.input.Autocomplete()
-				case "4":
+				case CommandStartDate:
 					u.Display.input.SetText("start-date=")
-				case "5":
+				case CommandEndDate:
 					u.Display.input.SetText("end-date=")
-				case "6":
+				case CommandFieldFilter:
 					u.Display.input.SetText("field-filter=")
 					u.Display.input.Autocomplete()
-				case "7":
+				case CommandSaveView:
 					u.Display.input.SetText("save-view=")
-				case "9":
-					u.runQuitCmd()
 				default:
-					u.Display.BottomHelp.SetPlaceholder("  Invalid command").SetPlaceholderTextColor(tcell.ColorRed)
-
-					go func() {
-						time.Sleep(200 * time.Millisecond)
-
-						u.Display.BottomHelp.SetPlaceholder("  3.source [source=source-name,source-name,source-name...] 4.start-date [start-date=now-2d] 5.end-date [end-date=now] 6.field-filter [field-filter=level=info] 7.save-view [save-view=name] 8.QUIT [q | quit | exit]").
-							SetPlaceholderTextColor(tcell.ColorGray)
-					}()
+					u.showInvalidCommand()
 				}
 			case false:
-				switch input {
+				switch command {
 				case "start":
 					RunLivetail(u, livetailStatus)
 				case "stop":
 					StopLivetail(u, livetailStatus)
-				case "q":
+				case CommandQuit:
 					u.runQuitCmd()
-				case "quit":
-					u.runQuitCmd()
-				case "exit":
-					u.runQuitCmd()
-				case "1":
+				case CommandLivetail:
 					StopLivetail(u, livetailStatus)
 
 					ResetFilters(u)
 
-					time.Sleep(200 * time.Millisecond)
-
 					RunLivetail(u, livetailStatus)
 
 					u.Display.Livetail = true
-					u.Display.TopHelp.SetPlaceholder("  Stream > Livetail | 1. livetail 2. view 9.QUIT [q | quit | exit]").
-						SetPlaceholderTextColor(tcell.ColorGray)
-					u.Display.BottomHelp.SetPlaceholder("  3.source [source=source-name,source-name,source-name...] 4.start-date [start-date=now-2d] 5.end-date [end-date=now] 6.field-filter [field-filter=level=info] 7.save-view [save-view=name]").
-						SetPlaceholderTextColor(tcell.ColorGray)
-				case "2":
-				case "3":
+					u.showHelp()
+				case CommandView:
+				case CommandSelectView:
 					u.Display.input.SetText("view=")
 					u.Display.input.Autocomplete()
-				case "9":
-					u.runQuitCmd()
 				default:
-					u.Display.BottomHelp.SetPlaceholder("  Invalid command").SetPlaceholderTextColor(tcell.ColorRed)
-
-					go func() {
-						time.Sleep(200 * time.Millisecond)
-
-						u.Display.BottomHelp.SetPlaceholder("  3.view [view=view-name] 4.QUIT [q | quit | exit]").
-							SetPlaceholderTextColor(tcell.ColorGray)
-					}()
+					u.showInvalidCommand()
 				}
 			}
 
@@ -392,6 +594,36 @@ func (u *UI) SetDisplayCapture() {
 	})
 }
 
+// showHelp shows the help of the current mode, with the keys and commands of the keymap.
+func (u *UI) showHelp() {
+	placeholder := u.Palette.UI.Placeholder.TCell()
+	u.Display.TopHelp.SetPlaceholder(u.Keymap.topHelp(u.Display.Livetail)).SetPlaceholderTextColor(placeholder)
+	u.Display.BottomHelp.SetPlaceholder(u.Keymap.bottomHelp(u.Display.Livetail)).SetPlaceholderTextColor(placeholder)
+}
+
+// showInvalidCommand flashes "Invalid command" in place of the help.
+func (u *UI) showInvalidCommand() {
+	u.Display.BottomHelp.SetPlaceholder("  Invalid command").SetPlaceholderTextColor(u.Palette.UI.Error.TCell())
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+
+		u.app.QueueUpdateDraw(u.showHelp)
+	}()
+}
+
+// showError shows the first line of err in place of the help for a few seconds.
+func (u *UI) showError(err error) {
+	message := strings.SplitN(err.Error(), "\n", 2)[0]
+	u.Display.BottomHelp.SetPlaceholder("  " + message).SetPlaceholderTextColor(u.Palette.UI.Error.TCell())
+
+	go func() {
+		time.Sleep(3 * time.Second)
+
+		u.app.QueueUpdateDraw(u.showHelp)
+	}()
+}
+
 var mu sync.Mutex
 
 func RunLivetail(u *UI, livetailStatus *LivetailStatus) {
@@ -402,15 +634,25 @@ func RunLivetail(u *UI, livetailStatus *LivetailStatus) {
 		livetailStatus.LivetailEnabled = true
 
 		u.logs = ""
-		u.Livetail.Logs = ""
+		u.Livetail.Records.Reset()
 		u.Display.View.SetText(u.logs)
+		u.view.paused = false
+		u.resetView()
+		if u.Display.InspectorOpen() {
+			u.closeInspector()
+		}
 
 		u.Display.View.SetTextAlign(tview.AlignLeft)
 
-		u.Livetail.ApplyFilter(u.Config, u.SourceFilter, u.StartDateTimeFilter, u.EndDateTimeFilter, u.FieldBasedFilterName, u.FieldBasedFilterValue, u.FieldBasedFilterCondition)
-		go u.Livetail.GenerateLogs(u.Ctx, u.Config)
-		go display(u, u.Livetail)
+		err := u.Livetail.Start(u.Ctx, u.Config, u.SourceFilter, u.StartDateTimeFilter, u.EndDateTimeFilter, u.Filter)
+		if err != nil {
+			livetailStatus.LivetailEnabled = false
+			u.showError(err)
+		} else {
+			u.refreshVolume()
+		}
 		u.Display.View.ScrollToEnd()
+		u.updateStatus()
 	}
 }
 
@@ -420,8 +662,8 @@ func StopLivetail(u *UI, livetailStatus *LivetailStatus) {
 
 	if livetailStatus.LivetailEnabled {
 		livetailStatus.LivetailEnabled = false
-		_, cancel := context.WithCancel(u.Ctx)
-		defer cancel()
+		u.Livetail.Stop()
+		u.updateStatus()
 	}
 }
 
@@ -429,9 +671,7 @@ func ResetFilters(u *UI) {
 	u.SourceFilter = nil
 	u.StartDateTimeFilter = time.Time{}
 	u.EndDateTimeFilter = time.Time{}
-	u.FieldBasedFilterName = ""
-	u.FieldBasedFilterValue = ""
-	u.FieldBasedFilterCondition = ""
+	u.Filter = nil
 }
 
 func (u *UI) Run() error {
@@ -0,0 +1,50 @@
+package gui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/logfire-sh/cli/internal/theme"
+	"github.com/logfire-sh/cli/livetail"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnectionStatus(t *testing.T) {
+	palette, _ := theme.Builtin(theme.Dark)
+
+	tests := []struct {
+		name   string
+		status livetail.Status
+		want   string
+	}{
+		{
+			name:   "connected",
+			status: livetail.Status{State: livetail.StateConnected, Rate: 2.5},
+			want:   "[green::-]● connected[-::-] | 2.5 records/s | no filters",
+		},
+		{
+			name: "reconnecting",
+			status: livetail.Status{
+				State:      livetail.StateReconnecting,
+				Reconnects: 3,
+				Err:        errors.New("connection refused\ndetails"),
+			},
+			want: "[yellow::-]◌ reconnecting (3)[-::-] | 0.0 records/s | no filters | [red::-]connection refused[-::-]",
+		},
+		{
+			name:   "stopped",
+			status: livetail.Status{State: livetail.StateStopped},
+			want:   "■ stopped | no filters",
+		},
+		{
+			name:   "failed",
+			status: livetail.Status{State: livetail.StateFailed, Err: errors.New("permission denied")},
+			want:   "[red::-]✕ failed[-::-] | 0.0 records/s | no filters | [red::-]permission denied[-::-]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, connectionStatus(tt.status, "no filters", palette))
+		})
+	}
+}
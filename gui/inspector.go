@@ -0,0 +1,132 @@
+package gui
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/logfire-sh/cli/internal/theme"
+	"github.com/logfire-sh/cli/pkg/cmdutil/filters"
+	"github.com/logfire-sh/cli/pkg/cmdutil/ingest"
+	pb "github.com/logfire-sh/cli/services/flink-service"
+	"github.com/rivo/tview"
+)
+
+// Inspector is the side pane showing the record under the cursor: its source, offset and level,
+// the whole message pretty-printed, and its fields as filter terms that can be picked.
+type Inspector struct {
+	*tview.Flex
+	details *tview.TextView
+	fields  *tview.List
+
+	palette *theme.Palette
+	terms   []string
+}
+
+// NewInspector creates the pane. pick is called with the filter term of the field selected with
+// Enter, and done when the pane is left with Escape.
+func NewInspector(palette *theme.Palette, background tcell.Color, pick func(term string), done func()) *Inspector {
+	details := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWordWrap(true)
+	details.SetBackgroundColor(background)
+
+	fields := tview.NewList().
+		ShowSecondaryText(false).
+		SetHighlightFullLine(true)
+	fields.SetBackgroundColor(background)
+	fields.SetBorder(true).SetTitle(" Enter: add to field-filter | Esc: close ").SetBackgroundColor(background)
+
+	inspector := &Inspector{
+		Flex:    tview.NewFlex().SetDirection(tview.FlexRow),
+		details: details,
+		fields:  fields,
+		palette: palette,
+	}
+	inspector.AddItem(details, 0, 2, false).AddItem(fields, 0, 1, true)
+	inspector.SetBorder(true).SetTitle(" Record ").SetBackgroundColor(background)
+
+	fields.SetSelectedFunc(func(index int, _ string, _ string, _ rune) {
+		if index < len(inspector.terms) {
+			pick(inspector.terms[index])
+		}
+	})
+	fields.SetDoneFunc(done)
+	return inspector
+}
+
+// Show replaces the record of the pane.
+func (i *Inspector) Show(record *pb.FilteredRecord) {
+	label, text := i.palette.UI.Label.Tag(), i.palette.UI.Text.Tag()
+
+	var b strings.Builder
+	for _, line := range [][2]string{
+		{"Time", record.Dt},
+		{"Source", record.SourceName},
+		{"Source ID", record.SourceID},
+		{"Offset", fmt.Sprint(record.Offset)},
+		{"Level", record.Level},
+	} {
+		fmt.Fprintf(&b, "%s%-10s%s%s\n", label, line[0], text, tview.Escape(line[1]))
+	}
+	fmt.Fprintf(&b, "\n%sMessage%s\n%s\n", label, text, tview.Escape(prettyMessage(record.Message)))
+	i.details.SetText(b.String()).ScrollToBeginning()
+
+	i.terms = fieldTerms(record)
+	i.fields.Clear()
+	for _, term := range i.terms {
+		i.fields.AddItem(tview.Escape(term), "", 0, nil)
+	}
+}
+
+// prettyMessage indents a JSON message, and keeps any other message as it is.
+func prettyMessage(message string) string {
+	var value interface{}
+	if err := json.Unmarshal([]byte(message), &value); err != nil {
+		return message
+	}
+	if _, ok := value.(map[string]interface{}); !ok {
+		return message
+	}
+	b, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return message
+	}
+	return string(b)
+}
+
+// fieldTerms returns the filter terms matching the level and every field of a JSON or logfmt
+// message, nested fields named by their dotted path, in name order after the level.
+func fieldTerms(record *pb.FilteredRecord) []string {
+	var terms []string
+	if record.Level != "" {
+		terms = append(terms, filters.FieldTerm("level", record.Level))
+	}
+
+	var fieldTerms []string
+	var walk func(prefix string, fields map[string]interface{})
+	walk = func(prefix string, fields map[string]interface{}) {
+		for key, value := range fields {
+			name := prefix + key
+			if name == "level" && record.Level != "" {
+				continue
+			}
+			switch v := value.(type) {
+			case map[string]interface{}:
+				walk(name+".", v)
+			case []interface{}, nil:
+				// Lists and nulls cannot be compared by a field filter.
+			case string:
+				fieldTerms = append(fieldTerms, filters.FieldTerm(name, v))
+			default:
+				b, _ := json.Marshal(v)
+				fieldTerms = append(fieldTerms, filters.FieldTerm(name, string(b)))
+			}
+		}
+	}
+	walk("", ingest.ParseFields(record.Message))
+	sort.Strings(fieldTerms)
+	return append(terms, fieldTerms...)
+}
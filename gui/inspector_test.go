@@ -0,0 +1,32 @@
+package gui
+
+import (
+	"testing"
+
+	pb "github.com/logfire-sh/cli/services/flink-service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldTerms(t *testing.T) {
+	record := &pb.FilteredRecord{
+		Level:   "error",
+		Message: `{"level":"error","msg":"payment failed","user":{"id":42,"name":"Ada L"},"retry":true,"tags":["a"],"trace":null}`,
+	}
+	assert.Equal(t, []string{
+		"level=error",
+		`msg="payment failed"`,
+		"retry=true",
+		"user.id=42",
+		`user.name="Ada L"`,
+	}, fieldTerms(record))
+
+	assert.Equal(t, []string{"level=info", "path=/health", "status=200"},
+		fieldTerms(&pb.FilteredRecord{Level: "info", Message: "status=200 path=/health"}))
+	assert.Empty(t, fieldTerms(&pb.FilteredRecord{Message: "plain text"}))
+}
+
+func TestPrettyMessage(t *testing.T) {
+	assert.Equal(t, "{\n  \"a\": 1,\n  \"b\": {\n    \"c\": \"d\"\n  }\n}", prettyMessage(`{"b":{"c":"d"},"a":1}`))
+	assert.Equal(t, "[1,2]", prettyMessage("[1,2]"))
+	assert.Equal(t, "not json", prettyMessage("not json"))
+}
@@ -0,0 +1,282 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Actions of the keys, which act on the records while the input is empty.
+const (
+	ActionUp       = "up"
+	ActionDown     = "down"
+	ActionPageUp   = "page-up"
+	ActionPageDown = "page-down"
+	// ActionFollow removes the cursor and follows the new records.
+	ActionFollow  = "follow"
+	ActionInspect = "inspect"
+	ActionPause   = "pause"
+)
+
+// Commands typed in the input and run with Enter.
+const (
+	CommandLivetail    = "livetail"
+	CommandView        = "view"
+	CommandSource      = "source"
+	CommandStartDate   = "start-date"
+	CommandEndDate     = "end-date"
+	CommandFieldFilter = "field-filter"
+	CommandSaveView    = "save-view"
+	// CommandSelectView lists the views to pick one, in the view mode.
+	CommandSelectView = "select-view"
+	CommandQuit       = "quit"
+)
+
+// livetailCommands and viewCommands are the commands of each mode of the GUI, whose words must
+// differ within a mode.
+var (
+	livetailCommands = []string{CommandLivetail, CommandView, CommandSource, CommandStartDate, CommandEndDate, CommandFieldFilter, CommandSaveView, CommandQuit}
+	viewCommands     = []string{CommandLivetail, CommandView, CommandSelectView, CommandQuit}
+)
+
+// Keymap binds the keys and the commands of the GUI to their actions. Keys are named like
+// tcell names them, such as Up, PgDn, Esc or Ctrl-U, or are a single character; Space is the
+// space bar. A key of a single character can no longer start a command typed in the input.
+type Keymap struct {
+	Keys     map[string][]string `yaml:"keys"`
+	Commands map[string][]string `yaml:"commands"`
+
+	actions map[keyStroke]string
+}
+
+type keyStroke struct {
+	key tcell.Key
+	r   rune
+}
+
+// DefaultKeymap returns the keys and commands of the GUI without a keymap file.
+func DefaultKeymap() *Keymap {
+	k := &Keymap{
+		Keys: map[string][]string{
+			ActionUp:       {"Up"},
+			ActionDown:     {"Down"},
+			ActionPageUp:   {"PgUp"},
+			ActionPageDown: {"PgDn"},
+			ActionFollow:   {"End", "Esc"},
+			ActionInspect:  {"Enter"},
+			ActionPause:    {"Space"},
+		},
+		Commands: map[string][]string{
+			CommandLivetail:    {"1"},
+			CommandView:        {"2"},
+			CommandSource:      {"3"},
+			CommandStartDate:   {"4"},
+			CommandEndDate:     {"5"},
+			CommandFieldFilter: {"6"},
+			CommandSaveView:    {"7"},
+			CommandSelectView:  {"3"},
+			CommandQuit:        {"9", "q", "quit", "exit"},
+		},
+	}
+	_ = k.compile()
+	return k
+}
+
+// KeymapPath is $LOGFIRE_KEYMAP, then ~/.logfire-keymap.yml.
+func KeymapPath() string {
+	if path := os.Getenv("LOGFIRE_KEYMAP"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".logfire-keymap.yml")
+}
+
+// LoadKeymap returns the default keymap changed by the keymap file at path, when it exists.
+// Every action or command listed by the file replaces all of its default keys or words.
+func LoadKeymap(path string) (*Keymap, error) {
+	k := DefaultKeymap()
+	if path == "" {
+		return k, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return k, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file Keymap
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for action, keys := range file.Keys {
+		if _, ok := k.Keys[action]; !ok {
+			return nil, fmt.Errorf("invalid keymap %s: unknown action %q, expected one of: %s", path, action, strings.Join(sortedKeys(k.Keys), ", "))
+		}
+		k.Keys[action] = keys
+	}
+	for command, words := range file.Commands {
+		if _, ok := k.Commands[command]; !ok {
+			return nil, fmt.Errorf("invalid keymap %s: unknown command %q, expected one of: %s", path, command, strings.Join(sortedKeys(k.Commands), ", "))
+		}
+		k.Commands[command] = words
+	}
+
+	if err := k.compile(); err != nil {
+		return nil, fmt.Errorf("invalid keymap %s: %w", path, err)
+	}
+	return k, nil
+}
+
+// compile parses the keys, and checks that no key and no word of a mode has two meanings.
+func (k *Keymap) compile() error {
+	names := map[string]tcell.Key{}
+	for key, name := range tcell.KeyNames {
+		names[strings.ToLower(name)] = key
+	}
+
+	k.actions = map[keyStroke]string{}
+	for _, action := range sortedKeys(k.Keys) {
+		for _, name := range k.Keys[action] {
+			stroke, err := parseKey(names, name)
+			if err != nil {
+				return fmt.Errorf("%s: %w", action, err)
+			}
+			if other, ok := k.actions[stroke]; ok {
+				return fmt.Errorf("key %s is bound to both %s and %s", name, other, action)
+			}
+			k.actions[stroke] = action
+		}
+	}
+
+	for _, commands := range [][]string{livetailCommands, viewCommands} {
+		seen := map[string]string{}
+		for _, command := range commands {
+			for _, word := range k.Commands[command] {
+				if word == "" || strings.ContainsAny(word, "= \t") {
+					return fmt.Errorf("%s: invalid command %q, commands cannot be empty or contain spaces or =", command, word)
+				}
+				if other, ok := seen[word]; ok {
+					return fmt.Errorf("command %q is bound to both %s and %s", word, other, command)
+				}
+				seen[word] = command
+			}
+		}
+	}
+	return nil
+}
+
+func parseKey(names map[string]tcell.Key, name string) (keyStroke, error) {
+	if strings.EqualFold(name, "space") {
+		return keyStroke{key: tcell.KeyRune, r: ' '}, nil
+	}
+	if utf8.RuneCountInString(name) == 1 {
+		r, _ := utf8.DecodeRuneInString(name)
+		return keyStroke{key: tcell.KeyRune, r: r}, nil
+	}
+	if key, ok := names[strings.ToLower(name)]; ok {
+		return keyStroke{key: key}, nil
+	}
+	return keyStroke{}, fmt.Errorf("unknown key %q, expected a name such as Up, PgDn, Esc or Ctrl-U, or a single character", name)
+}
+
+// Action returns the action bound to a key, or "" for none.
+func (k *Keymap) Action(event *tcell.EventKey) string {
+	stroke := keyStroke{key: event.Key()}
+	if stroke.key == tcell.KeyRune {
+		stroke.r = event.Rune()
+	}
+	return k.actions[stroke]
+}
+
+// Command returns the command of a word typed in the input for a mode of the GUI, or "" for none.
+func (k *Keymap) Command(word string, livetail bool) string {
+	commands := viewCommands
+	if livetail {
+		commands = livetailCommands
+	}
+	for _, command := range commands {
+		for _, w := range k.Commands[command] {
+			if w == word {
+				return command
+			}
+		}
+	}
+	return ""
+}
+
+// label returns the first word of a command followed by a dot, as shown by the help.
+func (k *Keymap) label(command string) string {
+	if words := k.Commands[command]; len(words) > 0 {
+		return words[0] + "."
+	}
+	return ""
+}
+
+// keyLabel returns the first key of an action, as shown by the help.
+func (k *Keymap) keyLabel(action string) string {
+	keys := k.Keys[action]
+	if len(keys) == 0 {
+		return "-"
+	}
+	switch keys[0] {
+	case "Up":
+		return "↑"
+	case "Down":
+		return "↓"
+	}
+	return keys[0]
+}
+
+// topHelp lists the modes, the commands to quit and the keys of the records.
+func (k *Keymap) topHelp(livetail bool) string {
+	mode := "View"
+	if livetail {
+		mode = "Livetail"
+	}
+	quit := k.label(CommandQuit) + "QUIT"
+	if words := k.Commands[CommandQuit]; len(words) > 1 {
+		quit += " [" + strings.Join(words[1:], " | ") + "]"
+	}
+	cursor := k.keyLabel(ActionUp) + k.keyLabel(ActionDown)
+	if cursor != "↑↓" {
+		cursor = k.keyLabel(ActionUp) + "/" + k.keyLabel(ActionDown)
+	}
+	return fmt.Sprintf("  Stream > %s | %s livetail %s view %s | %s select a record, %s to inspect it, %s to pause",
+		mode, k.label(CommandLivetail), k.label(CommandView), quit, cursor, k.keyLabel(ActionInspect), k.keyLabel(ActionPause))
+}
+
+// bottomHelp lists the commands of a mode with an example each.
+func (k *Keymap) bottomHelp(livetail bool) string {
+	if !livetail {
+		return "  " + k.label(CommandSelectView) + "view [view=view-name]"
+	}
+	return "  " + strings.Join([]string{
+		k.label(CommandSource) + "source [source=source-name,source-name,source-name...]",
+		k.label(CommandStartDate) + "start-date [start-date=now-2d]",
+		k.label(CommandEndDate) + "end-date [end-date=now]",
+		k.label(CommandFieldFilter) + "field-filter [field-filter=level=error status>=500 -path:/health]",
+		k.label(CommandSaveView) + "save-view [save-view=name]",
+	}, " ")
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,84 @@
+package gui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeKeymap(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keymap.yml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestDefaultKeymap(t *testing.T) {
+	k := DefaultKeymap()
+
+	assert.Equal(t, "  Stream > Livetail | 1. livetail 2. view 9.QUIT [q | quit | exit] | ↑↓ select a record, Enter to inspect it, Space to pause", k.topHelp(true))
+	assert.Equal(t, "  3.source [source=source-name,source-name,source-name...] 4.start-date [start-date=now-2d] 5.end-date [end-date=now] 6.field-filter [field-filter=level=error status>=500 -path:/health] 7.save-view [save-view=name]", k.bottomHelp(true))
+	assert.Equal(t, "  3.view [view=view-name]", k.bottomHelp(false))
+
+	assert.Equal(t, ActionFollow, k.Action(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone)))
+	assert.Equal(t, ActionPause, k.Action(tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModNone)))
+	assert.Equal(t, "", k.Action(tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModNone)))
+
+	assert.Equal(t, CommandSource, k.Command("3", true))
+	assert.Equal(t, CommandSelectView, k.Command("3", false))
+	assert.Equal(t, "", k.Command("7", false))
+	assert.Equal(t, CommandQuit, k.Command("exit", false))
+}
+
+func TestLoadKeymap(t *testing.T) {
+	k, err := LoadKeymap(writeKeymap(t, `
+keys:
+  up: [k, Up]
+  down: [j]
+  pause: [p]
+commands:
+  source: [s, src]
+  quit: [q]
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, ActionUp, k.Action(tcell.NewEventKey(tcell.KeyRune, 'k', tcell.ModNone)))
+	assert.Equal(t, ActionUp, k.Action(tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)))
+	assert.Equal(t, "", k.Action(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)))
+	assert.Equal(t, "", k.Action(tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModNone)))
+	assert.Equal(t, ActionPageUp, k.Action(tcell.NewEventKey(tcell.KeyPgUp, 0, tcell.ModNone)))
+
+	assert.Equal(t, CommandSource, k.Command("src", true))
+	assert.Equal(t, "", k.Command("3", true))
+	assert.Equal(t, "", k.Command("exit", true))
+	assert.Equal(t, "  Stream > Livetail | 1. livetail 2. view q.QUIT | k/j select a record, Enter to inspect it, p to pause", k.topHelp(true))
+
+	k, err = LoadKeymap(filepath.Join(t.TempDir(), "missing.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, DefaultKeymap().topHelp(false), k.topHelp(false))
+}
+
+func TestLoadKeymapInvalid(t *testing.T) {
+	tests := []struct {
+		content string
+		wantErr string
+	}{
+		{"keys:\n  jump: [g]\n", `unknown action "jump", expected one of: down, follow, inspect, page-down, page-up, pause, up`},
+		{"commands:\n  tail: [t]\n", `unknown command "tail"`},
+		{"keys:\n  up: [Upp]\n", `up: unknown key "Upp"`},
+		{"keys:\n  pause: [Enter]\n", `key Enter is bound to both inspect and pause`},
+		{"commands:\n  source: [\"1\"]\n", `command "1" is bound to both livetail and source`},
+		{"commands:\n  view: [\"a b\"]\n", `view: invalid command "a b"`},
+		{"keys: [up]\n", `failed to parse`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.content, func(t *testing.T) {
+			_, err := LoadKeymap(writeKeymap(t, tt.content))
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
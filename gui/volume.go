@@ -0,0 +1,114 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/logfire-sh/cli/internal/theme"
+	"github.com/logfire-sh/cli/pkg/cmdutil/grpcutil"
+	"github.com/logfire-sh/cli/pkg/cmdutil/logformat"
+	pb "github.com/logfire-sh/cli/services/flink-service"
+	"github.com/rivo/tview"
+)
+
+// volumeInterval is how often the volume line is read again while the filters stay the same.
+const volumeInterval = 30 * time.Second
+
+// watchVolume keeps the volume line up to date until the UI quits, reading it again every
+// volumeInterval and whenever a livetail starts.
+func (u *UI) watchVolume() {
+	ticker := time.NewTicker(volumeInterval)
+	defer ticker.Stop()
+
+	for {
+		buckets, err := u.Livetail.Volume(u.Ctx, u.Config)
+		if u.Ctx.Err() != nil {
+			return
+		}
+		// The sparkline is experimental: a server sending the bar graph in another format
+		// leaves the line empty rather than showing the same error for the whole session.
+		if errors.Is(err, grpcutil.ErrGraphFormat) {
+			u.app.QueueUpdateDraw(func() {
+				u.Display.Volume.SetText("")
+			})
+			return
+		}
+		// A stopped livetail keeps the volume of its last session.
+		if buckets != nil || err != nil {
+			line := volumeLine(buckets, err, u.Palette)
+			u.app.QueueUpdateDraw(func() {
+				u.Display.Volume.SetText(line)
+			})
+		}
+
+		select {
+		case <-u.Ctx.Done():
+			return
+		case <-ticker.C:
+		case <-u.volumeRefresh:
+		}
+	}
+}
+
+// refreshVolume reads the volume line again, once the running livetail changed.
+func (u *UI) refreshVolume() {
+	select {
+	case u.volumeRefresh <- struct{}{}:
+	default:
+	}
+}
+
+// volumeLine draws buckets as a sparkline, in the colour of errors where a bucket holds errors,
+// followed by the number of records and the busiest bucket.
+func volumeLine(buckets []grpcutil.GraphBucket, err error, palette *theme.Palette) string {
+	text, failed, gray := palette.UI.Text.Tag(), palette.Level("error").Tag(), palette.UI.Placeholder.Tag()
+	if err != nil {
+		return " " + gray + "volume unavailable: " + tview.Escape(strings.SplitN(err.Error(), "\n", 2)[0]) + "[-::-]"
+	}
+	if len(buckets) == 0 {
+		return " " + gray + "volume: no buckets[-::-]"
+	}
+
+	counts := make([]int, len(buckets))
+	total, max := 0, 0
+	for i, bucket := range buckets {
+		counts[i] = bucket.Count
+		total += bucket.Count
+		if bucket.Count > max {
+			max = bucket.Count
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(" " + gray + "volume " + buckets[0].Dt.Local().Format("15:04") + "[-::-] ")
+	for i, spark := range strings.Split(logformat.Sparkline(counts, false), "") {
+		if hasErrors(buckets[i]) {
+			b.WriteString(failed + spark + "[-::-]")
+		} else {
+			b.WriteString(text + spark + "[-::-]")
+		}
+	}
+
+	step := time.Minute
+	if len(buckets) > 1 {
+		step = buckets[1].Dt.Sub(buckets[0].Dt)
+	}
+	fmt.Fprintf(&b, " %s%s | %d logs, at most %d per %s[-::-]", gray, buckets[len(buckets)-1].Dt.Add(step).Local().Format("15:04"), total, max, step)
+	return b.String()
+}
+
+// hasErrors reports whether a bucket counts records of level ERROR or more severe.
+func hasErrors(bucket grpcutil.GraphBucket) bool {
+	for level, count := range bucket.Levels {
+		if count == 0 {
+			continue
+		}
+		switch logformat.Severity(level) {
+		case pb.SeverityLevel_ERROR, pb.SeverityLevel_FATAL, pb.SeverityLevel_CRITICAL, pb.SeverityLevel_ALERT:
+			return true
+		}
+	}
+	return false
+}
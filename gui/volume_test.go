@@ -0,0 +1,32 @@
+package gui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/logfire-sh/cli/internal/theme"
+	"github.com/logfire-sh/cli/pkg/cmdutil/grpcutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVolumeLine(t *testing.T) {
+	local := time.Local
+	time.Local = time.UTC
+	t.Cleanup(func() { time.Local = local })
+
+	start := time.Date(2024, 5, 1, 22, 0, 0, 0, time.UTC)
+	buckets := []grpcutil.GraphBucket{
+		{Dt: start, Count: 2, Levels: map[string]int{"info": 2}},
+		{Dt: start.Add(time.Minute)},
+		{Dt: start.Add(2 * time.Minute), Count: 8, Levels: map[string]int{"info": 2, "error": 6}},
+	}
+
+	palette, _ := theme.Builtin(theme.Dark)
+	assert.Equal(t,
+		" [gray::-]volume 22:00[-::-] [white::-]▂[-::-][white::-] [-::-][red::-]█[-::-] [gray::-]22:03 | 10 logs, at most 8 per 1m0s[-::-]",
+		volumeLine(buckets, nil, palette))
+	assert.Equal(t,
+		" [gray::-]volume unavailable: unimplemented[-::-]",
+		volumeLine(nil, errors.New("unimplemented\ndetails"), palette))
+}
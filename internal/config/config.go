@@ -1,181 +1,438 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"os/user"
 	"path/filepath"
+	"sort"
+	"strings"
 
-	"github.com/spf13/viper"
+	"github.com/logfire-sh/cli/internal/fsutil"
+	"gopkg.in/yaml.v3"
 )
 
-// AuthConfig Config represents the configuration structure
+const (
+	DefaultContext = "default"
+
+	defaultEndpoint      = "https://api.logfire.ai/"
+	defaultGrpcEndpoint  = "api.logfire.ai:443"
+	defaultGrpcIngestion = "https://in.logfire.ai"
+	defaultTheme         = "dark"
+)
+
+// AuthConfig holds the credentials and endpoints of a single named context.
+// Theme is shared by all contexts and is stored at the top level of the config file.
 type AuthConfig struct {
-	Username      string `mapstructure:"username"`
-	Role          string `mapstructure:"role"`
-	Token         string `mapstructure:"token"`
-	ProfileID     string `mapstructure:"profile_id"`
-	RefreshToken  string `mapstructure:"refresh_token"`
-	EndPoint      string `mapstructure:"endpoint"`
-	TeamId        string `mapstructure:"team_id"`
-	AccountId     string `mapstructure:"account_id"`
-	GrpcEndpoint  string `mapstructure:"grpc_endpoint"`
-	GrpcIngestion string `mapstructure:"grpc_ingestion"`
-	Theme         string `mapstructure:"theme"`
+	Username      string `yaml:"username"`
+	Role          string `yaml:"role"`
+	Token         string `yaml:"token"`
+	ProfileID     string `yaml:"profile_id"`
+	RefreshToken  string `yaml:"refresh_token"`
+	EndPoint      string `yaml:"endpoint"`
+	TeamId        string `yaml:"team_id"`
+	AccountId     string `yaml:"account_id"`
+	GrpcEndpoint  string `yaml:"grpc_endpoint"`
+	GrpcIngestion string `yaml:"grpc_ingestion"`
+	Theme         string `yaml:"-"`
 }
 
 type Config interface {
-	UpdateConfig(*string, *string, *string, *string, *string, *string, *string, *string, *string, *string, *string) error
+	UpdateConfig(func(*AuthConfig)) error
 	DeleteConfig() error
 	HasEnvToken() bool
+	// TokenOverridden reports whether the token is set by LOGFIRE_TOKEN rather than saved.
+	TokenOverridden() bool
 	Get() *AuthConfig
+
+	CurrentContext() string
+	ContextNames() []string
+	UseContext(name string) error
+	GetValue(key string) (string, error)
+	SetValue(key, value string) error
+}
+
+// fileConfig is the on-disk layout of ~/.logfire.
+type fileConfig struct {
+	CurrentContext string                 `yaml:"current_context"`
+	Theme          string                 `yaml:"theme"`
+	Contexts       map[string]*AuthConfig `yaml:"contexts"`
+}
+
+// legacyConfig is the flat layout written before named contexts existed.
+type legacyConfig struct {
+	AuthConfig `yaml:",inline"`
+	Theme      string `yaml:"theme"`
 }
 
 type cfg struct {
-	AuthCfg *AuthConfig
+	path      string
+	file      *fileConfig
+	context   string
+	overrides Overrides
+	AuthCfg   *AuthConfig
+	effective *AuthConfig
 }
 
-func NewConfig() (Config, error) {
-	configFile, err := getHomeConfigPath()
-	if err != nil {
-		return nil, err
+// Overrides replace values of the selected context for the current process only.
+// They are never written to the config file, so CI jobs and containers can run
+// the CLI without storing credentials.
+type Overrides struct {
+	Token         string
+	EndPoint      string
+	GrpcEndpoint  string
+	GrpcIngestion string
+	TeamId        string
+}
+
+// EnvOverrides reads LOGFIRE_TOKEN, LOGFIRE_ENDPOINT, LOGFIRE_GRPC_ENDPOINT, LOGFIRE_GRPC_INGESTION
+// and LOGFIRE_TEAM.
+func EnvOverrides() Overrides {
+	return Overrides{
+		Token:         os.Getenv("LOGFIRE_TOKEN"),
+		EndPoint:      os.Getenv("LOGFIRE_ENDPOINT"),
+		GrpcEndpoint:  os.Getenv("LOGFIRE_GRPC_ENDPOINT"),
+		GrpcIngestion: os.Getenv("LOGFIRE_GRPC_INGESTION"),
+		TeamId:        os.Getenv("LOGFIRE_TEAM"),
+	}
+}
+
+// Merge returns o with the non-empty values of other taking precedence.
+func (o Overrides) Merge(other Overrides) Overrides {
+	if other.Token != "" {
+		o.Token = other.Token
+	}
+	if other.EndPoint != "" {
+		o.EndPoint = other.EndPoint
+	}
+	if other.GrpcEndpoint != "" {
+		o.GrpcEndpoint = other.GrpcEndpoint
+	}
+	if other.GrpcIngestion != "" {
+		o.GrpcIngestion = other.GrpcIngestion
+	}
+	if other.TeamId != "" {
+		o.TeamId = other.TeamId
+	}
+	return o
+}
+
+func (o Overrides) apply(authConfig AuthConfig) *AuthConfig {
+	if o.Token != "" {
+		authConfig.Token = o.Token
+	}
+	if o.EndPoint != "" {
+		authConfig.EndPoint = withTrailingSlash(o.EndPoint)
+	}
+	if o.GrpcEndpoint != "" {
+		authConfig.GrpcEndpoint = o.GrpcEndpoint
+	}
+	if o.GrpcIngestion != "" {
+		authConfig.GrpcIngestion = o.GrpcIngestion
 	}
+	if o.TeamId != "" {
+		authConfig.TeamId = o.TeamId
+	}
+	return &authConfig
+}
+
+// withTrailingSlash keeps endpoint compatible with the API calls, which append paths without a separator.
+func withTrailingSlash(endpoint string) string {
+	if strings.HasSuffix(endpoint, "/") {
+		return endpoint
+	}
+	return endpoint + "/"
+}
+
+// Options select the config file and context to load.
+type Options struct {
+	// Path defaults to $LOGFIRE_CONFIG, then ~/.logfire.
+	Path string
+	// Context defaults to the current context of the file.
+	Context string
+	// Create allows Context to name a context that is not saved yet.
+	Create    bool
+	Overrides Overrides
+}
+
+// Keys lists the settings accepted by GetValue and SetValue.
+var Keys = []string{"username", "role", "token", "profile_id", "refresh_token", "endpoint", "team_id",
+	"account_id", "grpc_endpoint", "grpc_ingestion", "theme"}
+
+// NewConfig loads the current context with the environment overrides applied.
+func NewConfig() (Config, error) {
+	return NewConfigForContext("")
+}
+
+// NewConfigForContext loads the named context with the environment overrides applied.
+// An empty name selects the file's current context.
+func NewConfigForContext(name string) (Config, error) {
+	return Load(Options{Context: name, Overrides: EnvOverrides()})
+}
 
-	// Set up Viper for YAML configuration
-	viper.SetConfigFile(configFile)
-	viper.SetConfigType("yaml")
-	viper.SetDefault("username", "")
-	viper.SetDefault("role", "")
-	viper.SetDefault("token", "")
-	viper.SetDefault("profile_id", "")
-	viper.SetDefault("endpoint", "https://api.logfire.ai/")
-	viper.SetDefault("grpc_endpoint", "api.logfire.ai:443")
-	viper.SetDefault("grpc_ingestion", "https://in.logfire.ai")
-	viper.SetDefault("team_id", "")
-	viper.SetDefault("account_id", "")
-	viper.SetDefault("theme", "dark")
-
-	// Check if the config file exists
-	if _, err := os.Stat(configFile); os.IsNotExist(err) {
-		// Config file doesn't exist, create a new one
-		if err := viper.WriteConfig(); err != nil {
+// Load reads the config file and selects a context. Naming a context that does not exist is
+// an error unless opts.Create is set: such a context starts out with the default endpoints and
+// is only written once it is updated.
+func Load(opts Options) (Config, error) {
+	configFile := opts.Path
+	if configFile == "" {
+		var err error
+		configFile, err = getConfigPath()
+		if err != nil {
 			return nil, err
 		}
 	}
 
-	// Read the configuration from the file
-	if err := viper.ReadInConfig(); err != nil {
+	c, err := newConfigAt(configFile, opts.Context)
+	if err != nil {
 		return nil, err
 	}
+	if _, ok := c.file.Contexts[opts.Context]; opts.Context != "" && !opts.Create && !ok {
+		names := c.ContextNames()
+		if len(names) == 0 {
+			return nil, fmt.Errorf("unknown context %q, no context is saved yet", opts.Context)
+		}
+		return nil, fmt.Errorf("unknown context %q, expected one of: %s", opts.Context, strings.Join(names, ", "))
+	}
+
+	c.overrides = opts.Overrides
+	c.effective = c.overrides.apply(*c.AuthCfg)
 
-	// Unmarshal the configuration into the Config struct
-	authConfig := &AuthConfig{}
-	if err := viper.Unmarshal(authConfig); err != nil {
+	return c, nil
+}
+
+func newConfigAt(configFile, name string) (*cfg, error) {
+	file, err := readConfigFile(configFile)
+	if err != nil {
 		return nil, err
 	}
 
-	return &cfg{authConfig}, nil
-}
+	if name == "" {
+		name = file.CurrentContext
+	}
 
-func (c *cfg) Get() *AuthConfig {
-	return c.AuthCfg
+	c := &cfg{path: configFile, file: file}
+	c.selectContext(name)
+
+	return c, nil
 }
 
-// UpdateConfig updates the configuration values and writes them to the config file
-func (c *cfg) UpdateConfig(username, role, token, profileID, refreshToken, teamid, accountid, endpoint, grpcEndpoint, grpcIngestion, theme *string) error {
-	// Write the updated configuration to the file
+func readConfigFile(path string) (*fileConfig, error) {
+	file := &fileConfig{
+		CurrentContext: DefaultContext,
+		Theme:          defaultTheme,
+		Contexts:       map[string]*AuthConfig{},
+	}
 
-	if username != nil {
-		viper.Set("username", *username)
-		c.AuthCfg.Username = *username
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return file, nil
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	if role != nil {
-		viper.Set("role", *role)
-		c.AuthCfg.Role = *role
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
 	}
 
-	if token != nil {
-		viper.Set("token", *token)
-		c.AuthCfg.Token = *token
+	if _, ok := raw["contexts"]; !ok {
+		// Migrate a flat config file into the default context.
+		var legacy legacyConfig
+		if err := yaml.Unmarshal(data, &legacy); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+
+		if legacy.Theme != "" {
+			file.Theme = legacy.Theme
+		}
+		authConfig := legacy.AuthConfig
+		file.Contexts[DefaultContext] = withDefaults(&authConfig)
+		return file, nil
 	}
 
-	if profileID != nil {
-		viper.Set("profile_id", *profileID)
-		c.AuthCfg.ProfileID = *profileID
+	if err := yaml.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
 	}
 
-	if refreshToken != nil {
-		viper.Set("refresh_token", *refreshToken)
-		c.AuthCfg.RefreshToken = *refreshToken
+	if file.Contexts == nil {
+		file.Contexts = map[string]*AuthConfig{}
+	}
+	for _, authConfig := range file.Contexts {
+		withDefaults(authConfig)
+	}
+	if file.CurrentContext == "" {
+		file.CurrentContext = DefaultContext
 	}
+	if file.Theme == "" {
+		file.Theme = defaultTheme
+	}
+
+	return file, nil
+}
 
-	if teamid != nil {
-		viper.Set("team_id", *teamid)
-		c.AuthCfg.TeamId = *teamid
+func withDefaults(authConfig *AuthConfig) *AuthConfig {
+	if authConfig.EndPoint == "" {
+		authConfig.EndPoint = defaultEndpoint
 	}
+	if authConfig.GrpcEndpoint == "" {
+		authConfig.GrpcEndpoint = defaultGrpcEndpoint
+	}
+	if authConfig.GrpcIngestion == "" {
+		authConfig.GrpcIngestion = defaultGrpcIngestion
+	}
+	return authConfig
+}
 
-	if accountid != nil {
-		viper.Set("account_id", *accountid)
-		c.AuthCfg.AccountId = *accountid
+func (c *cfg) selectContext(name string) {
+	authConfig, ok := c.file.Contexts[name]
+	if !ok {
+		authConfig = withDefaults(&AuthConfig{})
 	}
+	authConfig.Theme = c.file.Theme
 
-	if endpoint != nil {
-		viper.Set("endpoint", *endpoint)
-		c.AuthCfg.EndPoint = *endpoint
+	c.context = name
+	c.AuthCfg = authConfig
+	c.effective = c.overrides.apply(*authConfig)
+}
+
+// Get returns the selected context with any overrides applied.
+func (c *cfg) Get() *AuthConfig {
+	return c.effective
+}
+
+// UpdateConfig applies update to the selected context and writes the config file.
+// Overrides are left out of the file.
+func (c *cfg) UpdateConfig(update func(*AuthConfig)) error {
+	update(c.AuthCfg)
+	c.effective = c.overrides.apply(*c.AuthCfg)
+
+	c.file.Theme = c.AuthCfg.Theme
+	c.file.Contexts[c.context] = c.AuthCfg
+	if len(c.file.Contexts) == 1 {
+		c.file.CurrentContext = c.context
 	}
 
-	if grpcEndpoint != nil {
-		viper.Set("grpc_endpoint", *grpcEndpoint)
-		c.AuthCfg.GrpcEndpoint = *grpcEndpoint
+	return c.write()
+}
+
+func (c *cfg) HasEnvToken() bool {
+	return c.effective.Token != "" || c.effective.Username != "" || c.effective.ProfileID != ""
+}
+
+func (c *cfg) TokenOverridden() bool {
+	return c.overrides.Token != ""
+}
+
+// DeleteConfig removes the selected context. The config file is removed once no context is left.
+func (c *cfg) DeleteConfig() error {
+	delete(c.file.Contexts, c.context)
+	c.selectContext(c.context)
+
+	if len(c.file.Contexts) == 0 {
+		err := os.Remove(c.path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
 	}
 
-	if grpcIngestion != nil {
-		viper.Set("grpc_ingestion", *grpcIngestion)
-		c.AuthCfg.GrpcIngestion = *grpcIngestion
+	if c.file.CurrentContext == c.context {
+		c.file.CurrentContext = c.ContextNames()[0]
 	}
 
-	if theme != nil {
-		viper.Set("theme", *theme)
-		c.AuthCfg.Theme = *theme
+	return c.write()
+}
+
+func (c *cfg) CurrentContext() string {
+	return c.context
+}
+
+// ContextNames returns the names of the saved contexts in alphabetical order.
+func (c *cfg) ContextNames() []string {
+	names := make([]string, 0, len(c.file.Contexts))
+	for name := range c.file.Contexts {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+	return names
+}
 
-	if err := viper.WriteConfig(); err != nil {
-		return err
+// UseContext makes name the current context of the config file.
+func (c *cfg) UseContext(name string) error {
+	if _, ok := c.file.Contexts[name]; !ok {
+		return fmt.Errorf("no context named %q", name)
 	}
 
-	return nil
+	c.file.CurrentContext = name
+	c.selectContext(name)
+
+	return c.write()
 }
 
-func (c *cfg) HasEnvToken() bool {
-	return c.AuthCfg.Token != "" || c.AuthCfg.Username != "" || c.AuthCfg.ProfileID != ""
+func (c *cfg) GetValue(key string) (string, error) {
+	field := fieldOf(c.effective, key)
+	if field == nil {
+		return "", fmt.Errorf("unknown config key %q", key)
+	}
+	return *field, nil
 }
 
-func (c *cfg) DeleteConfig() error {
-	c.AuthCfg.Username = ""
-	c.AuthCfg.Role = ""
-	c.AuthCfg.Token = ""
-	c.AuthCfg.RefreshToken = ""
-	c.AuthCfg.TeamId = ""
-	c.AuthCfg.ProfileID = ""
-	c.AuthCfg.EndPoint = "https://api.logfire.ai/"
-	c.AuthCfg.GrpcEndpoint = "api.logfire.ai:443"
-	c.AuthCfg.GrpcIngestion = "https://in.logfire.ai"
-
-	configFile, err := getHomeConfigPath()
-	if err != nil {
-		return err
+func (c *cfg) SetValue(key, value string) error {
+	if fieldOf(c.AuthCfg, key) == nil {
+		return fmt.Errorf("unknown config key %q", key)
+	}
+
+	return c.UpdateConfig(func(authConfig *AuthConfig) {
+		*fieldOf(authConfig, key) = value
+	})
+}
+
+func fieldOf(a *AuthConfig, key string) *string {
+	switch key {
+	case "username":
+		return &a.Username
+	case "role":
+		return &a.Role
+	case "token":
+		return &a.Token
+	case "profile_id":
+		return &a.ProfileID
+	case "refresh_token":
+		return &a.RefreshToken
+	case "endpoint":
+		return &a.EndPoint
+	case "team_id":
+		return &a.TeamId
+	case "account_id":
+		return &a.AccountId
+	case "grpc_endpoint":
+		return &a.GrpcEndpoint
+	case "grpc_ingestion":
+		return &a.GrpcIngestion
+	case "theme":
+		return &a.Theme
 	}
+	return nil
+}
 
-	// Delete file from homedir.
-	err = os.Remove(configFile)
+// write replaces the config file atomically so an interrupted write never loses credentials.
+func (c *cfg) write() error {
+	data, err := yaml.Marshal(c.file)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return fsutil.WriteFile(c.path, data, 0600)
 }
 
-func getHomeConfigPath() (string, error) {
+func getConfigPath() (string, error) {
+	if path := os.Getenv("LOGFIRE_CONFIG"); path != "" {
+		return path, nil
+	}
+
 	// Get user's home directory
 	usr, err := user.Current()
 	if err != nil {
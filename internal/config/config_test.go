@@ -0,0 +1,114 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLegacyConfigMigratesToDefaultContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".logfire")
+	legacy := "username: me@example.com\ntoken: abc\nendpoint: https://api.logfire.ai/\nteam_id: team-1\ntheme: light\n"
+	require.NoError(t, os.WriteFile(path, []byte(legacy), 0600))
+
+	c, err := newConfigAt(path, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, DefaultContext, c.CurrentContext())
+	assert.Equal(t, "abc", c.Get().Token)
+	assert.Equal(t, "team-1", c.Get().TeamId)
+	assert.Equal(t, "light", c.Get().Theme)
+	assert.Equal(t, defaultGrpcEndpoint, c.Get().GrpcEndpoint)
+}
+
+func TestContexts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".logfire")
+
+	prod, err := newConfigAt(path, "")
+	require.NoError(t, err)
+	require.NoError(t, prod.UpdateConfig(func(a *AuthConfig) {
+		a.Token = "prod-token"
+	}))
+
+	staging, err := newConfigAt(path, "staging")
+	require.NoError(t, err)
+	assert.Equal(t, "", staging.Get().Token)
+	require.NoError(t, staging.SetValue("endpoint", "https://staging.api.logfire.ai/"))
+	require.NoError(t, staging.SetValue("token", "staging-token"))
+
+	c, err := newConfigAt(path, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{DefaultContext, "staging"}, c.ContextNames())
+	assert.Equal(t, "prod-token", c.Get().Token)
+
+	require.NoError(t, c.UseContext("staging"))
+	c, err = newConfigAt(path, "")
+	require.NoError(t, err)
+	assert.Equal(t, "staging", c.CurrentContext())
+	endpoint, err := c.GetValue("endpoint")
+	require.NoError(t, err)
+	assert.Equal(t, "https://staging.api.logfire.ai/", endpoint)
+
+	assert.Error(t, c.UseContext("missing"))
+	_, err = c.GetValue("nope")
+	assert.Error(t, err)
+
+	require.NoError(t, c.DeleteConfig())
+	c, err = newConfigAt(path, "")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultContext, c.CurrentContext())
+	assert.Equal(t, []string{DefaultContext}, c.ContextNames())
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestLoadUnknownContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".logfire")
+
+	_, err := Load(Options{Path: path, Context: "staging"})
+	assert.EqualError(t, err, `unknown context "staging", no context is saved yet`)
+
+	staging, err := Load(Options{Path: path, Context: "staging", Create: true})
+	require.NoError(t, err)
+	require.NoError(t, staging.SetValue("token", "staging-token"))
+
+	_, err = Load(Options{Path: path, Context: "stagign"})
+	assert.EqualError(t, err, `unknown context "stagign", expected one of: staging`)
+
+	c, err := Load(Options{Path: path, Context: "staging"})
+	require.NoError(t, err)
+	assert.Equal(t, "staging-token", c.Get().Token)
+}
+
+func TestOverridesAreNotPersisted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".logfire")
+
+	c, err := Load(Options{Path: path, Overrides: Overrides{
+		Token:         "env-token",
+		EndPoint:      "http://localhost:8080",
+		GrpcIngestion: "http://localhost:8080/ingest",
+		TeamId:        "env-team",
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, "env-token", c.Get().Token)
+	assert.Equal(t, "http://localhost:8080/", c.Get().EndPoint)
+	assert.Equal(t, "http://localhost:8080/ingest", c.Get().GrpcIngestion)
+	assert.True(t, c.HasEnvToken())
+
+	require.NoError(t, c.SetValue("team_id", "stored-team"))
+	teamId, err := c.GetValue("team_id")
+	require.NoError(t, err)
+	assert.Equal(t, "env-team", teamId)
+
+	stored, err := Load(Options{Path: path})
+	require.NoError(t, err)
+	assert.Equal(t, "", stored.Get().Token)
+	assert.Equal(t, defaultEndpoint, stored.Get().EndPoint)
+	assert.Equal(t, defaultGrpcIngestion, stored.Get().GrpcIngestion)
+	assert.Equal(t, "stored-team", stored.Get().TeamId)
+}
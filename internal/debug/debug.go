@@ -0,0 +1,64 @@
+// Package debug traces the HTTP and gRPC traffic of the CLI when --debug or LOGFIRE_DEBUG is set.
+// Credentials are redacted before anything is written.
+package debug
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	mu  sync.Mutex
+	out io.Writer
+)
+
+// Enable writes traces to w from now on. A nil w turns tracing off.
+func Enable(w io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+	out = w
+}
+
+// Enabled reports whether traces are being written.
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return out != nil
+}
+
+// EnvEnabled reports whether LOGFIRE_DEBUG asks for tracing. Any value other than
+// an empty string, "0" or "false" turns it on.
+func EnvEnabled() bool {
+	value := os.Getenv("LOGFIRE_DEBUG")
+	if value == "" {
+		return false
+	}
+	enabled, err := strconv.ParseBool(value)
+	return err != nil || enabled
+}
+
+// printf writes one trace line. Lines of concurrent requests are never interleaved.
+func printf(format string, args ...interface{}) {
+	mu.Lock()
+	defer mu.Unlock()
+	if out == nil {
+		return
+	}
+	fmt.Fprintf(out, format, args...)
+}
+
+var secretRE = regexp.MustCompile(`(?i)("(?:sourceToken|accessToken|refreshToken|credential|password)"\s*:\s*)"[^"]*"`)
+
+// Redact hides the credentials a JSON document may carry.
+func Redact(body []byte) string {
+	return secretRE.ReplaceAllString(string(body), `${1}"REDACTED"`)
+}
+
+func elapsed(start time.Time) time.Duration {
+	return time.Since(start).Round(time.Millisecond)
+}
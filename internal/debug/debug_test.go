@@ -0,0 +1,89 @@
+package debug
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "source token",
+			input: `{"name":"api","sourceToken":"abc123"}`,
+			want:  `{"name":"api","sourceToken":"REDACTED"}`,
+		},
+		{
+			name:  "bearer tokens with spacing",
+			input: `{"accessToken": "a", "refreshToken" : "b"}`,
+			want:  `{"accessToken": "REDACTED", "refreshToken" : "REDACTED"}`,
+		},
+		{
+			name:  "password credential",
+			input: `{"email":"a@b.c","authType":2,"credential":"secret"}`,
+			want:  `{"email":"a@b.c","authType":2,"credential":"REDACTED"}`,
+		},
+		{
+			name:  "nothing to redact",
+			input: `{"isSuccessful":true}`,
+			want:  `{"isSuccessful":true}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Redact([]byte(tt.input)))
+		})
+	}
+}
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, `{"name":"api"}`, string(body))
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		io.WriteString(w, `{"isSuccessful":true,"data":{"sourceToken":"abc123"}}`)
+	}))
+	defer server.Close()
+
+	out := &bytes.Buffer{}
+	Enable(out)
+	defer Enable(nil)
+
+	req, err := http.NewRequest("POST", server.URL+"/api/team/1/source", strings.NewReader(`{"name":"api"}`))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+
+	resp, err := HTTPClient(&http.Client{}).Do(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	assert.Equal(t, `{"isSuccessful":true,"data":{"sourceToken":"abc123"}}`, string(body))
+
+	trace := out.String()
+	assert.Contains(t, trace, "> POST "+server.URL+"/api/team/1/source")
+	assert.Contains(t, trace, "> Authorization: REDACTED")
+	assert.Contains(t, trace, `{"name":"api"}`)
+	assert.Contains(t, trace, "< 200 OK")
+	assert.Contains(t, trace, `"sourceToken":"REDACTED"`)
+	assert.NotContains(t, trace, "secret")
+	assert.NotContains(t, trace, "abc123")
+}
+
+func TestEnvEnabled(t *testing.T) {
+	for value, want := range map[string]bool{"": false, "0": false, "false": false, "1": true, "true": true, "yes": true} {
+		t.Setenv("LOGFIRE_DEBUG", value)
+		assert.Equal(t, want, EnvEnabled(), "LOGFIRE_DEBUG=%q", value)
+	}
+}
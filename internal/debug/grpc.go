@@ -0,0 +1,86 @@
+package debug
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// DialOptions returns the interceptors that trace gRPC calls, or nothing when tracing is off.
+// They are chained after the options already given to grpc.Dial.
+func DialOptions() []grpc.DialOption {
+	if !Enabled() {
+		return nil
+	}
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(unaryInterceptor),
+		grpc.WithChainStreamInterceptor(streamInterceptor),
+	}
+}
+
+func unaryInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	printf("> grpc %s %s\n", method, message(req))
+
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	if err != nil {
+		printf("< grpc %s %s (%s)\n\n", method, status.Code(err), elapsed(start))
+		return err
+	}
+
+	printf("< grpc %s OK (%s) %s\n\n", method, elapsed(start), message(reply))
+	return nil
+}
+
+func streamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	start := time.Now()
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		printf("< grpc stream %s %s (%s)\n\n", method, status.Code(err), elapsed(start))
+		return nil, err
+	}
+
+	printf("> grpc stream %s opened (%s)\n", method, elapsed(start))
+	return &tracedStream{ClientStream: stream, method: method, start: start}, nil
+}
+
+type tracedStream struct {
+	grpc.ClientStream
+	method string
+	start  time.Time
+}
+
+func (s *tracedStream) SendMsg(m interface{}) error {
+	printf("> grpc stream %s %s\n", s.method, message(m))
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *tracedStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		printf("< grpc stream %s closed: %s (%s)\n\n", s.method, status.Code(err), elapsed(s.start))
+		return err
+	}
+	printf("< grpc stream %s %s\n", s.method, message(m))
+	return nil
+}
+
+func message(m interface{}) string {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return ""
+	}
+	b, err := protojson.Marshal(msg)
+	if err != nil {
+		return ""
+	}
+	text := Redact(b)
+	if len(text) > maxBody {
+		text = text[:maxBody] + "... (truncated)"
+	}
+	return text
+}
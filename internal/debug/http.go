@@ -0,0 +1,105 @@
+package debug
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxBody caps the bytes of a request or response body that are traced.
+const maxBody = 8 * 1024
+
+// Transport traces every request and response going through Base.
+type Transport struct {
+	Base http.RoundTripper
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+	return t.Base
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !Enabled() {
+		return t.base().RoundTrip(req)
+	}
+
+	var reqBody []byte
+	if req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			reqBody, _ = io.ReadAll(body)
+			body.Close()
+		}
+	}
+	reqText := bodyText(reqBody)
+	if encoding := req.Header.Get("Content-Encoding"); encoding != "" && len(reqBody) > 0 {
+		reqText = fmt.Sprintf("(%d bytes, %s encoded)\n", len(reqBody), encoding)
+	}
+	printf("> %s %s\n%s%s", req.Method, req.URL, headers(req.Header, "> "), reqText)
+
+	start := time.Now()
+	resp, err := t.base().RoundTrip(req)
+	if err != nil {
+		printf("< %s %s failed after %s: %v\n\n", req.Method, req.URL, elapsed(start), err)
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	if err != nil {
+		printf("< %s (%s), reading the body failed: %v\n\n", resp.Status, elapsed(start), err)
+		return resp, nil
+	}
+
+	printf("< %s (%s)\n%s%s\n", resp.Status, elapsed(start), headers(resp.Header, "< "), bodyText(respBody))
+	return resp, nil
+}
+
+// HTTPClient returns a copy of client whose requests are traced, or client itself when tracing is off.
+func HTTPClient(client *http.Client) *http.Client {
+	if !Enabled() {
+		return client
+	}
+	if _, ok := client.Transport.(*Transport); ok {
+		return client
+	}
+	traced := *client
+	traced.Transport = &Transport{Base: client.Transport}
+	return &traced
+}
+
+func headers(h http.Header, prefix string) string {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		value := strings.Join(h[name], ", ")
+		if strings.EqualFold(name, "Authorization") {
+			value = "REDACTED"
+		}
+		b.WriteString(prefix + name + ": " + value + "\n")
+	}
+	return b.String()
+}
+
+func bodyText(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	text := Redact(body)
+	if len(text) > maxBody {
+		text = text[:maxBody] + "... (truncated)"
+	}
+	return text + "\n"
+}
@@ -0,0 +1,445 @@
+package devserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	sqlModels "github.com/logfire-sh/cli/pkg/cmd/sql/models"
+	pb "github.com/logfire-sh/cli/services/flink-service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// sourceTopicPrefix is put in front of source IDs in gRPC requests, see grpcutil.CreateGrpcSource.
+const sourceTopicPrefix = "source_topic_"
+
+const (
+	defaultBatchSize = 100
+	graphBuckets     = 60
+)
+
+type filterServer struct {
+	pb.UnimplementedFilterServiceServer
+	store *Store
+}
+
+type metaServer struct {
+	pb.UnimplementedMetaServiceServer
+	store *Store
+}
+
+// authorize checks the access token in the Authorization metadata of ctx.
+func authorize(ctx context.Context, store *Store) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		if _, ok := store.Authenticate(strings.TrimSpace(strings.TrimPrefix(value, "Bearer"))); ok {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "invalid or expired access token")
+}
+
+func (s *filterServer) GetFilteredData(ctx context.Context, request *pb.FilterRequest) (*pb.FilteredRecords, error) {
+	if err := authorize(ctx, s.store); err != nil {
+		return nil, err
+	}
+	return &pb.FilteredRecords{
+		Records:      s.store.filter(request),
+		IsScrollDown: request.IsScrollDown,
+		Sources:      request.Sources,
+	}, nil
+}
+
+// GetStreamData sends the records matching request, then every new matching record as it is ingested.
+func (s *filterServer) GetStreamData(request *pb.FilterRequest, stream pb.FilterService_GetStreamDataServer) error {
+	ctx := stream.Context()
+	if err := authorize(ctx, s.store); err != nil {
+		return err
+	}
+
+	for {
+		changed := s.store.Changed()
+
+		records := s.store.filter(request)
+		if len(records) > 0 {
+			if err := stream.Send(&pb.FilteredRecords{Records: records, IsScrollDown: true, Sources: request.Sources}); err != nil {
+				return err
+			}
+			advance(request.Sources, records)
+			request.IsScrollDown = true
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changed:
+		}
+	}
+}
+
+// advance moves the starting offset of every source behind the records already sent.
+func advance(sources []*pb.Source, records []*pb.FilteredRecord) {
+	for _, source := range sources {
+		for _, record := range records {
+			if record.SourceID == source.SourceID && record.Offset >= source.StartingOffset {
+				source.StartingOffset = record.Offset + 1
+			}
+		}
+	}
+}
+
+// SubmitSQL does not interpret the query: it answers with the records of the sources in the
+// date range, in the format of a real query result.
+func (s *filterServer) SubmitSQL(ctx context.Context, request *pb.SQLRequest) (*pb.SQLResponse, error) {
+	if err := authorize(ctx, s.store); err != nil {
+		return nil, err
+	}
+
+	limit := int(request.PerPage)
+	if limit == 0 {
+		limit = int(request.BatchSize)
+	}
+	if limit == 0 {
+		limit = defaultBatchSize
+	}
+
+	start, end := timeRange(request.DateTimeFilter)
+	result := sqlModels.SQLResponse{
+		Fields: []sqlModels.SQLFieldsBody{{Name: "dt", Type: "string"}, {Name: "source", Type: "string"},
+			{Name: "level", Type: "string"}, {Name: "message", Type: "string"}},
+		Records: []map[string]interface{}{},
+	}
+
+	var all []Record
+	for _, src := range request.Sources {
+		source, err := s.store.GetSource(src.SourceID)
+		if err != nil {
+			continue
+		}
+		records := s.store.records(source.ID)
+		all = append(all, records...)
+		for _, record := range records {
+			if len(result.Records) >= limit || !inRange(record, start, end) {
+				continue
+			}
+			row := map[string]interface{}{
+				"dt":      record.Dt.Format(time.RFC3339Nano),
+				"source":  source.Name,
+				"level":   record.Level,
+				"message": record.Message,
+			}
+			for key, value := range record.Fields {
+				row[key] = value
+			}
+			result.Records = append(result.Records, row)
+		}
+	}
+	for _, name := range fieldNames(all) {
+		result.Fields = append(result.Fields, sqlModels.SQLFieldsBody{Name: name, Type: "string"})
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.SQLResponse{Data: string(data), TotalCount: uint32(len(result.Records)), PerPage: uint32(limit)}, nil
+}
+
+func (s *filterServer) GetOffsetData(ctx context.Context, request *pb.OffsetRequest) (*pb.OffsetResponse, error) {
+	if err := authorize(ctx, s.store); err != nil {
+		return nil, err
+	}
+
+	response := &pb.OffsetResponse{Type: request.Type}
+	for _, id := range request.IDs {
+		source, err := s.store.GetSource(id)
+		if err != nil {
+			return nil, status.Errorf(codes.NotFound, "no source with id %s", id)
+		}
+		// An empty source has no offsets, which MaxOffset below MinOffset stands for.
+		offsets := &pb.OffsetRecord{ID: id, MaxOffset: -1}
+		if records := s.store.records(source.ID); len(records) > 0 {
+			offsets.MinOffset = int64(records[0].Offset)
+			offsets.MaxOffset = int64(records[len(records)-1].Offset)
+		}
+		response.OffsetRecords = append(response.OffsetRecords, offsets)
+	}
+	return response, nil
+}
+
+// graphBucket is the meta of one GraphResponse of GetBarGraph, as JSON.
+type graphBucket struct {
+	Dt      string         `json:"dt"`
+	Count   int            `json:"count"`
+	Levels  map[string]int `json:"levels"`
+	Sources map[string]int `json:"sources"`
+}
+
+// GetBarGraph streams the number of matching records in each of 60 equal buckets of the date range,
+// oldest first. The range defaults to the last hour.
+func (s *metaServer) GetBarGraph(request *pb.GraphRequest, stream pb.MetaService_GetBarGraphServer) error {
+	if err := authorize(stream.Context(), s.store); err != nil {
+		return err
+	}
+
+	start, end := timeRange(request.DateTimeFilter)
+	if end.IsZero() {
+		end = time.Now().UTC()
+	}
+	if start.IsZero() || !start.Before(end) {
+		start = end.Add(-time.Hour)
+	}
+	width := end.Sub(start) / graphBuckets
+	if width <= 0 {
+		width = time.Nanosecond
+	}
+
+	buckets := make([]graphBucket, graphBuckets)
+	for i := range buckets {
+		buckets[i] = graphBucket{
+			Dt:      start.Add(time.Duration(i) * width).Format(time.RFC3339),
+			Levels:  map[string]int{},
+			Sources: map[string]int{},
+		}
+	}
+
+	query := newQuery(&pb.DateTimeFilter{}, request.FieldBasedFilters, nil)
+	for _, src := range request.Sources {
+		source, err := s.store.GetSource(src.SourceID)
+		if err != nil {
+			continue
+		}
+		for _, record := range s.store.records(source.ID) {
+			if record.Dt.Before(start) || !record.Dt.Before(end) || !query.match(record) {
+				continue
+			}
+			index := int(record.Dt.Sub(start) / width)
+			if index >= graphBuckets {
+				index = graphBuckets - 1
+			}
+			bucket := &buckets[index]
+			bucket.Count++
+			bucket.Levels[record.Level]++
+			bucket.Sources[source.Name]++
+		}
+	}
+
+	for _, bucket := range buckets {
+		meta, err := json.Marshal(bucket)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&pb.GraphResponse{Meta: string(meta)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetStatus streams the record count and the time of the newest record of every source.
+func (s *metaServer) GetStatus(request *pb.GraphRequest, stream pb.MetaService_GetStatusServer) error {
+	if err := authorize(stream.Context(), s.store); err != nil {
+		return err
+	}
+
+	for _, src := range request.Sources {
+		source, err := s.store.GetSource(src.SourceID)
+		if err != nil {
+			continue
+		}
+		records := s.store.records(source.ID)
+		sourceStatus := map[string]interface{}{
+			"sourceID":   src.SourceID,
+			"sourceName": source.Name,
+			"records":    len(records),
+		}
+		if len(records) > 0 {
+			sourceStatus["latestTimestamp"] = records[len(records)-1].Dt.Format(time.RFC3339Nano)
+		}
+		meta, err := json.Marshal(sourceStatus)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&pb.GraphResponse{Meta: string(meta)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filter returns the records of the request's sources that match its date range, field filters
+// and search queries. Each source is paged on its own: scrolling down, or from a starting offset,
+// returns the oldest batch after that offset, otherwise the newest batch.
+func (s *Store) filter(request *pb.FilterRequest) []*pb.FilteredRecord {
+	batchSize := int(request.BatchSize)
+	if batchSize == 0 {
+		batchSize = defaultBatchSize
+	}
+	query := newQuery(request.DateTimeFilter, request.FieldBasedFilters, request.SearchQueries)
+
+	var result []*pb.FilteredRecord
+	for _, src := range request.Sources {
+		source, err := s.GetSource(src.SourceID)
+		if err != nil {
+			continue
+		}
+
+		var matched []Record
+		for _, record := range s.records(source.ID) {
+			if record.Offset < src.StartingOffset || (src.EndingOffset > 0 && record.Offset >= src.EndingOffset) {
+				continue
+			}
+			if query.match(record) {
+				matched = append(matched, record)
+			}
+		}
+
+		if len(matched) > batchSize {
+			if request.IsScrollDown || src.StartingOffset > 0 {
+				matched = matched[:batchSize]
+			} else {
+				matched = matched[len(matched)-batchSize:]
+			}
+		}
+
+		for _, record := range matched {
+			result = append(result, &pb.FilteredRecord{
+				Offset:     record.Offset,
+				Message:    record.Message,
+				Dt:         record.Dt.Format(time.RFC3339Nano),
+				Level:      record.Level,
+				SourceName: source.Name,
+				SourceID:   src.SourceID,
+			})
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Dt < result[j].Dt })
+	return result
+}
+
+type query struct {
+	start, end time.Time
+	filters    []*pb.FieldBasedFilter
+	search     []string
+}
+
+func newQuery(dateTime *pb.DateTimeFilter, filters []*pb.FieldBasedFilter, search []string) query {
+	start, end := timeRange(dateTime)
+	return query{start: start, end: end, filters: filters, search: search}
+}
+
+func timeRange(filter *pb.DateTimeFilter) (time.Time, time.Time) {
+	var start, end time.Time
+	if filter.GetStartTimeStamp() != nil {
+		start = filter.GetStartTimeStamp().AsTime()
+	}
+	if filter.GetEndTimeStamp() != nil {
+		end = filter.GetEndTimeStamp().AsTime()
+	}
+	return start, end
+}
+
+func inRange(record Record, start, end time.Time) bool {
+	if !start.IsZero() && record.Dt.Before(start) {
+		return false
+	}
+	return end.IsZero() || !record.Dt.After(end)
+}
+
+func (q query) match(record Record) bool {
+	if !inRange(record, q.start, q.end) {
+		return false
+	}
+	for _, filter := range q.filters {
+		if !matchField(record, filter) {
+			return false
+		}
+	}
+	for _, search := range q.search {
+		if !containsText(record, search) {
+			return false
+		}
+	}
+	return true
+}
+
+func fieldValue(record Record, name string) (string, bool) {
+	switch name {
+	case "message":
+		return record.Message, true
+	case "level":
+		return record.Level, true
+	case "dt":
+		return record.Dt.Format(time.RFC3339Nano), true
+	}
+	value, ok := record.Fields[name]
+	if !ok {
+		return "", false
+	}
+	if str, isString := value.(string); isString {
+		return str, true
+	}
+	return fmt.Sprint(value), true
+}
+
+func matchField(record Record, filter *pb.FieldBasedFilter) bool {
+	value, ok := fieldValue(record, filter.FieldName)
+	want := filter.FieldValue
+
+	switch filter.Operator {
+	case pb.FieldBasedFilter_CONTAINS:
+		return ok && strings.Contains(strings.ToLower(value), strings.ToLower(want))
+	case pb.FieldBasedFilter_DOES_NOT_CONTAIN:
+		return !ok || !strings.Contains(strings.ToLower(value), strings.ToLower(want))
+	case pb.FieldBasedFilter_EQUALS:
+		return ok && compare(value, want) == 0
+	case pb.FieldBasedFilter_NOT_EQUALS:
+		return !ok || compare(value, want) != 0
+	case pb.FieldBasedFilter_GREATER_THAN:
+		return ok && compare(value, want) > 0
+	case pb.FieldBasedFilter_GREATER_THAN_EQUALS:
+		return ok && compare(value, want) >= 0
+	case pb.FieldBasedFilter_LESS_THAN:
+		return ok && compare(value, want) < 0
+	case pb.FieldBasedFilter_LESS_THAN_EQUALS:
+		return ok && compare(value, want) <= 0
+	}
+	return false
+}
+
+// compare compares numerically when both values are numbers, and as strings otherwise.
+func compare(a, b string) int {
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(a, b)
+}
+
+func containsText(record Record, text string) bool {
+	text = strings.ToLower(text)
+	if strings.Contains(strings.ToLower(record.Message), text) {
+		return true
+	}
+	for _, value := range record.Fields {
+		if strings.Contains(strings.ToLower(fmt.Sprint(value)), text) {
+			return true
+		}
+	}
+	return false
+}
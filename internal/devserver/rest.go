@@ -0,0 +1,581 @@
+package devserver
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	alertModels "github.com/logfire-sh/cli/pkg/cmd/alerts/models"
+	integrationModels "github.com/logfire-sh/cli/pkg/cmd/integrations/models"
+	loginModels "github.com/logfire-sh/cli/pkg/cmd/login/models"
+	resetPasswordModels "github.com/logfire-sh/cli/pkg/cmd/reset_password/models"
+	sourceModels "github.com/logfire-sh/cli/pkg/cmd/sources/models"
+	teamModels "github.com/logfire-sh/cli/pkg/cmd/teams/models"
+	viewModels "github.com/logfire-sh/cli/pkg/cmd/views/models"
+)
+
+// IngestPath is where the dev server accepts logs; it is the grpc_ingestion endpoint of a config
+// context pointing at the dev server.
+const IngestPath = "/ingest"
+
+var errUnauthorized = errors.New("not authorized")
+
+// restHandler serves the routes of the Logfire API used by the CLI.
+type restHandler struct {
+	store *Store
+}
+
+// response is the envelope of every API answer.
+type response struct {
+	IsSuccessful bool        `json:"isSuccessful"`
+	Message      []string    `json:"message,omitempty"`
+	Data         interface{} `json:"data,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeData(w http.ResponseWriter, data interface{}) {
+	writeJSON(w, http.StatusOK, response{IsSuccessful: true, Data: data})
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, errNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, errUnauthorized):
+		status = http.StatusUnauthorized
+	}
+	writeJSON(w, status, response{Message: []string{err.Error()}})
+}
+
+func readJSON(r *http.Request, v interface{}) error {
+	body := io.Reader(r.Body)
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		body = gz
+	}
+	if err := json.NewDecoder(body).Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+func bearerToken(r *http.Request) string {
+	return strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer"))
+}
+
+func (h *restHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+	route := strings.Split(path, "/")
+
+	switch {
+	case "/"+path == IngestPath:
+		h.ingest(w, r)
+	case strings.HasPrefix(path, "api/auth/"):
+		h.auth(w, r, route[2])
+	default:
+		user, ok := h.store.Authenticate(bearerToken(r))
+		if !ok {
+			writeError(w, errUnauthorized)
+			return
+		}
+		switch {
+		case route[0] == "api" && len(route) >= 2 && route[1] == "team":
+			h.team(w, r, user, route[2:])
+		case route[0] == "api" && len(route) >= 3 && route[1] == "profile":
+			h.profile(w, r, user, route[2:])
+		case route[0] == "ai" && strings.HasSuffix(r.URL.Path, "/filter-recommend"):
+			// The dev server has no recommendations to give.
+			writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{"recommendations": []interface{}{}}})
+		case route[0] == "ai":
+			writeJSON(w, http.StatusOK, map[string]interface{}{"data": []interface{}{}})
+		default:
+			http.NotFound(w, r)
+		}
+	}
+}
+
+func (h *restHandler) ingest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var raw json.RawMessage
+	if err := readJSON(r, &raw); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// Both a single record and a batch of records are accepted.
+	var records []map[string]interface{}
+	if err := json.Unmarshal(raw, &records); err != nil {
+		var record map[string]interface{}
+		if err := json.Unmarshal(raw, &record); err != nil {
+			writeError(w, err)
+			return
+		}
+		records = append(records, record)
+	}
+
+	if _, err := h.store.Ingest(bearerToken(r), records); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, response{IsSuccessful: true})
+}
+
+func (h *restHandler) auth(w http.ResponseWriter, r *http.Request, action string) {
+	switch action {
+	case "signin":
+		var request loginModels.SigninRequest
+		if err := readJSON(r, &request); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		var user User
+		var token, refreshToken string
+		var err error
+		if request.Email == "" {
+			// The CLI sends magic link tokens without an email; any live access token will do.
+			user, err = h.store.SignInWithToken(request.Credential)
+			token = request.Credential
+		} else {
+			user, token, refreshToken, err = h.store.SignIn(request.Email, request.Credential)
+		}
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		h.writeSession(w, user, token, refreshToken)
+	case "refresh":
+		var request struct {
+			RefreshToken string `json:"refreshToken"`
+		}
+		if err := readJSON(r, &request); err != nil {
+			writeError(w, err)
+			return
+		}
+		token, refreshToken, err := h.store.Refresh(request.RefreshToken)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, loginModels.Response{
+			IsSuccessful: true,
+			BearerToken:  loginModels.BearerToken{AccessToken: token, RefreshToken: refreshToken},
+		})
+	case "signout":
+		var request struct {
+			AccessToken  string
+			RefreshToken string
+		}
+		if err := readJSON(r, &request); err != nil {
+			writeError(w, err)
+			return
+		}
+		_ = h.store.SignOut(request.AccessToken)
+		_ = h.store.SignOut(request.RefreshToken)
+		writeJSON(w, http.StatusOK, response{IsSuccessful: true})
+	case "magiclink", "signup":
+		writeJSON(w, http.StatusOK, response{
+			IsSuccessful: true,
+			Message:      []string{"The dev server sends no emails, sign in with " + DevEmail + " and password " + DevPassword},
+		})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *restHandler) writeSession(w http.ResponseWriter, user User, token, refreshToken string) {
+	team := h.store.DefaultTeam()
+	writeJSON(w, http.StatusOK, loginModels.Response{
+		IsSuccessful: true,
+		Email:        user.Email,
+		UserBody: loginModels.UserBody{
+			ProfileID: user.ProfileID,
+			AccountID: user.AccountID,
+			Onboarded: user.Onboarded,
+			Email:     user.Email,
+			Role:      user.Role,
+		},
+		TeamBody:    loginModels.TeamBody{Id: team.ID, Name: team.Name, AccountId: user.AccountID, Role: team.Role},
+		BearerToken: loginModels.BearerToken{AccessToken: token, RefreshToken: refreshToken},
+	})
+}
+
+// profile accepts the profile updates of the CLI. Only passwords are kept; onboarding, names,
+// flags and deletion are acknowledged without changing anything.
+func (h *restHandler) profile(w http.ResponseWriter, r *http.Request, user User, route []string) {
+	if route[0] != user.ProfileID {
+		writeError(w, errNotFound)
+		return
+	}
+
+	if len(route) == 2 && route[1] == "set-password" {
+		var request resetPasswordModels.ResetPasswordRequest
+		if err := readJSON(r, &request); err != nil {
+			writeError(w, err)
+			return
+		}
+		if err := h.store.SetPassword(user.ProfileID, request.Password); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, response{IsSuccessful: true})
+}
+
+func (h *restHandler) team(w http.ResponseWriter, r *http.Request, user User, route []string) {
+	if len(route) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			writeData(w, h.store.ListTeams())
+		case http.MethodPost:
+			var request teamModels.CreateTeamRequest
+			if err := readJSON(r, &request); err != nil {
+				writeError(w, err)
+				return
+			}
+			team, err := h.store.CreateTeam(user.ProfileID, request.Name)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			writeData(w, team)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	teamId := route[0]
+	if !h.store.HasTeam(teamId) {
+		writeError(w, errNotFound)
+		return
+	}
+
+	if len(route) == 1 {
+		switch r.Method {
+		case http.MethodPut:
+			var request teamModels.CreateTeamRequest
+			if err := readJSON(r, &request); err != nil {
+				writeError(w, err)
+				return
+			}
+			team, err := h.store.UpdateTeam(teamId, request.Name)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			writeData(w, team)
+		case http.MethodDelete:
+			h.writeResult(w, h.store.DeleteTeam(teamId))
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	switch route[1] {
+	case "members":
+		h.members(w, r, teamId)
+	case "invites":
+		// Invites would be sent by email, which the dev server does not do.
+		writeJSON(w, http.StatusOK, response{IsSuccessful: true})
+	case "source":
+		h.sources(w, r, user, teamId, route[2:])
+	case "schema":
+		h.schema(w, r)
+	case "view":
+		h.views(w, r, teamId, route[2:])
+	case "alert", "alertpause":
+		h.alerts(w, r, teamId, route[1:])
+	case "alertintegrations":
+		var integrations []alertModels.AlertIntegrationBody
+		for _, integration := range h.store.ListIntegrations(teamId) {
+			integrations = append(integrations, alertModels.AlertIntegrationBody{
+				ModelId:     integration.Id,
+				Name:        integration.Name,
+				Type:        integration.Type,
+				Identifier:  integration.Email,
+				Description: integration.Description,
+			})
+		}
+		writeData(w, integrations)
+	case "integration":
+		h.integrations(w, r, teamId, route[2:])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *restHandler) writeResult(w http.ResponseWriter, err error) {
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response{IsSuccessful: true})
+}
+
+func (h *restHandler) members(w http.ResponseWriter, r *http.Request, teamId string) {
+	switch r.Method {
+	case http.MethodGet:
+		members := h.store.ListMembers(teamId)
+		writeData(w, teamModels.AllTMandTI{CountTeamMembers: len(members), TeamMembers: members})
+	case http.MethodDelete:
+		var request teamModels.RemoveMemberReq
+		if err := readJSON(r, &request); err != nil {
+			writeError(w, err)
+			return
+		}
+		h.writeResult(w, h.store.RemoveMember(teamId, request.MemberId))
+	case http.MethodPut:
+		// Roles are not enforced by the dev server.
+		writeJSON(w, http.StatusOK, response{IsSuccessful: true})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *restHandler) sources(w http.ResponseWriter, r *http.Request, user User, teamId string, route []string) {
+	if len(route) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			writeData(w, h.store.ListSources(teamId))
+		case http.MethodPost:
+			var request sourceModels.SourceCreate
+			if err := readJSON(r, &request); err != nil {
+				writeError(w, err)
+				return
+			}
+			source, err := h.store.CreateSource(teamId, user.ProfileID, request.Name, request.SourceType)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			writeData(w, source)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	source, err := h.store.GetSource(route[0])
+	if err != nil || source.TeamID != teamId {
+		writeError(w, errNotFound)
+		return
+	}
+
+	if len(route) == 2 && route[1] == "configuration" {
+		writeData(w, map[string]string{
+			"endpoint":    "POST " + IngestPath,
+			"token":       source.SourceToken,
+			"description": "Send JSON records with dt and message to the ingest endpoint, authorized with the source token.",
+		})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeData(w, source)
+	case http.MethodPut:
+		var request sourceModels.SourceCreate
+		if err := readJSON(r, &request); err != nil {
+			writeError(w, err)
+			return
+		}
+		source, err := h.store.UpdateSource(source.ID, request.Name)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeData(w, source)
+	case http.MethodDelete:
+		h.writeResult(w, h.store.DeleteSource(source.ID))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// schema answers with one {"field": "type"} object per field of the sources in the query string.
+func (h *restHandler) schema(w http.ResponseWriter, r *http.Request) {
+	var records []Record
+	for sourceId := range parseQueryKeys(r.URL.RawQuery) {
+		records = append(records, h.store.records(sourceId)...)
+	}
+
+	schema := []map[string]string{{"dt": "string"}, {"message": "string"}, {"level": "string"}}
+	types := map[string]string{}
+	for _, record := range records {
+		for key, value := range record.Fields {
+			switch value.(type) {
+			case float64:
+				types[key] = "int"
+			case bool:
+				types[key] = "bool"
+			default:
+				types[key] = "string"
+			}
+		}
+	}
+	for _, name := range fieldNames(records) {
+		schema = append(schema, map[string]string{name: types[name]})
+	}
+	writeJSON(w, http.StatusOK, schema)
+}
+
+func parseQueryKeys(rawQuery string) map[string]bool {
+	keys := map[string]bool{}
+	values, _ := url.ParseQuery(rawQuery)
+	for key := range values {
+		keys[key] = true
+	}
+	return keys
+}
+
+func (h *restHandler) views(w http.ResponseWriter, r *http.Request, teamId string, route []string) {
+	if len(route) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			writeData(w, h.store.ListViews(teamId))
+		case http.MethodPost:
+			var request viewModels.ViewResponseBody
+			if err := readJSON(r, &request); err != nil {
+				writeError(w, err)
+				return
+			}
+			view, err := h.store.CreateView(teamId, request)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			writeData(w, view)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		view, err := h.store.GetView(teamId, route[0])
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeData(w, view)
+	case http.MethodDelete:
+		h.writeResult(w, h.store.DeleteView(teamId, route[0]))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *restHandler) alerts(w http.ResponseWriter, r *http.Request, teamId string, route []string) {
+	if route[0] == "alertpause" {
+		var request alertModels.PauseAlertRequest
+		if err := readJSON(r, &request); err != nil {
+			writeError(w, err)
+			return
+		}
+		h.writeResult(w, h.store.UpdateAlerts(teamId, request.AlertIds, func(alert *alertModels.CreateAlertBody) {
+			alert.AlertPaused = request.AlertPause
+		}))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeData(w, h.store.ListAlerts(teamId))
+	case http.MethodPost, http.MethodPut:
+		var request alertModels.CreateAlertRequest
+		if err := readJSON(r, &request); err != nil {
+			writeError(w, err)
+			return
+		}
+		alert := alertModels.CreateAlertBody{
+			Name:                    request.Name,
+			Description:             request.Description,
+			ViewId:                  request.ViewId,
+			AlertWhenHasMoreRecords: request.AlertWhenHasMoreRecords,
+			NumberOfRecords:         request.NumberOfRecords,
+			WithinSeconds:           request.WithinSeconds,
+			AlertSeverity:           request.AlertSeverity,
+			Integrations:            request.Integrations,
+			AlertPaused:             request.AlertPaused,
+			AlertLabels:             request.AlertLabels,
+			Runbook:                 request.Runbook,
+		}
+		if len(route) > 1 {
+			alert.Id = route[1]
+		}
+		alert, err := h.store.SaveAlert(teamId, alert)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeData(w, alert)
+	case http.MethodDelete:
+		var request alertModels.DeleteAlertRequest
+		if err := readJSON(r, &request); err != nil {
+			writeError(w, err)
+			return
+		}
+		h.writeResult(w, h.store.DeleteAlerts(teamId, request.AlertIds))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *restHandler) integrations(w http.ResponseWriter, r *http.Request, teamId string, route []string) {
+	if len(route) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			writeData(w, h.store.ListIntegrations(teamId))
+		case http.MethodPost:
+			var request integrationModels.CreateIntegrationRequest
+			if err := readJSON(r, &request); err != nil {
+				writeError(w, err)
+				return
+			}
+			integration, err := h.store.CreateIntegration(teamId, request)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			writeData(w, integration)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		var request integrationModels.UpdateIntegrationRequest
+		if err := readJSON(r, &request); err != nil {
+			writeError(w, err)
+			return
+		}
+		h.writeResult(w, h.store.UpdateIntegration(teamId, route[0], request))
+	case http.MethodDelete:
+		h.writeResult(w, h.store.DeleteIntegration(teamId, route[0]))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,87 @@
+// Package devserver is a local stand-in for the Logfire API. It serves the REST routes and the
+// FilterService and MetaService gRPC services used by the CLI from an in-memory or file backed
+// Store, so commands can be developed, demonstrated and tested without network access.
+package devserver
+
+import (
+	"errors"
+	"net"
+	"net/http"
+
+	pb "github.com/logfire-sh/cli/services/flink-service"
+	"google.golang.org/grpc"
+)
+
+// Server runs the REST API and the gRPC services of a Store on two listeners.
+type Server struct {
+	Store *Store
+
+	http     *http.Server
+	grpc     *grpc.Server
+	httpAddr net.Addr
+	grpcAddr net.Addr
+}
+
+func New(store *Store) *Server {
+	grpcServer := grpc.NewServer()
+	pb.RegisterFilterServiceServer(grpcServer, &filterServer{store: store})
+	pb.RegisterMetaServiceServer(grpcServer, &metaServer{store: store})
+
+	return &Server{
+		Store: store,
+		http:  &http.Server{Handler: &restHandler{store: store}},
+		grpc:  grpcServer,
+	}
+}
+
+// Handler returns the handler of the REST API and of the ingest endpoint.
+func (s *Server) Handler() http.Handler {
+	return s.http.Handler
+}
+
+// Start listens on httpAddr and grpcAddr, such as "127.0.0.1:0", and serves in the background.
+func (s *Server) Start(httpAddr, grpcAddr string) error {
+	httpListener, err := net.Listen("tcp", httpAddr)
+	if err != nil {
+		return err
+	}
+	grpcListener, err := net.Listen("tcp", grpcAddr)
+	if err != nil {
+		httpListener.Close()
+		return err
+	}
+
+	s.httpAddr = httpListener.Addr()
+	s.grpcAddr = grpcListener.Addr()
+
+	go func() {
+		if err := s.http.Serve(httpListener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			grpcListener.Close()
+		}
+	}()
+	go func() {
+		_ = s.grpc.Serve(grpcListener)
+	}()
+	return nil
+}
+
+// Endpoint is the endpoint of a config context using the server.
+func (s *Server) Endpoint() string {
+	return "http://" + s.httpAddr.String() + "/"
+}
+
+// GrpcEndpoint is the grpc_endpoint of a config context using the server.
+func (s *Server) GrpcEndpoint() string {
+	return s.grpcAddr.String()
+}
+
+// IngestionEndpoint is the grpc_ingestion endpoint of a config context using the server.
+func (s *Server) IngestionEndpoint() string {
+	return "http://" + s.httpAddr.String() + IngestPath
+}
+
+// Close stops both listeners and the open streams.
+func (s *Server) Close() error {
+	s.grpc.Stop()
+	return s.http.Close()
+}
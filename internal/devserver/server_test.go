@@ -0,0 +1,143 @@
+package devserver
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/logfire-sh/cli/api"
+	pb "github.com/logfire-sh/cli/services/flink-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestServer(t *testing.T) {
+	server := New(NewStore())
+	require.NoError(t, server.Start("127.0.0.1:0", "127.0.0.1:0"))
+	defer server.Close()
+
+	session, err := api.NewClient(nil, server.Endpoint(), "").SignInWithPassword(DevEmail, DevPassword)
+	require.NoError(t, err)
+	teamId := session.TeamBody.Id
+
+	client := api.NewClient(nil, server.Endpoint(), session.BearerToken.AccessToken)
+	sources, err := client.ListSources(teamId)
+	require.NoError(t, err)
+	require.Len(t, sources, 1)
+	source := sources[0]
+
+	// The recommendations the GUI loads on start are empty, in the shape of each endpoint.
+	_, err = client.GetFilterRecommendations(teamId, "admin")
+	require.NoError(t, err)
+	_, err = client.GetRecommendations(teamId, "admin")
+	require.NoError(t, err)
+
+	err = api.NewClient(nil, server.IngestionEndpoint(), "wrong").SendLogs([]map[string]interface{}{{"message": "lost"}})
+	assert.Error(t, err)
+
+	ingest := api.NewClient(nil, server.IngestionEndpoint(), source.SourceToken)
+	require.NoError(t, ingest.SendLogs([]map[string]interface{}{
+		{"dt": "2024-05-01T12:00:00Z", "message": "GET /health", "status": 200},
+		{"dt": "2024-05-01T12:00:01Z", "message": "GET /orders", "status": 500, "level": "ERROR"},
+		{"dt": "2024-05-01T12:00:02Z", "message": "POST /orders", "status": 201},
+	}))
+
+	conn, err := grpc.Dial(server.GrpcEndpoint(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	filter := pb.NewFilterServiceClient(conn)
+
+	request := &pb.FilterRequest{
+		TeamID:        teamId,
+		Sources:       []*pb.Source{{SourceID: sourceTopicPrefix + source.ID, SourceName: source.Name}},
+		SearchQueries: []string{"orders"},
+		FieldBasedFilters: []*pb.FieldBasedFilter{
+			{FieldName: "status", FieldValue: "300", Operator: pb.FieldBasedFilter_GREATER_THAN},
+		},
+	}
+
+	_, err = filter.GetFilteredData(context.Background(), request)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "Authorization", "Bearer "+session.BearerToken.AccessToken)
+	response, err := filter.GetFilteredData(ctx, request)
+	require.NoError(t, err)
+	require.Len(t, response.Records, 1)
+	assert.Equal(t, "GET /orders", response.Records[0].Message)
+	assert.Equal(t, "error", response.Records[0].Level)
+	assert.Equal(t, uint64(1), response.Records[0].Offset)
+
+	offsets, err := filter.GetOffsetData(ctx, &pb.OffsetRequest{IDs: []string{source.ID}})
+	require.NoError(t, err)
+	require.Len(t, offsets.OffsetRecords, 1)
+	assert.Equal(t, int64(0), offsets.OffsetRecords[0].MinOffset)
+	assert.Equal(t, int64(2), offsets.OffsetRecords[0].MaxOffset)
+}
+
+func TestFilterPaging(t *testing.T) {
+	store := NewStore()
+	source := store.Sources[0]
+	_, err := store.Ingest(source.SourceToken, []map[string]interface{}{
+		{"message": "0"}, {"message": "1"}, {"message": "2"}, {"message": "3"}, {"message": "4"},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		source *pb.Source
+		down   bool
+		want   []uint64
+	}{
+		{
+			name:   "newest batch",
+			source: &pb.Source{SourceID: source.ID},
+			want:   []uint64{3, 4},
+		},
+		{
+			name:   "oldest batch scrolling down",
+			source: &pb.Source{SourceID: source.ID},
+			down:   true,
+			want:   []uint64{0, 1},
+		},
+		{
+			name:   "after a starting offset",
+			source: &pb.Source{SourceID: source.ID, StartingOffset: 2},
+			want:   []uint64{2, 3},
+		},
+		{
+			name:   "before an ending offset",
+			source: &pb.Source{SourceID: source.ID, EndingOffset: 2},
+			want:   []uint64{0, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records := store.filter(&pb.FilterRequest{Sources: []*pb.Source{tt.source}, BatchSize: 2, IsScrollDown: tt.down})
+			var offsets []uint64
+			for _, record := range records {
+				offsets = append(offsets, record.Offset)
+			}
+			assert.Equal(t, tt.want, offsets)
+		})
+	}
+}
+
+func TestOpenStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dev.json")
+
+	store, err := OpenStore(path)
+	require.NoError(t, err)
+	team, err := store.CreateTeam("profile", "saved")
+	require.NoError(t, err)
+
+	reopened, err := OpenStore(path)
+	require.NoError(t, err)
+	assert.True(t, reopened.HasTeam(team.ID))
+	assert.Equal(t, store.Sources, reopened.Sources)
+}
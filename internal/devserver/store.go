@@ -0,0 +1,704 @@
+package devserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/logfire-sh/cli/internal/fsutil"
+	alertModels "github.com/logfire-sh/cli/pkg/cmd/alerts/models"
+	integrationModels "github.com/logfire-sh/cli/pkg/cmd/integrations/models"
+	sourceModels "github.com/logfire-sh/cli/pkg/cmd/sources/models"
+	teamModels "github.com/logfire-sh/cli/pkg/cmd/teams/models"
+	viewModels "github.com/logfire-sh/cli/pkg/cmd/views/models"
+)
+
+// Seeded credentials of a new store, printed by `logfire dev server`.
+const (
+	DevEmail    = "dev@logfire.local"
+	DevPassword = "logfire"
+	DevTeam     = "dev"
+	DevSource   = "local"
+)
+
+// errNotFound is answered with 404 by the REST handlers.
+var errNotFound = errors.New("not found")
+
+type User struct {
+	Email     string `json:"email"`
+	Password  string `json:"password"`
+	ProfileID string `json:"profileId"`
+	AccountID string `json:"accountId"`
+	Role      string `json:"role"`
+	Onboarded bool   `json:"onboarded"`
+}
+
+// Record is one ingested log line. Offsets count up from 0 per source, like the partitions
+// the real FilterService reads from.
+type Record struct {
+	Offset  uint64                 `json:"offset"`
+	Dt      time.Time              `json:"dt"`
+	Level   string                 `json:"level"`
+	Message string                 `json:"message"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+}
+
+type View struct {
+	TeamID string `json:"teamId"`
+	viewModels.ViewResponseBody
+}
+
+type Alert struct {
+	TeamID string `json:"teamId"`
+	alertModels.CreateAlertBody
+}
+
+// Store keeps everything the dev server knows. It is safe for concurrent use and, when
+// opened with a path, saved to that file as JSON after every change.
+type Store struct {
+	mu      sync.Mutex
+	path    string
+	changed chan struct{}
+
+	Users        []User                                `json:"users"`
+	Sessions     map[string]string                     `json:"sessions"`
+	Teams        []teamModels.Team                     `json:"teams"`
+	Members      map[string][]teamModels.TeamMemberRes `json:"members"`
+	Sources      []sourceModels.Source                 `json:"sources"`
+	Views        []View                                `json:"views"`
+	Alerts       []Alert                               `json:"alerts"`
+	Integrations []integrationModels.IntegrationBody   `json:"integrations"`
+	Records      map[string][]Record                   `json:"records"`
+}
+
+// NewStore returns an in-memory store seeded with a user, a team and a source.
+func NewStore() *Store {
+	s := newStore()
+	s.seed()
+	return s
+}
+
+func newStore() *Store {
+	return &Store{
+		changed:  make(chan struct{}),
+		Sessions: make(map[string]string),
+		Members:  make(map[string][]teamModels.TeamMemberRes),
+		Records:  make(map[string][]Record),
+	}
+}
+
+// OpenStore loads the store saved at path, or seeds a new one that will be saved there.
+func OpenStore(path string) (*Store, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		s := NewStore()
+		s.path = path
+		return s, s.save()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s := newStore()
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if s.Sessions == nil {
+		s.Sessions = make(map[string]string)
+	}
+	if s.Members == nil {
+		s.Members = make(map[string][]teamModels.TeamMemberRes)
+	}
+	if s.Records == nil {
+		s.Records = make(map[string][]Record)
+	}
+	s.path = path
+	return s, nil
+}
+
+func (s *Store) seed() {
+	user := User{
+		Email:     DevEmail,
+		Password:  DevPassword,
+		ProfileID: uuid.NewString(),
+		AccountID: uuid.NewString(),
+		Role:      "admin",
+		Onboarded: true,
+	}
+	s.Users = append(s.Users, user)
+
+	team := teamModels.Team{ID: uuid.NewString(), Name: DevTeam, Role: "owner"}
+	s.Teams = append(s.Teams, team)
+	s.Members[team.ID] = []teamModels.TeamMemberRes{{
+		TeamMember: teamModels.TeamMember{ProfileId: user.ProfileID, TeamId: team.ID},
+		FirstName:  "Dev",
+	}}
+
+	s.addSource(team.ID, user.ProfileID, DevSource, sourceModels.PlatformMap["http"])
+}
+
+// save writes the store to its file, if it has one, and wakes up the streams waiting for
+// records. Called with s.mu held.
+func (s *Store) save() error {
+	close(s.changed)
+	s.changed = make(chan struct{})
+
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fsutil.WriteFile(s.path, data, 0600)
+}
+
+// update runs fn with the store locked and saves the store when fn succeeds.
+func (s *Store) update(fn func() error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := fn(); err != nil {
+		return err
+	}
+	return s.save()
+}
+
+// read runs fn with the store locked.
+func (s *Store) read(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn()
+}
+
+// Changed returns a channel that is closed at the next change of the store.
+func (s *Store) Changed() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.changed
+}
+
+// SignIn checks the credentials and returns the user with a new access and refresh token.
+func (s *Store) SignIn(email, password string) (User, string, string, error) {
+	var user User
+	var token, refreshToken string
+	err := s.update(func() error {
+		for _, u := range s.Users {
+			if strings.EqualFold(u.Email, email) && u.Password == password {
+				user = u
+				token, refreshToken = s.newSession(u.Email)
+				return nil
+			}
+		}
+		return errors.New("invalid email or password")
+	})
+	return user, token, refreshToken, err
+}
+
+// SignInWithToken accepts an access token of an existing session in place of a magic link token.
+func (s *Store) SignInWithToken(token string) (User, error) {
+	user, ok := s.Authenticate(token)
+	if !ok {
+		return User{}, errors.New("invalid token")
+	}
+	return user, nil
+}
+
+// Refresh replaces the session of refreshToken with a new one.
+func (s *Store) Refresh(refreshToken string) (string, string, error) {
+	var token string
+	err := s.update(func() error {
+		email, ok := s.Sessions[refreshToken]
+		if !ok || !strings.HasPrefix(refreshToken, refreshPrefix) {
+			return errUnauthorized
+		}
+		delete(s.Sessions, refreshToken)
+		token, refreshToken = s.newSession(email)
+		return nil
+	})
+	return token, refreshToken, err
+}
+
+const (
+	accessPrefix  = "dev_"
+	refreshPrefix = "devrefresh_"
+)
+
+// newSession is called with s.mu held.
+func (s *Store) newSession(email string) (string, string) {
+	token := accessPrefix + uuid.NewString()
+	refreshToken := refreshPrefix + uuid.NewString()
+	s.Sessions[token] = email
+	s.Sessions[refreshToken] = email
+	return token, refreshToken
+}
+
+func (s *Store) SignOut(token string) error {
+	return s.update(func() error {
+		delete(s.Sessions, token)
+		return nil
+	})
+}
+
+// Authenticate returns the user of an access token.
+func (s *Store) Authenticate(token string) (User, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	email, ok := s.Sessions[token]
+	if !ok || !strings.HasPrefix(token, accessPrefix) {
+		return User{}, false
+	}
+	for _, user := range s.Users {
+		if user.Email == email {
+			return user, true
+		}
+	}
+	return User{}, false
+}
+
+func (s *Store) SetPassword(profileId, password string) error {
+	return s.update(func() error {
+		for i := range s.Users {
+			if s.Users[i].ProfileID == profileId {
+				s.Users[i].Password = password
+				return nil
+			}
+		}
+		return errNotFound
+	})
+}
+
+// DefaultTeam returns the first team, which sign in reports as the team of the user.
+func (s *Store) DefaultTeam() teamModels.Team {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.Teams) == 0 {
+		return teamModels.Team{}
+	}
+	return s.Teams[0]
+}
+
+func (s *Store) ListTeams() []teamModels.Team {
+	var teams []teamModels.Team
+	s.read(func() { teams = append(teams, s.Teams...) })
+	return teams
+}
+
+func (s *Store) CreateTeam(profileId, name string) (teamModels.Team, error) {
+	team := teamModels.Team{ID: uuid.NewString(), Name: name, Role: "owner"}
+	err := s.update(func() error {
+		s.Teams = append(s.Teams, team)
+		s.Members[team.ID] = []teamModels.TeamMemberRes{{
+			TeamMember: teamModels.TeamMember{ProfileId: profileId, TeamId: team.ID},
+		}}
+		return nil
+	})
+	return team, err
+}
+
+func (s *Store) UpdateTeam(teamId, name string) (teamModels.Team, error) {
+	var team teamModels.Team
+	err := s.update(func() error {
+		for i := range s.Teams {
+			if s.Teams[i].ID == teamId {
+				s.Teams[i].Name = name
+				team = s.Teams[i]
+				return nil
+			}
+		}
+		return errNotFound
+	})
+	return team, err
+}
+
+func (s *Store) DeleteTeam(teamId string) error {
+	return s.update(func() error {
+		for i := range s.Teams {
+			if s.Teams[i].ID == teamId {
+				s.Teams = append(s.Teams[:i], s.Teams[i+1:]...)
+				delete(s.Members, teamId)
+				return nil
+			}
+		}
+		return errNotFound
+	})
+}
+
+// HasTeam reports whether teamId exists.
+func (s *Store) HasTeam(teamId string) bool {
+	found := false
+	s.read(func() {
+		for _, team := range s.Teams {
+			if team.ID == teamId {
+				found = true
+			}
+		}
+	})
+	return found
+}
+
+func (s *Store) ListMembers(teamId string) []teamModels.TeamMemberRes {
+	var members []teamModels.TeamMemberRes
+	s.read(func() { members = append(members, s.Members[teamId]...) })
+	return members
+}
+
+func (s *Store) RemoveMember(teamId, memberId string) error {
+	return s.update(func() error {
+		members := s.Members[teamId]
+		for i := range members {
+			if members[i].ProfileId == memberId {
+				s.Members[teamId] = append(members[:i], members[i+1:]...)
+				return nil
+			}
+		}
+		return errNotFound
+	})
+}
+
+func (s *Store) ListSources(teamId string) []sourceModels.Source {
+	var sources []sourceModels.Source
+	s.read(func() {
+		for _, source := range s.Sources {
+			if source.TeamID == teamId {
+				sources = append(sources, source)
+			}
+		}
+	})
+	return sources
+}
+
+// GetSource returns the source with the given ID, or with the given topic name
+// ("source_topic_<id>") used by the gRPC requests.
+func (s *Store) GetSource(sourceId string) (sourceModels.Source, error) {
+	sourceId = strings.TrimPrefix(sourceId, sourceTopicPrefix)
+
+	var source sourceModels.Source
+	err := errNotFound
+	s.read(func() {
+		for _, src := range s.Sources {
+			if src.ID == sourceId {
+				source, err = src, nil
+			}
+		}
+	})
+	return source, err
+}
+
+func (s *Store) sourceByToken(token string) (sourceModels.Source, bool) {
+	for _, source := range s.Sources {
+		if source.SourceToken == token {
+			return source, true
+		}
+	}
+	return sourceModels.Source{}, false
+}
+
+func (s *Store) CreateSource(teamId, profileId, name string, sourceType int) (sourceModels.Source, error) {
+	var source sourceModels.Source
+	err := s.update(func() error {
+		source = s.addSource(teamId, profileId, name, sourceType)
+		return nil
+	})
+	return source, err
+}
+
+// addSource is called with s.mu held.
+func (s *Store) addSource(teamId, profileId, name string, sourceType int) sourceModels.Source {
+	now := time.Now().UTC()
+	source := sourceModels.Source{
+		ID:          uuid.NewString(),
+		ProfileID:   profileId,
+		TeamID:      teamId,
+		Name:        name,
+		SourceType:  sourceType,
+		SourceToken: strings.ReplaceAll(uuid.NewString(), "-", ""),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	for platform, value := range sourceModels.PlatformMap {
+		if value == sourceType {
+			source.Platform = platform
+		}
+	}
+	s.Sources = append(s.Sources, source)
+	return source
+}
+
+func (s *Store) UpdateSource(sourceId, name string) (sourceModels.Source, error) {
+	var source sourceModels.Source
+	err := s.update(func() error {
+		for i := range s.Sources {
+			if s.Sources[i].ID == sourceId {
+				s.Sources[i].Name = name
+				s.Sources[i].UpdatedAt = time.Now().UTC()
+				source = s.Sources[i]
+				return nil
+			}
+		}
+		return errNotFound
+	})
+	return source, err
+}
+
+func (s *Store) DeleteSource(sourceId string) error {
+	return s.update(func() error {
+		for i := range s.Sources {
+			if s.Sources[i].ID == sourceId {
+				s.Sources = append(s.Sources[:i], s.Sources[i+1:]...)
+				delete(s.Records, sourceId)
+				return nil
+			}
+		}
+		return errNotFound
+	})
+}
+
+// Ingest appends records to the source owning token and returns how many were stored.
+func (s *Store) Ingest(token string, records []map[string]interface{}) (int, error) {
+	err := s.update(func() error {
+		source, ok := s.sourceByToken(token)
+		if !ok {
+			return errUnauthorized
+		}
+
+		stored := s.Records[source.ID]
+		for _, fields := range records {
+			stored = append(stored, newRecord(uint64(len(stored)), fields))
+		}
+		s.Records[source.ID] = stored
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(records), nil
+}
+
+// newRecord takes dt, message and level out of fields and keeps the rest as they are.
+func newRecord(offset uint64, fields map[string]interface{}) Record {
+	record := Record{Offset: offset, Dt: time.Now().UTC(), Fields: make(map[string]interface{})}
+	for key, value := range fields {
+		str, isString := value.(string)
+		switch {
+		case key == "dt" && isString:
+			if dt, ok := parseTime(str); ok {
+				record.Dt = dt
+			}
+		case key == "message" && isString:
+			record.Message = str
+		case (key == "level" || key == "severity") && isString && record.Level == "":
+			record.Level = strings.ToLower(str)
+		default:
+			record.Fields[key] = value
+		}
+	}
+	if record.Level == "" {
+		record.Level = "info"
+	}
+	return record
+}
+
+func parseTime(value string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// records returns a copy of the records of a source, in offset order.
+func (s *Store) records(sourceId string) []Record {
+	var records []Record
+	s.read(func() { records = append(records, s.Records[sourceId]...) })
+	return records
+}
+
+func (s *Store) ListViews(teamId string) []viewModels.ViewResponseBody {
+	var views []viewModels.ViewResponseBody
+	s.read(func() {
+		for _, view := range s.Views {
+			if view.TeamID == teamId {
+				views = append(views, view.ViewResponseBody)
+			}
+		}
+	})
+	return views
+}
+
+func (s *Store) GetView(teamId, viewId string) (viewModels.ViewResponseBody, error) {
+	for _, view := range s.ListViews(teamId) {
+		if view.Id == viewId {
+			return view, nil
+		}
+	}
+	return viewModels.ViewResponseBody{}, errNotFound
+}
+
+func (s *Store) CreateView(teamId string, view viewModels.ViewResponseBody) (viewModels.ViewResponseBody, error) {
+	view.Id = uuid.NewString()
+	err := s.update(func() error {
+		s.Views = append(s.Views, View{TeamID: teamId, ViewResponseBody: view})
+		return nil
+	})
+	return view, err
+}
+
+func (s *Store) DeleteView(teamId, viewId string) error {
+	return s.update(func() error {
+		for i := range s.Views {
+			if s.Views[i].TeamID == teamId && s.Views[i].Id == viewId {
+				s.Views = append(s.Views[:i], s.Views[i+1:]...)
+				return nil
+			}
+		}
+		return errNotFound
+	})
+}
+
+func (s *Store) ListAlerts(teamId string) []alertModels.CreateAlertBody {
+	var alerts []alertModels.CreateAlertBody
+	s.read(func() {
+		for _, alert := range s.Alerts {
+			if alert.TeamID == teamId {
+				alerts = append(alerts, alert.CreateAlertBody)
+			}
+		}
+	})
+	return alerts
+}
+
+// SaveAlert creates the alert, or replaces the alert with the same ID.
+func (s *Store) SaveAlert(teamId string, alert alertModels.CreateAlertBody) (alertModels.CreateAlertBody, error) {
+	if alert.Id == "" {
+		alert.Id = uuid.NewString()
+	}
+	err := s.update(func() error {
+		for i := range s.Alerts {
+			if s.Alerts[i].TeamID == teamId && s.Alerts[i].Id == alert.Id {
+				s.Alerts[i].CreateAlertBody = alert
+				return nil
+			}
+		}
+		s.Alerts = append(s.Alerts, Alert{TeamID: teamId, CreateAlertBody: alert})
+		return nil
+	})
+	return alert, err
+}
+
+// UpdateAlerts calls fn for every alert of the team listed in alertIds.
+func (s *Store) UpdateAlerts(teamId string, alertIds []string, fn func(alert *alertModels.CreateAlertBody)) error {
+	return s.update(func() error {
+		for i := range s.Alerts {
+			if s.Alerts[i].TeamID == teamId && contains(alertIds, s.Alerts[i].Id) {
+				fn(&s.Alerts[i].CreateAlertBody)
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Store) DeleteAlerts(teamId string, alertIds []string) error {
+	return s.update(func() error {
+		kept := s.Alerts[:0]
+		for _, alert := range s.Alerts {
+			if alert.TeamID != teamId || !contains(alertIds, alert.Id) {
+				kept = append(kept, alert)
+			}
+		}
+		s.Alerts = kept
+		return nil
+	})
+}
+
+func (s *Store) ListIntegrations(teamId string) []integrationModels.IntegrationBody {
+	var integrations []integrationModels.IntegrationBody
+	s.read(func() {
+		for _, integration := range s.Integrations {
+			if integration.TeamId == teamId {
+				integrations = append(integrations, integration)
+			}
+		}
+	})
+	return integrations
+}
+
+func (s *Store) CreateIntegration(teamId string, request integrationModels.CreateIntegrationRequest) (integrationModels.IntegrationBody, error) {
+	integration := integrationModels.IntegrationBody{
+		Name:        request.Name,
+		Type:        request.AlertType,
+		Description: request.Description,
+		Email:       request.Id,
+		Id:          uuid.NewString(),
+		TeamId:      teamId,
+	}
+	err := s.update(func() error {
+		s.Integrations = append(s.Integrations, integration)
+		return nil
+	})
+	return integration, err
+}
+
+func (s *Store) UpdateIntegration(teamId, integrationId string, request integrationModels.UpdateIntegrationRequest) error {
+	return s.update(func() error {
+		for i := range s.Integrations {
+			if s.Integrations[i].TeamId == teamId && s.Integrations[i].Id == integrationId {
+				if request.Name != "" {
+					s.Integrations[i].Name = request.Name
+				}
+				if request.Description != "" {
+					s.Integrations[i].Description = request.Description
+				}
+				return nil
+			}
+		}
+		return errNotFound
+	})
+}
+
+func (s *Store) DeleteIntegration(teamId, integrationId string) error {
+	return s.update(func() error {
+		for i := range s.Integrations {
+			if s.Integrations[i].TeamId == teamId && s.Integrations[i].Id == integrationId {
+				s.Integrations = append(s.Integrations[:i], s.Integrations[i+1:]...)
+				return nil
+			}
+		}
+		return errNotFound
+	})
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldNames returns the sorted names of all fields seen in the records of the sources.
+func fieldNames(records []Record) []string {
+	seen := map[string]bool{}
+	for _, record := range records {
+		for key := range record.Fields {
+			seen[key] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
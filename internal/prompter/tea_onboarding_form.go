@@ -14,8 +14,8 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	"github.com/logfire-sh/cli/pkg/cmdutil/grpcutil"
 )
 
@@ -130,6 +130,7 @@ type model struct {
 	choice      string
 	quitting    bool
 	config      config.Config
+	client      *api.Client
 	log         string
 	sourceId    string
 	sourceToken string
@@ -256,6 +257,7 @@ func initialModel() *model {
 		roleList: lr,
 		choice:   "",
 		config:   cfg,
+		client:   api.NewClientFromConfig(nil, cfg),
 		log:      "",
 		sourceId: "",
 	}
@@ -295,7 +297,7 @@ func (m *model) handleKeyPres() (tea.Model, tea.Cmd) {
 		switch subStep {
 		case "email":
 			if m.inputs[email].Value() != "" {
-				_, err := APICalls.SignupFlow(m.inputs[email].Value(), m.config.Get().EndPoint)
+				_, err := m.client.Signup(m.inputs[email].Value())
 				if err != nil {
 
 					m.err = err
@@ -306,11 +308,14 @@ func (m *model) handleKeyPres() (tea.Model, tea.Cmd) {
 			}
 		case "token":
 			if m.inputs[token].Value() != "" {
-				err, onboarded := APICalls.TokenSignIn(m.config, m.inputs[token].Value(), m.config.Get().EndPoint)
+				response, err := m.client.SignInWithToken(m.inputs[token].Value())
+				if err == nil {
+					err = api.SaveSession(m.config, response)
+				}
 				if err != nil {
 					m.err = err
 					return m, nil
-				} else if onboarded {
+				} else if response.UserBody.Onboarded {
 					m.err = errors.New("you have already onboarded. Please use `logfire stream` to start streaming logs or `logfire` to interact with the cli and create teams, sources and more")
 					return m, nil
 				}
@@ -334,8 +339,7 @@ func (m *model) handleKeyPres() (tea.Model, tea.Cmd) {
 		case "role":
 			i, _ := m.roleList.SelectedItem().(item)
 			if string(i) != "" {
-				err := APICalls.OnboardingFlow(m.config.Get().ProfileID, m.config.Get().Token, m.config.Get().EndPoint,
-					m.inputs[firstName].Value(), m.inputs[lastName].Value(), string(i))
+				err := m.client.Onboard(m.config.Get().ProfileID, m.inputs[firstName].Value(), m.inputs[lastName].Value())
 				if err != nil {
 					m.err = err
 					return m, nil
@@ -345,8 +349,7 @@ func (m *model) handleKeyPres() (tea.Model, tea.Cmd) {
 			}
 		case "password":
 			if m.inputs[password].Value() != "" {
-				err := APICalls.SetPassword(m.config.Get().Token, m.config.Get().EndPoint, m.config.Get().ProfileID,
-					m.inputs[password].Value())
+				err := m.client.SetPassword(m.config.Get().ProfileID, m.inputs[password].Value())
 				if err != nil {
 					m.err = err
 					return m, nil
@@ -360,14 +363,15 @@ func (m *model) handleKeyPres() (tea.Model, tea.Cmd) {
 		switch subStep {
 		case "teamName":
 			if m.inputs[teamName].Value() != "" {
-				team, err := APICalls.CreateTeam(m.config.Get().Token, m.config.Get().EndPoint,
-					m.inputs[teamName].Value())
+				team, err := m.client.CreateTeam(m.inputs[teamName].Value())
 				if err != nil {
 					m.err = err
 					return m, nil
 				}
 
-				err = m.config.UpdateConfig(nil, nil, nil, nil, nil, &team.ID, nil, nil, nil, nil, nil)
+				err = m.config.UpdateConfig(func(c *config.AuthConfig) {
+					c.TeamId = team.ID
+				})
 				if err != nil {
 					m.err = err
 					return m, nil
@@ -389,7 +393,7 @@ func (m *model) handleKeyPres() (tea.Model, tea.Cmd) {
 			m.nextInput()
 		case "sourceName":
 			if m.inputs[sourceName].Value() != "" && !sourceCreated {
-				source, err := APICalls.CreateSource(m.config.Get().Token, m.config.Get().EndPoint, m.config.Get().TeamId, m.inputs[sourceName].Value(), m.choice)
+				source, err := m.client.CreateSource(m.config.Get().TeamId, m.inputs[sourceName].Value(), m.choice)
 				if err != nil {
 					m.err = err
 					return m, nil
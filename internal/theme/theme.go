@@ -0,0 +1,281 @@
+// Package theme holds the colours of log lines and of the GUI: the built-in dark and light
+// palettes, and the user theme file that changes them.
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	Dark  = "dark"
+	Light = "light"
+)
+
+// Color is a colour name of the W3C or tcell, such as "red" or "slategray", or a hex value such
+// as "#ff8700", optionally preceded by "bold". The empty Color is the default colour.
+type Color string
+
+// parse returns the colour and whether it is bold, or an error for an unknown colour.
+func (c Color) parse() (tcell.Color, bool, error) {
+	fields := strings.Fields(strings.ToLower(string(c)))
+	bold := len(fields) > 0 && fields[0] == "bold"
+	if bold {
+		fields = fields[1:]
+	}
+	switch len(fields) {
+	case 0:
+		return tcell.ColorDefault, bold, nil
+	case 1:
+		if color := tcell.GetColor(fields[0]); color != tcell.ColorDefault {
+			return color, bold, nil
+		}
+	}
+	return tcell.ColorDefault, false, fmt.Errorf("unknown colour %q, expected a name such as red or slategray, or a hex value such as #ff8700", string(c))
+}
+
+// Name returns the colour without "bold", in lower case.
+func (c Color) Name() string {
+	return strings.TrimSpace(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(string(c))), "bold"))
+}
+
+// Bold reports whether the colour is bold.
+func (c Color) Bold() bool {
+	_, bold, _ := c.parse()
+	return bold
+}
+
+// TCell returns the colour for tcell, tcell.ColorDefault for the default or an unknown colour.
+func (c Color) TCell() tcell.Color {
+	color, _, _ := c.parse()
+	return color
+}
+
+// Tag returns the tview colour tag of the colour, which also sets or clears bold.
+func (c Color) Tag() string {
+	name := c.Name()
+	if name == "" {
+		name = "-"
+	}
+	if c.Bold() {
+		return "[" + name + "::b]"
+	}
+	return "[" + name + "::-]"
+}
+
+// UI are the colours of the GUI around the records.
+type UI struct {
+	Background  Color `yaml:"background"`
+	Text        Color `yaml:"text"`
+	Placeholder Color `yaml:"placeholder"`
+	Caret       Color `yaml:"caret"`
+	// Label colours the names of the fields of the record inspector.
+	Label Color `yaml:"label"`
+	// Error colours the errors shown in place of the help, and the failures of the connection.
+	Error Color `yaml:"error"`
+	// Connected and Connecting colour the state of the connection of the livetail, the latter
+	// while it connects or reconnects.
+	Connected  Color `yaml:"connected"`
+	Connecting Color `yaml:"connecting"`
+
+	AutocompleteBackground         Color `yaml:"autocomplete_background"`
+	AutocompleteText               Color `yaml:"autocomplete_text"`
+	AutocompleteSelectedBackground Color `yaml:"autocomplete_selected_background"`
+	AutocompleteSelectedText       Color `yaml:"autocomplete_selected_text"`
+}
+
+// Palette colours the parts of log lines, printed by tail and the other log commands as well as
+// by the GUI, and the GUI itself.
+type Palette struct {
+	// Base is the built-in palette that the theme file changes, Dark or Light.
+	Base string `yaml:"base"`
+
+	Timestamp Color `yaml:"timestamp"`
+	Source    Color `yaml:"source"`
+	// Message colours the messages of the GUI, UI.Text when empty. The CLI prints messages in
+	// the colour of the terminal, as they may hold highlighted matches.
+	Message Color `yaml:"message"`
+	// Levels colour each severity by its lower case name, such as warning. Levels without a
+	// colour use the one of info.
+	Levels map[string]Color `yaml:"levels"`
+
+	UI UI `yaml:"ui"`
+}
+
+// severities are the names of Palette.Levels, from the least to the most severe.
+var severities = []string{"trace", "debug", "info", "informational", "notice", "warning", "error", "fatal", "critical", "alert"}
+
+// Builtin returns a copy of the dark or light palette, and false for any other name.
+func Builtin(name string) (*Palette, bool) {
+	levels := map[string]Color{
+		"trace":         "gray",
+		"debug":         "gray",
+		"info":          "cyan",
+		"informational": "cyan",
+		"notice":        "blue",
+		"warning":       "yellow",
+		"error":         "red",
+		"fatal":         "bold red",
+		"critical":      "bold red",
+		"alert":         "bold red",
+	}
+	ui := UI{
+		AutocompleteBackground:         "gray",
+		AutocompleteText:               "#ffffff",
+		AutocompleteSelectedBackground: "gray",
+		AutocompleteSelectedText:       "slategray",
+		Placeholder:                    "gray",
+		Error:                          "red",
+		Connected:                      "green",
+	}
+
+	switch name {
+	case Dark:
+		ui.Background, ui.Text, ui.Caret, ui.Label, ui.Connecting = "black", "white", "white", "yellow", "yellow"
+		return &Palette{Base: Dark, Timestamp: "yellow", Source: "green", Levels: levels, UI: ui}, true
+	case Light:
+		levels["info"], levels["informational"], levels["warning"] = "blue", "blue", "olive"
+		ui.Background, ui.Text, ui.Caret, ui.Label, ui.Connecting = "#ffffff", "black", "black", "purple", "olive"
+		return &Palette{Base: Light, Timestamp: "gray", Source: "purple", Levels: levels, UI: ui}, true
+	}
+	return nil, false
+}
+
+// Level returns the colour of a severity such as "warning".
+func (p *Palette) Level(severity string) Color {
+	if color, ok := p.Levels[strings.ToLower(severity)]; ok {
+		return color
+	}
+	return p.Levels["info"]
+}
+
+// DefaultPath is $LOGFIRE_THEME, then ~/.logfire-theme.yml.
+func DefaultPath() string {
+	if path := os.Getenv("LOGFIRE_THEME"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".logfire-theme.yml")
+}
+
+// Load returns the built-in palette base, the theme of the config file, changed by the theme
+// file at path when it exists. The base of the theme file wins over base, and an unknown base
+// is dark. On error, Load returns the palette of base along with the error.
+func Load(base, path string) (*Palette, error) {
+	palette, ok := Builtin(base)
+	if !ok {
+		palette, _ = Builtin(Dark)
+	}
+	if path == "" {
+		return palette, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return palette, nil
+	}
+	if err != nil {
+		return palette, err
+	}
+
+	var file Palette
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return palette, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if err := file.validate(); err != nil {
+		return palette, fmt.Errorf("invalid theme %s: %w", path, err)
+	}
+
+	if file.Base != "" {
+		palette, _ = Builtin(file.Base)
+	}
+	palette.overlay(&file)
+	return palette, nil
+}
+
+// validate checks the base, the names of the levels and every colour of a theme file.
+func (p *Palette) validate() error {
+	if _, ok := Builtin(p.Base); p.Base != "" && !ok {
+		return fmt.Errorf("unknown base %q, expected %s or %s", p.Base, Dark, Light)
+	}
+
+	colors := map[string]Color{
+		"timestamp":                           p.Timestamp,
+		"source":                              p.Source,
+		"message":                             p.Message,
+		"ui.background":                       p.UI.Background,
+		"ui.text":                             p.UI.Text,
+		"ui.placeholder":                      p.UI.Placeholder,
+		"ui.caret":                            p.UI.Caret,
+		"ui.label":                            p.UI.Label,
+		"ui.error":                            p.UI.Error,
+		"ui.connected":                        p.UI.Connected,
+		"ui.connecting":                       p.UI.Connecting,
+		"ui.autocomplete_background":          p.UI.AutocompleteBackground,
+		"ui.autocomplete_text":                p.UI.AutocompleteText,
+		"ui.autocomplete_selected_background": p.UI.AutocompleteSelectedBackground,
+		"ui.autocomplete_selected_text":       p.UI.AutocompleteSelectedText,
+	}
+	for level, color := range p.Levels {
+		known := false
+		for _, severity := range severities {
+			known = known || level == severity
+		}
+		if !known {
+			return fmt.Errorf("unknown level %q, expected one of: %s", level, strings.Join(severities, ", "))
+		}
+		colors["levels."+level] = color
+	}
+
+	keys := make([]string, 0, len(colors))
+	for key := range colors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if _, _, err := colors[key].parse(); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// overlay sets the colours given by file.
+func (p *Palette) overlay(file *Palette) {
+	set := func(color *Color, value Color) {
+		if value != "" {
+			*color = value
+		}
+	}
+	set(&p.Timestamp, file.Timestamp)
+	set(&p.Source, file.Source)
+	set(&p.Message, file.Message)
+	for level, color := range file.Levels {
+		if color != "" {
+			p.Levels[level] = color
+		}
+	}
+
+	set(&p.UI.Background, file.UI.Background)
+	set(&p.UI.Text, file.UI.Text)
+	set(&p.UI.Placeholder, file.UI.Placeholder)
+	set(&p.UI.Caret, file.UI.Caret)
+	set(&p.UI.Label, file.UI.Label)
+	set(&p.UI.Error, file.UI.Error)
+	set(&p.UI.Connected, file.UI.Connected)
+	set(&p.UI.Connecting, file.UI.Connecting)
+	set(&p.UI.AutocompleteBackground, file.UI.AutocompleteBackground)
+	set(&p.UI.AutocompleteText, file.UI.AutocompleteText)
+	set(&p.UI.AutocompleteSelectedBackground, file.UI.AutocompleteSelectedBackground)
+	set(&p.UI.AutocompleteSelectedText, file.UI.AutocompleteSelectedText)
+}
@@ -0,0 +1,85 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTheme(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "theme.yml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeTheme(t, `
+timestamp: "#87afaf"
+levels:
+  warning: orange
+  error: bold fuchsia
+ui:
+  background: navy
+  connected: teal
+`)
+
+	palette, err := Load(Dark, path)
+	require.NoError(t, err)
+	assert.Equal(t, Color("#87afaf"), palette.Timestamp)
+	assert.Equal(t, Color("green"), palette.Source)
+	assert.Equal(t, Color("orange"), palette.Level("WARNING"))
+	assert.Equal(t, Color("bold fuchsia"), palette.Level("error"))
+	assert.Equal(t, Color("cyan"), palette.Level("unknown"))
+	assert.Equal(t, tcell.ColorNavy, palette.UI.Background.TCell())
+	assert.Equal(t, Color("white"), palette.UI.Text)
+	assert.Equal(t, Color("teal"), palette.UI.Connected)
+	assert.Equal(t, Color("yellow"), palette.UI.Connecting)
+
+	// The built-in palettes are left as they are.
+	dark, _ := Builtin(Dark)
+	assert.Equal(t, Color("yellow"), dark.Level("warning"))
+}
+
+func TestLoadBase(t *testing.T) {
+	palette, err := Load(Dark, writeTheme(t, "base: light\nsource: teal\n"))
+	require.NoError(t, err)
+	assert.Equal(t, Light, palette.Base)
+	assert.Equal(t, Color("teal"), palette.Source)
+	assert.Equal(t, Color("black"), palette.UI.Text)
+
+	palette, err = Load("", filepath.Join(t.TempDir(), "missing.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, Dark, palette.Base)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		content string
+		wantErr string
+	}{
+		{"base: sepia\n", `unknown base "sepia", expected dark or light`},
+		{"levels:\n  verbose: red\n", `unknown level "verbose", expected one of: trace, debug, info, informational, notice, warning, error, fatal, critical, alert`},
+		{"ui:\n  caret: reed\n", `ui.caret: unknown colour "reed", expected a name such as red or slategray, or a hex value such as #ff8700`},
+		{"source: bold red green\n", `source: unknown colour "bold red green"`},
+		{"source: [red]\n", `failed to parse`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.content, func(t *testing.T) {
+			palette, err := Load(Light, writeTheme(t, tt.content))
+			assert.ErrorContains(t, err, tt.wantErr)
+			assert.Equal(t, Light, palette.Base)
+		})
+	}
+}
+
+func TestColorTag(t *testing.T) {
+	assert.Equal(t, "[red::-]", Color("red").Tag())
+	assert.Equal(t, "[red::b]", Color("Bold Red").Tag())
+	assert.Equal(t, "[#ff8700::-]", Color("#ff8700").Tag())
+	assert.Equal(t, "[-::-]", Color("").Tag())
+}
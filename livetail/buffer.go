@@ -0,0 +1,93 @@
+package livetail
+
+import (
+	"sync"
+
+	pb "github.com/logfire-sh/cli/services/flink-service"
+)
+
+// BufferCapacity is how many records the GUI keeps, the oldest being dropped first.
+const BufferCapacity = 10000
+
+// Buffer keeps the last records of a stream up to a fixed capacity. Every record gets a sequence
+// number counting the records added before it, so that a view can tell which records it has
+// shown after older ones were dropped. It is safe for concurrent use.
+type Buffer struct {
+	mu      sync.Mutex
+	records []*pb.FilteredRecord
+	// next is the sequence number of the next record added, records[next%capacity] its slot.
+	next uint64
+}
+
+// NewBuffer returns a Buffer keeping up to capacity records.
+func NewBuffer(capacity int) *Buffer {
+	return &Buffer{records: make([]*pb.FilteredRecord, capacity)}
+}
+
+// Add appends records, dropping the oldest ones beyond the capacity.
+func (b *Buffer) Add(records []*pb.FilteredRecord) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, record := range records {
+		b.records[b.next%uint64(len(b.records))] = record
+		b.next++
+	}
+}
+
+// Bounds returns the sequence number of the oldest record kept and of the next record added.
+// Both are equal while the buffer is empty.
+func (b *Buffer) Bounds() (first, next uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.first(), b.next
+}
+
+func (b *Buffer) first() uint64 {
+	if b.next < uint64(len(b.records)) {
+		return 0
+	}
+	return b.next - uint64(len(b.records))
+}
+
+// Since returns the records kept from sequence number seq on, and the sequence number of the
+// first of them, which is past seq when records were dropped since.
+func (b *Buffer) Since(seq uint64) ([]*pb.FilteredRecord, uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if first := b.first(); seq < first {
+		seq = first
+	}
+	if seq >= b.next {
+		return nil, b.next
+	}
+
+	records := make([]*pb.FilteredRecord, 0, b.next-seq)
+	for i := seq; i < b.next; i++ {
+		records = append(records, b.records[i%uint64(len(b.records))])
+	}
+	return records, seq
+}
+
+// Get returns the record of sequence number seq, if it is still kept.
+func (b *Buffer) Get(seq uint64) (*pb.FilteredRecord, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if seq < b.first() || seq >= b.next {
+		return nil, false
+	}
+	return b.records[seq%uint64(len(b.records))], true
+}
+
+// Reset drops every record and starts the sequence numbers over.
+func (b *Buffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i := range b.records {
+		b.records[i] = nil
+	}
+	b.next = 0
+}
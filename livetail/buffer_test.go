@@ -0,0 +1,63 @@
+package livetail
+
+import (
+	"testing"
+
+	pb "github.com/logfire-sh/cli/services/flink-service"
+	"github.com/stretchr/testify/assert"
+)
+
+func messages(records []*pb.FilteredRecord) []string {
+	var messages []string
+	for _, record := range records {
+		messages = append(messages, record.Message)
+	}
+	return messages
+}
+
+func batch(messages ...string) []*pb.FilteredRecord {
+	var records []*pb.FilteredRecord
+	for _, message := range messages {
+		records = append(records, &pb.FilteredRecord{Message: message})
+	}
+	return records
+}
+
+func TestBuffer(t *testing.T) {
+	buffer := NewBuffer(3)
+	first, next := buffer.Bounds()
+	assert.Equal(t, []uint64{0, 0}, []uint64{first, next})
+
+	buffer.Add(batch("a", "b"))
+	records, from := buffer.Since(1)
+	assert.Equal(t, []string{"b"}, messages(records))
+	assert.Equal(t, uint64(1), from)
+
+	// d and e push a and b out.
+	buffer.Add(batch("c", "d", "e"))
+	first, next = buffer.Bounds()
+	assert.Equal(t, []uint64{2, 5}, []uint64{first, next})
+
+	records, from = buffer.Since(0)
+	assert.Equal(t, []string{"c", "d", "e"}, messages(records))
+	assert.Equal(t, uint64(2), from)
+
+	records, from = buffer.Since(5)
+	assert.Empty(t, records)
+	assert.Equal(t, uint64(5), from)
+
+	record, ok := buffer.Get(3)
+	assert.True(t, ok)
+	assert.Equal(t, "d", record.Message)
+	_, ok = buffer.Get(1)
+	assert.False(t, ok)
+	_, ok = buffer.Get(5)
+	assert.False(t, ok)
+
+	buffer.Reset()
+	first, next = buffer.Bounds()
+	assert.Equal(t, []uint64{0, 0}, []uint64{first, next})
+	buffer.Add(batch("f"))
+	records, _ = buffer.Since(0)
+	assert.Equal(t, []string{"f"}, messages(records))
+}
@@ -3,69 +3,225 @@ package livetail
 import (
 	"context"
 	"fmt"
-	"net/http"
-	"sort"
 	"sync"
 	"time"
 
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
+	"github.com/logfire-sh/cli/internal/theme"
 	"github.com/logfire-sh/cli/pkg/cmd/sources/models"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
+	"github.com/logfire-sh/cli/pkg/cmdutil/filters"
 	"github.com/logfire-sh/cli/pkg/cmdutil/grpcutil"
+	"github.com/logfire-sh/cli/pkg/cmdutil/logformat"
+	"github.com/rivo/tview"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	pb "github.com/logfire-sh/cli/services/flink-service"
 )
 
+// Livetail streams the records of the GUI into Records, one Session at a time.
 type Livetail struct {
-	Logs          string
-	pbSources     []*pb.Source
-	sourcesOffset map[string]uint64
-	offsetMutex   sync.Mutex // Mutex to protect sourcesOffset map
-	FilterService *grpcutil.FilterService
+	// Records are the last records received, up to BufferCapacity.
+	Records *Buffer
+
+	mu      sync.Mutex
+	session *Session
+}
+
+// State is the connection state of a Session.
+type State int
+
+const (
+	StateConnecting State = iota
+	StateConnected
+	StateReconnecting
+	// StateFailed is the state of a session whose stream ended with an error.
+	StateFailed
+	// StateStopped is the state of a Livetail without a running session.
+	StateStopped
+)
+
+// Status describes the running session of a Livetail.
+type Status struct {
+	State State
+	// Err is the error of the last reconnect, or the one that ended the session.
+	Err error
+	// Reconnects counts the streams reopened by the session.
+	Reconnects int
+	// Rate is the number of records received per second over the last seconds.
+	Rate float64
 }
 
-var request = &pb.FilterRequest{
-	DateTimeFilter:    &pb.DateTimeFilter{},
-	FieldBasedFilters: []*pb.FieldBasedFilter{},
-	SearchQueries:     []string{},
-	Sources:           []*pb.Source{},
-	BatchSize:         15,
-	IsScrollDown:      false,
+// Session is one run of the livetail with one set of filters. It owns the context, the request
+// and the FilterService of its stream, so that stopping it leaves nothing running.
+type Session struct {
+	request       *pb.FilterRequest
+	filterService *grpcutil.FilterService
+	cancel        context.CancelFunc
+	done          chan struct{}
+
+	// graph requests the volume of the records of the session, whose date range is set by Volume.
+	graph      *pb.GraphRequest
+	start, end time.Time
+
+	mu         sync.Mutex
+	state      State
+	err        error
+	reconnects int
+	stats      *logformat.Stats
 }
 
 func NewLivetail() (*Livetail, error) {
 	livetail := &Livetail{
-		Logs:          "",
-		sourcesOffset: make(map[string]uint64),
+		Records: NewBuffer(BufferCapacity),
 	}
 
 	return livetail, nil
 }
 
-// Inverse map
-var OperatorToName = map[string]string{
-	":":  pb.FieldBasedFilter_Operator_name[0],
-	"!:": pb.FieldBasedFilter_Operator_name[1],
-	"=":  pb.FieldBasedFilter_Operator_name[2],
-	"!=": pb.FieldBasedFilter_Operator_name[3],
-	">":  pb.FieldBasedFilter_Operator_name[4],
-	">=": pb.FieldBasedFilter_Operator_name[5],
-	"<":  pb.FieldBasedFilter_Operator_name[6],
-	"<=": pb.FieldBasedFilter_Operator_name[7],
+// Start stops the running session, if any, and streams the records matching the filters into
+// Records until Stop or until ctx is done.
+func (l *Livetail) Start(
+	ctx context.Context,
+	cfg config.Config,
+	sourceFilter []string,
+	StartDateTimeFilter time.Time,
+	EndDateTimeFilter time.Time,
+	filter *filters.Query,
+) error {
+	l.Stop()
+
+	request, err := newRequest(cfg, sourceFilter, StartDateTimeFilter, EndDateTimeFilter, filter)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	session := &Session{
+		request:       request,
+		filterService: grpcutil.NewFilterService(cfg),
+		cancel:        cancel,
+		done:          make(chan struct{}),
+		graph:         newGraphRequest(request),
+		start:         StartDateTimeFilter,
+		end:           EndDateTimeFilter,
+		stats:         logformat.NewStats(nil, time.Now()),
+	}
+	session.filterService.OnConnect = func() { session.setState(StateConnected, nil) }
+	session.filterService.OnReconnect = func(err error) {
+		session.mu.Lock()
+		session.reconnects++
+		session.mu.Unlock()
+		session.setState(StateReconnecting, err)
+	}
+
+	go func() {
+		defer close(session.done)
+		defer session.filterService.CloseConnection()
+
+		err := session.filterService.StreamRecords(ctx, request, func(records []*pb.FilteredRecord) error {
+			l.Records.Add(records)
+			session.stats.Add(records, 0, time.Now())
+			return nil
+		})
+		if err != nil {
+			session.setState(StateFailed, err)
+		}
+	}()
+
+	l.mu.Lock()
+	l.session = session
+	l.mu.Unlock()
+	return nil
+}
+
+// Stop ends the running session, if any, and waits for its stream to close.
+func (l *Livetail) Stop() {
+	l.mu.Lock()
+	session := l.session
+	l.session = nil
+	l.mu.Unlock()
+
+	if session != nil {
+		session.cancel()
+		<-session.done
+	}
+}
+
+// Status returns the status of the running session, StateStopped without one.
+func (l *Livetail) Status() Status {
+	l.mu.Lock()
+	session := l.session
+	l.mu.Unlock()
+
+	if session == nil {
+		return Status{State: StateStopped}
+	}
+	rate := session.stats.Rate(time.Now())
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return Status{State: session.state, Err: session.err, Reconnects: session.reconnects, Rate: rate}
+}
+
+// Volume returns the number of records of the running session over time, over its date range or
+// the last hour for a live session. It returns no bucket without a running session.
+func (l *Livetail) Volume(ctx context.Context, cfg config.Config) ([]grpcutil.GraphBucket, error) {
+	l.mu.Lock()
+	session := l.session
+	l.mu.Unlock()
+
+	if session == nil {
+		return nil, nil
+	}
+
+	start, end := session.start, session.end
+	if end.IsZero() {
+		end = time.Now()
+	}
+	if start.IsZero() {
+		start = end.Add(-time.Hour)
+	}
+	request := &pb.GraphRequest{
+		TeamID:            session.graph.TeamID,
+		AccountID:         session.graph.AccountID,
+		Sources:           session.graph.Sources,
+		FieldBasedFilters: session.graph.FieldBasedFilters,
+		DateTimeFilter: &pb.DateTimeFilter{
+			StartTimeStamp: timestamppb.New(start),
+			EndTimeStamp:   timestamppb.New(end),
+		},
+	}
+
+	metaService := grpcutil.NewMetaService(cfg)
+	defer metaService.CloseConnection()
+	return metaService.BarGraph(ctx, request)
+}
+
+func (s *Session) setState(state State, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state, s.err = state, err
 }
 
-func (livetail *Livetail) ApplyFilter(
+// newRequest builds the request of a session from its filters.
+func newRequest(
 	cfg config.Config,
 	sourceFilter []string,
 	StartDateTimeFilter time.Time,
 	EndDateTimeFilter time.Time,
-	FieldBasedFilterName string,
-	FieldBasedFilterValue string,
-	FieldBasedFilterCondition string,
-) {
+	filter *filters.Query,
+) (*pb.FilterRequest, error) {
+	var request = &pb.FilterRequest{
+		DateTimeFilter:    &pb.DateTimeFilter{},
+		FieldBasedFilters: []*pb.FieldBasedFilter{},
+		SearchQueries:     []string{},
+		Sources:           []*pb.Source{},
+		BatchSize:         15,
+		IsScrollDown:      false,
+	}
 
-	client := &http.Client{}
+	client := api.NewClientFromConfig(nil, cfg)
 
 	var sources []models.Source
 
@@ -74,14 +230,21 @@ func (livetail *Livetail) ApplyFilter(
 
 	if sourceFilter != nil {
 		for _, sourceId := range sourceFilter {
-			source, _ := APICalls.GetSource(cfg.Get().Token, cfg.Get().EndPoint, cfg.Get().TeamId, sourceId)
+			source, err := client.GetSource(cfg.Get().TeamId, sourceId)
+			if err != nil {
+				return nil, err
+			}
 			sources = append(sources, source)
 		}
 	} else {
-		sources, _ = APICalls.GetAllSources(client, cfg.Get().Token, cfg.Get().EndPoint, cfg.Get().TeamId)
+		var err error
+		sources, err = client.ListSources(cfg.Get().TeamId)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	livetail.pbSources = createGrpcSource(sources)
+	request.Sources = createGrpcSource(sources)
 
 	if StartDateTimeFilter.IsZero() {
 		request.DateTimeFilter.StartTimeStamp = timestamppb.New(time.Now().Add(-1 * time.Second))
@@ -95,81 +258,41 @@ func (livetail *Livetail) ApplyFilter(
 		}
 	}
 
-	if FieldBasedFilterName != "" && FieldBasedFilterValue != "" && FieldBasedFilterCondition != "" {
-		request.FieldBasedFilters = append(request.FieldBasedFilters, &pb.FieldBasedFilter{
-			FieldName:  FieldBasedFilterName,
-			FieldValue: FieldBasedFilterValue,
-			Operator:   pb.FieldBasedFilter_Operator(pb.FieldBasedFilter_Operator_value[OperatorToName[FieldBasedFilterCondition]]),
-		})
-	} else {
-		request.FieldBasedFilters = []*pb.FieldBasedFilter{}
-	}
-
-}
-
-func (l *Livetail) CreateConnection() {
-	l.FilterService = grpcutil.NewFilterService()
+	filter.Apply(request)
+	return request, nil
 }
 
-func (l *Livetail) GenerateLogs(ctx context.Context, cfg config.Config) {
-	request.Sources = l.pbSources
-	theme := cfg.Get().Theme
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-			response, err := l.FilterService.Client.GetFilteredData(context.Background(), request)
-
-			if err != nil {
-				_, cancel := context.WithCancel(ctx)
-				defer cancel()
-
-				return
-			}
-
-			if len(response.Records) > 0 {
-				sort.Sort(ByOffset(response.Records))
-
-				// Lock the mutex before accessing sourcesOffset
-				l.offsetMutex.Lock()
-				l.sourcesOffset = getOffsets(l.sourcesOffset, response.Records)
-				l.offsetMutex.Unlock()
-
-				// Lock the mutex before accessing pbSources
-				l.offsetMutex.Lock()
-				l.pbSources = addOffset(l.pbSources, l.sourcesOffset)
-				l.offsetMutex.Unlock()
-
-				newLogs := showLogsWithColor(response.Records, theme)
-				l.Logs += newLogs
-			}
-
-			time.Sleep(500 * time.Millisecond)
-		}
+// newGraphRequest counts the records of request. Its sources are copies, as the stream moves the
+// offsets of those of request. Text searches are not part of a GraphRequest, so every record
+// matching the field filters is counted.
+func newGraphRequest(request *pb.FilterRequest) *pb.GraphRequest {
+	graph := &pb.GraphRequest{
+		TeamID:            request.TeamID,
+		AccountID:         request.AccountID,
+		FieldBasedFilters: request.FieldBasedFilters,
+	}
+	for _, source := range request.Sources {
+		graph.Sources = append(graph.Sources, &pb.Source{
+			SourceID:   source.SourceID,
+			SourceName: source.SourceName,
+			TeamID:     source.TeamID,
+		})
 	}
+	return graph
 }
 
-// Convert logs with colors
-func showLogsWithColor(records []*pb.FilteredRecord, theme string) string {
-	stream := ""
-	if theme == "dark" {
-		for _, record := range records {
-			stream += fmt.Sprintf("[yellow]" + record.Dt +
-				"[green] " + record.SourceName +
-				"[blue] " + record.Level + " [white]" +
-				record.Message + "\n")
-		}
-
-	} else {
-		for _, record := range records {
-			stream += fmt.Sprintf(`[gray]%s [purple]%s [blue]%s [black]%s`+"\n",
-				record.Dt, record.SourceName, record.Level, record.Message)
-		}
+// FormatRecord formats record as one line of tview color tags with the colours of palette, the
+// level coloured by its severity like tail colours it.
+func FormatRecord(record *pb.FilteredRecord, palette *theme.Palette) string {
+	message := palette.Message
+	if message == "" {
+		message = palette.UI.Text
 	}
-
-	return stream
+	return fmt.Sprintf("%s%s %s%s %s%s %s%s",
+		palette.Timestamp.Tag(), tview.Escape(record.Dt),
+		palette.Source.Tag(), tview.Escape(record.SourceName),
+		palette.Level(logformat.SeverityName(record.Level)).Tag(), tview.Escape(record.Level),
+		message.Tag(), tview.Escape(record.Message))
 }
 
 // createGrpcSource creates a proper sources to be used in grpc request
@@ -193,15 +316,6 @@ func (a ByOffset) Len() int           { return len(a) }
 func (a ByOffset) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 func (a ByOffset) Less(i, j int) bool { return a[i].Offset < a[j].Offset }
 
-// addOffset adds offset to sources based on the last response received.
-func addOffset(sources []*pb.Source, offset map[string]uint64) []*pb.Source {
-	for _, source := range sources {
-		source.StartingOffset = offset[source.SourceName]
-	}
-
-	return sources
-}
-
 // getFilteredData makes the actual grpc call to connect with flink-service.
 // func getFilteredData(client pb.FilterServiceClient, sources []*pb.Source) (*pb.FilteredRecords, error) {
 // 	// Invoke the gRPC method
@@ -214,12 +328,3 @@ func addOffset(sources []*pb.Source, offset map[string]uint64) []*pb.Source {
 
 // 	return response, nil
 // }
-
-func getOffsets(offsets map[string]uint64, records []*pb.FilteredRecord) map[string]uint64 {
-	for _, record := range records {
-		if offsets[record.SourceName] == 0 || record.Offset >= offsets[record.SourceName] {
-			offsets[record.SourceName] = record.Offset + 1
-		}
-	}
-	return offsets
-}
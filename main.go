@@ -1,12 +1,14 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	surveyCore "github.com/AlecAivazis/survey/v2/core"
 	"github.com/logfire-sh/cli/pkg/cmd/factory"
 	"github.com/logfire-sh/cli/pkg/cmd/root"
+	"github.com/logfire-sh/cli/pkg/cmdutil"
 	"github.com/mgutz/ansi"
 
 	"github.com/spf13/cobra"
@@ -101,7 +103,15 @@ func main() {
 	rootCmd.SetArgs(expandedArgs)
 
 	if err := rootCmd.Execute(); err != nil {
+		var exitErr *cmdutil.ExitError
+		if errors.As(err, &exitErr) {
+			if exitErr.Err != nil {
+				fmt.Fprintf(stderr, "failed to run application: %s\n", exitErr.Err)
+			}
+			os.Exit(exitErr.Code)
+		}
 		fmt.Fprintf(stderr, "failed to run application: %s\n", err)
+		os.Exit(1)
 	}
 
 	// rootCmd := &cobra.Command{
@@ -6,10 +6,10 @@ import (
 	"os"
 
 	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	"github.com/logfire-sh/cli/pkg/cmdutil/helpers"
 	"github.com/logfire-sh/cli/pkg/cmdutil/pre_defined_prompters"
 	"github.com/logfire-sh/cli/pkg/iostreams"
@@ -77,10 +77,10 @@ func CreateAlertRun(opts *CreateAlertOption) {
 		fmt.Fprintf(opts.IO.ErrOut, "%s Failed to read config\n", cs.FailureIcon())
 	}
 
-	client := http.Client{}
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
 
 	if opts.TeamId != "" {
-		teamId := helpers.TeamNameToTeamId(&client, cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+		teamId := helpers.TeamNameToTeamId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 
 		if teamId == "" {
 			fmt.Fprintf(opts.IO.ErrOut, "%s no team with name: %s found.\n", cs.FailureIcon(), opts.TeamId)
@@ -92,7 +92,7 @@ func CreateAlertRun(opts *CreateAlertOption) {
 
 	if opts.Interactive {
 		if opts.TeamId == "" && opts.Name == "" && opts.ViewId == "" && opts.NumberOfRecords == 0 && opts.WithinSeconds == 0 && opts.IntegrationsId == nil {
-			opts.TeamId, _ = pre_defined_prompters.AskTeamId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter)
+			opts.TeamId, _ = pre_defined_prompters.AskTeamId(client, opts.IO, cs, opts.Prompter)
 
 			opts.Name, err = opts.Prompter.Input("Enter a name for the alert:", "")
 			if err != nil {
@@ -100,7 +100,7 @@ func CreateAlertRun(opts *CreateAlertOption) {
 				return
 			}
 
-			opts.ViewId, _ = pre_defined_prompters.AskViewId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+			opts.ViewId, _ = pre_defined_prompters.AskViewId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 
 			nor, err := opts.Prompter.InputInt("Enter a Number at when alerts should be triggered.", 0)
 			opts.NumberOfRecords = uint32(nor)
@@ -116,7 +116,7 @@ func CreateAlertRun(opts *CreateAlertOption) {
 				return
 			}
 
-			opts.IntegrationsId, _ = pre_defined_prompters.AskAlertIntegrationIds(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+			opts.IntegrationsId, _ = pre_defined_prompters.AskAlertIntegrationIds(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 
 		}
 	} else {
@@ -180,7 +180,7 @@ func CreateAlertRun(opts *CreateAlertOption) {
 		os.Exit(0)
 	}
 
-	err = APICalls.CreateAlert(opts.HttpClient(), cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId,
+	err = client.CreateAlert(opts.TeamId,
 		opts.Name, opts.ViewId, opts.NumberOfRecords, opts.WithinSeconds, opts.IntegrationsId)
 	if err != nil {
 		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
@@ -6,10 +6,10 @@ import (
 	"os"
 
 	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	"github.com/logfire-sh/cli/pkg/cmdutil/helpers"
 	"github.com/logfire-sh/cli/pkg/cmdutil/pre_defined_prompters"
 	"github.com/logfire-sh/cli/pkg/iostreams"
@@ -68,10 +68,10 @@ func DeleteAlertRun(opts *DeleteAlertOptions) {
 		fmt.Fprintf(opts.IO.ErrOut, "%s Failed to read config\n", cs.FailureIcon())
 	}
 
-	client := http.Client{}
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
 
 	if opts.TeamId != "" {
-		teamId := helpers.TeamNameToTeamId(&client, cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+		teamId := helpers.TeamNameToTeamId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 
 		if teamId == "" {
 			fmt.Fprintf(opts.IO.ErrOut, "%s no team with name: %s found.\n", cs.FailureIcon(), opts.TeamId)
@@ -82,9 +82,9 @@ func DeleteAlertRun(opts *DeleteAlertOptions) {
 	}
 
 	if opts.Interactive && opts.TeamId == "" && opts.AlertId == nil {
-		opts.TeamId, _ = pre_defined_prompters.AskTeamId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter)
+		opts.TeamId, _ = pre_defined_prompters.AskTeamId(client, opts.IO, cs, opts.Prompter)
 
-		opts.AlertId, _ = pre_defined_prompters.AskAlertIds(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+		opts.AlertId, _ = pre_defined_prompters.AskAlertIds(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 	} else {
 		if opts.TeamId == "" {
 			opts.TeamId = cfg.Get().TeamId
@@ -97,7 +97,7 @@ func DeleteAlertRun(opts *DeleteAlertOptions) {
 
 	}
 
-	err = APICalls.DeleteAlert(opts.HttpClient(), cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId,
+	err = client.DeleteAlerts(opts.TeamId,
 		opts.AlertId)
 	if err != nil {
 		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
@@ -6,10 +6,10 @@ import (
 	"os"
 
 	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	"github.com/logfire-sh/cli/pkg/cmdutil/helpers"
 	"github.com/logfire-sh/cli/pkg/cmdutil/pre_defined_prompters"
 	"github.com/logfire-sh/cli/pkg/iostreams"
@@ -23,6 +23,7 @@ type ListAlertOptions struct {
 
 	HttpClient func() *http.Client
 	Config     func() (config.Config, error)
+	Exporter   *cmdutil.Exporter
 
 	Interactive bool
 	TeamId      string
@@ -35,6 +36,7 @@ func NewListAlertCmd(f *cmdutil.Factory) *cobra.Command {
 
 		HttpClient: f.HttpClient,
 		Config:     f.Config,
+		Exporter:   f.Exporter,
 	}
 
 	cmd := &cobra.Command{
@@ -57,6 +59,9 @@ func NewListAlertCmd(f *cmdutil.Factory) *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVarP(&opts.TeamId, "team-name", "t", "", "Team name from which alerts are to be listed.")
+
+	cmdutil.EnableExport(cmd)
+
 	return cmd
 }
 
@@ -67,10 +72,10 @@ func ListAlertRun(opts *ListAlertOptions) {
 		fmt.Fprintf(opts.IO.ErrOut, "%s Failed to read config\n", cs.FailureIcon())
 	}
 
-	client := http.Client{}
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
 
 	if opts.TeamId != "" {
-		teamId := helpers.TeamNameToTeamId(&client, cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+		teamId := helpers.TeamNameToTeamId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 
 		if teamId == "" {
 			fmt.Fprintf(opts.IO.ErrOut, "%s no team with name: %s found.\n", cs.FailureIcon(), opts.TeamId)
@@ -82,7 +87,7 @@ func ListAlertRun(opts *ListAlertOptions) {
 
 	if opts.Interactive {
 		if opts.TeamId == "" {
-			opts.TeamId, _ = pre_defined_prompters.AskTeamId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter)
+			opts.TeamId, _ = pre_defined_prompters.AskTeamId(client, opts.IO, cs, opts.Prompter)
 		}
 	} else {
 		if opts.TeamId == "" {
@@ -90,9 +95,13 @@ func ListAlertRun(opts *ListAlertOptions) {
 		}
 	}
 
-	data, err := APICalls.ListAlert(opts.HttpClient(), cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId)
+	data, err := client.ListAlerts(opts.TeamId)
 	if err != nil {
 		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+	} else if opts.Exporter.Enabled() {
+		if err := opts.Exporter.Write(opts.IO.Out, data); err != nil {
+			fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+		}
 	} else if len(data) > 0 {
 		table := tablewriter.NewWriter(os.Stdout)
 		table.SetHeader([]string{"Name", "Alert-Id"})
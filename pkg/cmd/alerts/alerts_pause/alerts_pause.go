@@ -6,10 +6,10 @@ import (
 	"os"
 
 	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	"github.com/logfire-sh/cli/pkg/cmdutil/helpers"
 	"github.com/logfire-sh/cli/pkg/cmdutil/pre_defined_prompters"
 	"github.com/logfire-sh/cli/pkg/iostreams"
@@ -70,10 +70,10 @@ func PauseAlertRun(opts *PauseAlertOptions) {
 		fmt.Fprintf(opts.IO.ErrOut, "%s Failed to read config\n", cs.FailureIcon())
 	}
 
-	client := http.Client{}
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
 
 	if opts.TeamId != "" {
-		teamId := helpers.TeamNameToTeamId(&client, cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+		teamId := helpers.TeamNameToTeamId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 
 		if teamId == "" {
 			fmt.Fprintf(opts.IO.ErrOut, "%s no team with name: %s found.\n", cs.FailureIcon(), opts.TeamId)
@@ -84,8 +84,8 @@ func PauseAlertRun(opts *PauseAlertOptions) {
 	}
 
 	if opts.Interactive && opts.TeamId == "" && len(opts.AlertId) == 0 {
-		opts.TeamId, _ = pre_defined_prompters.AskTeamId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter)
-		opts.AlertId, _ = pre_defined_prompters.AskAlertIds(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+		opts.TeamId, _ = pre_defined_prompters.AskTeamId(client, opts.IO, cs, opts.Prompter)
+		opts.AlertId, _ = pre_defined_prompters.AskAlertIds(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 
 		if len(opts.AlertId) == 0 {
 			fmt.Fprintf(opts.IO.ErrOut, "%s No alerts to pause/unpause\n", cs.FailureIcon())
@@ -104,7 +104,7 @@ func PauseAlertRun(opts *PauseAlertOptions) {
 		}
 	}
 
-	err = APICalls.PauseAlert(opts.HttpClient(), cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId,
+	err = client.PauseAlerts(opts.TeamId,
 		opts.AlertId, opts.AlertPause)
 	if err != nil {
 		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
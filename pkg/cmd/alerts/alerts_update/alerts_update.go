@@ -6,10 +6,10 @@ import (
 	"os"
 
 	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	"github.com/logfire-sh/cli/pkg/cmdutil/helpers"
 	"github.com/logfire-sh/cli/pkg/cmdutil/pre_defined_prompters"
 	"github.com/logfire-sh/cli/pkg/iostreams"
@@ -78,10 +78,10 @@ func UpdateMemberRun(opts *AlertUpdateOptions) {
 		fmt.Fprintf(opts.IO.ErrOut, "%s Failed to read config\n", cs.FailureIcon())
 	}
 
-	client := http.Client{}
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
 
 	if opts.TeamId != "" {
-		teamId := helpers.TeamNameToTeamId(&client, cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+		teamId := helpers.TeamNameToTeamId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 
 		if teamId == "" {
 			fmt.Fprintf(opts.IO.ErrOut, "%s no team with name: %s found.\n", cs.FailureIcon(), opts.TeamId)
@@ -92,9 +92,9 @@ func UpdateMemberRun(opts *AlertUpdateOptions) {
 	}
 
 	if opts.Interactive && opts.TeamId == "" && opts.AlertId == "" {
-		opts.TeamId, _ = pre_defined_prompters.AskTeamId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter)
+		opts.TeamId, _ = pre_defined_prompters.AskTeamId(client, opts.IO, cs, opts.Prompter)
 
-		opts.AlertId, _ = pre_defined_prompters.AskAlertId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+		opts.AlertId, _ = pre_defined_prompters.AskAlertId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 
 		updateName, _ := opts.Prompter.Confirm("Do you want to update the alert name?", false)
 		if updateName {
@@ -103,7 +103,7 @@ func UpdateMemberRun(opts *AlertUpdateOptions) {
 
 		updateView, _ := opts.Prompter.Confirm("Do you want to update the view?", false)
 		if updateView {
-			opts.ViewId, _ = pre_defined_prompters.AskViewId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+			opts.ViewId, _ = pre_defined_prompters.AskViewId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 		}
 
 		updateNOR, _ := opts.Prompter.Confirm("Do you want to update the number of records?", false)
@@ -120,7 +120,7 @@ func UpdateMemberRun(opts *AlertUpdateOptions) {
 
 		updateIntegrations, _ := opts.Prompter.Confirm("Do you want to update the integrations?", false)
 		if updateIntegrations {
-			opts.IntegrationsId, _ = pre_defined_prompters.AskAlertIntegrationIds(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+			opts.IntegrationsId, _ = pre_defined_prompters.AskAlertIntegrationIds(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 		}
 	} else {
 		if opts.TeamId == "" {
@@ -134,8 +134,8 @@ func UpdateMemberRun(opts *AlertUpdateOptions) {
 		}
 	}
 
-	err = APICalls.UpdateAlert(opts.HttpClient(), cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId,
-		opts.Name, opts.ViewId, opts.NumberOfRecords, opts.WithinSeconds, opts.IntegrationsId, opts.AlertId)
+	err = client.UpdateAlert(opts.TeamId, opts.AlertId,
+		opts.Name, opts.ViewId, opts.NumberOfRecords, opts.WithinSeconds, opts.IntegrationsId)
 	if err != nil {
 		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
 	} else {
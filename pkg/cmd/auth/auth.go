@@ -3,10 +3,11 @@ package auth
 import (
 	"github.com/logfire-sh/cli/pkg/cmd/auth/login"
 	"github.com/logfire-sh/cli/pkg/cmd/auth/signup"
+	"github.com/logfire-sh/cli/pkg/cmdutil"
 	"github.com/spf13/cobra"
 )
 
-func NewCmdAuth() *cobra.Command {
+func NewCmdAuth(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "auth <command>",
 		Short:   "Authenticate logfire.sh",
@@ -14,8 +15,8 @@ func NewCmdAuth() *cobra.Command {
 	}
 
 	disableAuthCheck(cmd)
-	cmd.AddCommand(login.NewLoginCmd())
-	cmd.AddCommand(signup.NewSignupCmd())
+	cmd.AddCommand(login.NewLoginCmd(f))
+	cmd.AddCommand(signup.NewSignupCmd(f))
 
 	return cmd
 }
@@ -1,39 +1,34 @@
 package login
 
 import (
-	"bytes"
-	"encoding/json"
-	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/http"
-	"strings"
 
 	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
-	"github.com/logfire-sh/cli/pkg/cmd/auth/models"
+	"github.com/logfire-sh/cli/pkg/cmdutil"
 	"github.com/logfire-sh/cli/pkg/iostreams"
 	"github.com/spf13/cobra"
 )
 
 type LoginOptions struct {
 	IO       *iostreams.IOStreams
-	Config   config.Config
 	Prompter prompter.Prompter
 
+	HttpClient func() *http.Client
+	Config     func() (config.Config, error)
+
 	Interactive bool
 }
 
-func NewLoginCmd() *cobra.Command {
-	io := iostreams.System()
-	cfg, _ := config.NewConfig()
-	prmpt := prompter.New(io.In, io.Out, io.ErrOut)
-
+func NewLoginCmd(f *cmdutil.Factory) *cobra.Command {
 	opts := &LoginOptions{
-		IO:       io,
-		Config:   *cfg,
-		Prompter: prmpt,
+		IO:         f.IOStreams,
+		Prompter:   f.Prompter,
+		HttpClient: f.HttpClient,
+		Config:     f.Config,
 	}
 
 	cmd := &cobra.Command{
@@ -70,14 +65,13 @@ func NewLoginCmd() *cobra.Command {
 	return cmd
 }
 
-type SigninRequest struct {
-	Email      string `json:"email,omitempty"`
-	AuthType   int    `json:"authType"`
-	Credential string `json:"credential"`
-}
-
 func loginRun(opts *LoginOptions) {
 	cs := opts.IO.ColorScheme()
+	cfg, err := opts.Config()
+	if err != nil {
+		fmt.Fprintf(opts.IO.ErrOut, "%s Failed to read config\n", cs.FailureIcon())
+		return
+	}
 
 	email, err := opts.Prompter.Input("Enter your email:", "")
 	if err != nil {
@@ -93,93 +87,16 @@ func loginRun(opts *LoginOptions) {
 
 	opts.IO.StartProgressIndicatorWithLabel("Logging in to logfire.sh")
 
-	resp, err := PasswordSignin(email, password)
+	resp, err := api.NewClientFromConfig(opts.HttpClient(), cfg).SignInWithPassword(email, password)
 	opts.IO.StopProgressIndicator()
 	if err != nil {
 		fmt.Fprintf(opts.IO.ErrOut, "\n%s Signin Failed. %s\n", cs.FailureIcon(), err.Error())
 		return
 	}
 
-	opts.Config.UpdateConfig(email, resp.BearerToken.AccessToken)
-	fmt.Fprintf(opts.IO.Out, "\n%s Logged in as %s\n", cs.SuccessIcon(), cs.Bold(email))
-}
-
-func PasswordSignin(email, password string) (models.Response, error) {
-	var response models.Response
-	signinReq := SigninRequest{
-		Email:      email,
-		AuthType:   2,
-		Credential: password,
-	}
-
-	reqBody, err := json.Marshal(signinReq)
-	if err != nil {
-		return response, err
-	}
-
-	url := "https://api.logfire.sh/api/auth/signin"
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(reqBody))
-	if err != nil {
-		return response, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return response, errors.New("wrong password")
-	}
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return response, err
-	}
-
-	err = json.Unmarshal(body, &response)
-	if err != nil {
-		return response, err
-	}
-
-	return response, nil
-}
-
-func TokenSignin(token string) (models.Response, error) {
-	var response models.Response
-
-	signinReq := SigninRequest{
-		AuthType:   1,
-		Credential: strings.TrimSpace(token),
-	}
-
-	reqBody, err := json.Marshal(signinReq)
-	if err != nil {
-		fmt.Printf("Failed to marshal request body: %v\n", err)
-		return response, err
-	}
-
-	url := "https://api.logfire.sh/api/auth/signin"
-
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(reqBody))
-	if err != nil {
-		fmt.Printf("Failed to send POST request: %v\n", err)
-		return response, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode == http.StatusOK {
-		fmt.Println("Sign-in successful!")
-	} else {
-		fmt.Printf("Sign-in failed with status code: %d\n", resp.StatusCode)
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		fmt.Printf("Failed to read response body: %v\n", err)
-		return response, err
-	}
-
-	err = json.Unmarshal(body, &response)
-	if err != nil {
-		fmt.Printf("Failed to unmarshal JSON: %v\n", err)
-		return response, err
+	if err := api.SaveSession(cfg, resp); err != nil {
+		fmt.Fprintf(opts.IO.ErrOut, "\n%s Failed to save the session. %s\n", cs.FailureIcon(), err.Error())
+		return
 	}
-
-	return response, nil
+	fmt.Fprintf(opts.IO.Out, "\n%s Logged in as %s\n", cs.SuccessIcon(), cs.Bold(email))
 }
@@ -1,39 +1,35 @@
 package signup
 
 import (
-	"bytes"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
-	"time"
 
 	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
-	"github.com/logfire-sh/cli/pkg/cmd/auth/login"
-	"github.com/logfire-sh/cli/pkg/cmd/auth/models"
+	"github.com/logfire-sh/cli/pkg/cmdutil"
 	"github.com/logfire-sh/cli/pkg/iostreams"
 	"github.com/spf13/cobra"
 )
 
 type SignupOptions struct {
 	IO       *iostreams.IOStreams
-	Config   config.Config
 	Prompter prompter.Prompter
 
+	HttpClient func() *http.Client
+	Config     func() (config.Config, error)
+
 	Interactive bool
 }
 
-func NewSignupCmd() *cobra.Command {
-	io := iostreams.System()
-	cfg, _ := config.NewConfig()
-	prmpt := prompter.New(io.In, io.Out, io.ErrOut)
-
+func NewSignupCmd(f *cmdutil.Factory) *cobra.Command {
 	opts := &SignupOptions{
-		IO:       io,
-		Config:   *cfg,
-		Prompter: prmpt,
+		IO:         f.IOStreams,
+		Prompter:   f.Prompter,
+		HttpClient: f.HttpClient,
+		Config:     f.Config,
 	}
 
 	cmd := &cobra.Command{
@@ -60,6 +56,12 @@ func NewSignupCmd() *cobra.Command {
 
 func signupRun(opts *SignupOptions) {
 	cs := opts.IO.ColorScheme()
+	cfg, err := opts.Config()
+	if err != nil {
+		fmt.Fprintf(opts.IO.ErrOut, "%s Failed to read config\n", cs.FailureIcon())
+		return
+	}
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
 
 	email, err := opts.Prompter.Input("Enter your email:", "")
 	if err != nil {
@@ -67,8 +69,7 @@ func signupRun(opts *SignupOptions) {
 		return
 	}
 
-	err = SignupFlow(email)
-	if err != nil {
+	if _, err := client.Signup(email); err != nil {
 		fmt.Fprintf(opts.IO.ErrOut, "\n%s Error while signing up %s", cs.FailureIcon(), err.Error())
 		return
 	}
@@ -81,61 +82,27 @@ func signupRun(opts *SignupOptions) {
 		return
 	}
 
-	resp, err := login.TokenSignin(credentialToken)
+	resp, err := client.SignInWithToken(credentialToken)
 	if err != nil {
 		fmt.Fprintf(opts.IO.ErrOut, "\n%s Error while signing up with the token %s", cs.FailureIcon(), err.Error())
 		return
 	}
-
-	opts.Config.UpdateConfig(email, resp.BearerToken.AccessToken)
-
-	err = OnboardingFlow(opts.IO, opts.Prompter, resp.UserBody.ProfileID, opts.Config.AuthConfig.Token)
-	if err != nil {
+	if err := api.SaveSession(cfg, resp); err != nil {
 		fmt.Fprintf(opts.IO.ErrOut, "\n%s %s", cs.FailureIcon(), err.Error())
 		return
 	}
 
-	fmt.Fprintf(opts.IO.Out, "%s User onboarded successfully.\n", cs.SuccessIcon())
-}
-
-func SignupFlow(email string) error {
-	signupReq := models.SignupRequest{
-		Email: email,
-	}
-
-	reqBody, err := json.Marshal(signupReq)
+	err = OnboardingFlow(opts.IO, opts.Prompter, client, resp.UserBody.ProfileID)
 	if err != nil {
-		return err
-	}
-
-	url := "https://api.logfire.sh/api/auth/signup"
-
-	transport := http.Transport{
-		IdleConnTimeout:   30 * time.Second,
-		MaxIdleConns:      100,
-		MaxConnsPerHost:   0,
-		DisableKeepAlives: false,
-	}
-
-	client := http.Client{
-		Transport: &transport,
-		Timeout:   10 * time.Second,
-	}
-
-	resp, err := client.Post(url, "application/json", bytes.NewBuffer(reqBody))
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusCreated {
-		return err
+		fmt.Fprintf(opts.IO.ErrOut, "\n%s %s", cs.FailureIcon(), err.Error())
+		return
 	}
 
-	return nil
+	fmt.Fprintf(opts.IO.Out, "%s User onboarded successfully.\n", cs.SuccessIcon())
 }
 
-func OnboardingFlow(IO *iostreams.IOStreams, prompt prompter.Prompter, profileID, authToken string) error {
+// OnboardingFlow asks for the name and the password of a new profile, signed in with client.
+func OnboardingFlow(IO *iostreams.IOStreams, prompt prompter.Prompter, client *api.Client, profileID string) error {
 	cs := IO.ColorScheme()
 
 	firstName, err := prompt.Input("Enter your first name:", "")
@@ -148,36 +115,9 @@ func OnboardingFlow(IO *iostreams.IOStreams, prompt prompter.Prompter, profileID
 		return err
 	}
 
-	onboardReq := models.OnboardRequest{
-		FirstName: firstName,
-		LastName:  lastName,
-	}
-
-	reqBody, err := json.Marshal(onboardReq)
-	if err != nil {
-		fmt.Printf("Failed to marshal request body: %v\n", err)
-		return err
-	}
-
-	url := "https://api.logfire.sh/api/profile/" + profileID + "/onboard"
-
-	client := &http.Client{}
-
-	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(reqBody))
-	if err != nil {
-		return err
-	}
-
-	req.Header.Set("Authorization", "Bearer "+authToken)
-	resp, err := client.Do(req)
-	if err != nil {
+	if err := client.Onboard(profileID, firstName, lastName); err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusAccepted {
-		return errors.New("unable to process the request")
-	}
 
 	var password string
 	var isConfirmed bool
@@ -205,32 +145,5 @@ func OnboardingFlow(IO *iostreams.IOStreams, prompt prompter.Prompter, profileID
 		return errors.New("maximum number of attempts exceeded")
 	}
 
-	pwdReq := models.SetPassword{
-		Password: password,
-	}
-
-	reqBody, err = json.Marshal(pwdReq)
-	if err != nil {
-		return err
-	}
-
-	url = "https://api.logfire.sh/api/profile/" + profileID + "/set-password"
-
-	req, err = http.NewRequest("POST", url, bytes.NewBuffer(reqBody))
-	if err != nil {
-		return err
-	}
-
-	req.Header.Set("Authorization", "Bearer "+authToken)
-	resp, err = client.Do(req)
-
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusAccepted {
-		return errors.New("unable to set password, please try again later")
-	}
-	return nil
+	return client.SetPassword(profileID, password)
 }
@@ -0,0 +1,41 @@
+package cli_config
+
+import (
+	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/pkg/cmd/cli_config/config_get"
+	"github.com/logfire-sh/cli/pkg/cmd/cli_config/config_list_contexts"
+	"github.com/logfire-sh/cli/pkg/cmd/cli_config/config_set"
+	"github.com/logfire-sh/cli/pkg/cmd/cli_config/config_use_context"
+	"github.com/logfire-sh/cli/pkg/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdConfig(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config <command>",
+		Short: "Manage CLI configuration and contexts",
+		Long: heredoc.Doc(`
+			Manage the CLI configuration stored in ~/.logfire.
+
+			A context holds its own endpoints, token and default team, so one CLI can
+			switch between accounts or self-hosted installations. Use the global
+			--profile flag to run a single command against another context.
+		`),
+		Example: heredoc.Doc(`
+			$ logfire config list-contexts
+			$ logfire config use-context staging
+			$ logfire --profile staging login
+			$ logfire config set endpoint https://logfire.example.com/
+			$ logfire config get team_id
+		`),
+		Annotations: map[string]string{
+			"skipAuthCheck": "true",
+		},
+	}
+
+	cmd.AddCommand(config_use_context.NewUseContextCmd(f))
+	cmd.AddCommand(config_list_contexts.NewListContextsCmd(f))
+	cmd.AddCommand(config_get.NewGetCmd(f))
+	cmd.AddCommand(config_set.NewSetCmd(f))
+	return cmd
+}
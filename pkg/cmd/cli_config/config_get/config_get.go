@@ -0,0 +1,63 @@
+package config_get
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/internal/config"
+	"github.com/logfire-sh/cli/pkg/cmdutil"
+	"github.com/logfire-sh/cli/pkg/iostreams"
+	"github.com/spf13/cobra"
+)
+
+type GetOptions struct {
+	IO     *iostreams.IOStreams
+	Config func() (config.Config, error)
+
+	Key string
+}
+
+func NewGetCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &GetOptions{
+		IO:     f.IOStreams,
+		Config: f.Config,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "get <key>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Print a value from the current context",
+		Long: heredoc.Docf(`
+			Print a configuration value from the current context.
+
+			Available keys: %s
+		`, strings.Join(config.Keys, ", ")),
+		Example: heredoc.Doc(`
+			$ logfire config get endpoint
+			$ logfire --profile staging config get team_id
+		`),
+		ValidArgs: config.Keys,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Key = args[0]
+			return getRun(opts)
+		},
+	}
+
+	return cmd
+}
+
+func getRun(opts *GetOptions) error {
+	cfg, err := opts.Config()
+	if err != nil {
+		return err
+	}
+
+	value, err := cfg.GetValue(opts.Key)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(opts.IO.Out, value)
+	return nil
+}
@@ -0,0 +1,101 @@
+package config_list_contexts
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/internal/config"
+	"github.com/logfire-sh/cli/pkg/cmdutil"
+	"github.com/logfire-sh/cli/pkg/iostreams"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+type ListContextsOptions struct {
+	IO       *iostreams.IOStreams
+	Config   func() (config.Config, error)
+	Exporter *cmdutil.Exporter
+}
+
+type contextSummary struct {
+	Name         string `json:"name"`
+	Current      bool   `json:"current"`
+	Username     string `json:"username"`
+	Endpoint     string `json:"endpoint"`
+	GrpcEndpoint string `json:"grpc_endpoint"`
+	TeamId       string `json:"team_id"`
+}
+
+func NewListContextsCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &ListContextsOptions{
+		IO:       f.IOStreams,
+		Config:   f.Config,
+		Exporter: f.Exporter,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "list-contexts",
+		Args:  cobra.ExactArgs(0),
+		Short: "List the saved contexts",
+		Example: heredoc.Doc(`
+			$ logfire config list-contexts
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listContextsRun(opts)
+		},
+	}
+
+	cmdutil.EnableExport(cmd)
+
+	return cmd
+}
+
+func listContextsRun(opts *ListContextsOptions) error {
+	cs := opts.IO.ColorScheme()
+	cfg, err := opts.Config()
+	if err != nil {
+		return err
+	}
+
+	var contexts []contextSummary
+	for _, name := range cfg.ContextNames() {
+		// Show what is stored, without the environment overrides.
+		contextCfg, err := config.Load(config.Options{Context: name})
+		if err != nil {
+			return err
+		}
+
+		contexts = append(contexts, contextSummary{
+			Name:         name,
+			Current:      name == cfg.CurrentContext(),
+			Username:     contextCfg.Get().Username,
+			Endpoint:     contextCfg.Get().EndPoint,
+			GrpcEndpoint: contextCfg.Get().GrpcEndpoint,
+			TeamId:       contextCfg.Get().TeamId,
+		})
+	}
+
+	if opts.Exporter.Enabled() {
+		return opts.Exporter.Write(opts.IO.Out, contexts)
+	}
+
+	if len(contexts) == 0 {
+		fmt.Fprintf(opts.IO.ErrOut, "%s No contexts saved. Run logfire login to create one\n", cs.FailureIcon())
+		return nil
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Current", "Name", "User", "Endpoint", "Team-Id"})
+
+	for _, c := range contexts {
+		current := ""
+		if c.Current {
+			current = "*"
+		}
+		table.Append([]string{current, c.Name, c.Username, c.Endpoint, c.TeamId})
+	}
+
+	table.Render()
+	return nil
+}
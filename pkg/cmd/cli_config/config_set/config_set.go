@@ -0,0 +1,70 @@
+package config_set
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/internal/config"
+	"github.com/logfire-sh/cli/pkg/cmdutil"
+	"github.com/logfire-sh/cli/pkg/iostreams"
+	"github.com/spf13/cobra"
+)
+
+type SetOptions struct {
+	IO     *iostreams.IOStreams
+	Config func() (config.Config, error)
+
+	Key   string
+	Value string
+}
+
+func NewSetCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &SetOptions{
+		IO:     f.IOStreams,
+		Config: f.Config,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "set <key> <value>",
+		Args:  cobra.ExactArgs(2),
+		Short: "Update a value in the current context",
+		Long: heredoc.Docf(`
+			Update a configuration value in the current context. The context is created
+			if it does not exist yet.
+
+			Available keys: %s
+		`, strings.Join(config.Keys, ", ")),
+		Example: heredoc.Doc(`
+			$ logfire config set theme light
+			$ logfire --profile self-hosted config set endpoint https://logfire.example.com/
+			$ logfire --profile self-hosted config set grpc_endpoint logfire.example.com:443
+		`),
+		ValidArgs: config.Keys,
+		Annotations: map[string]string{
+			"createContext": "true",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Key = args[0]
+			opts.Value = args[1]
+			return setRun(opts)
+		},
+	}
+
+	return cmd
+}
+
+func setRun(opts *SetOptions) error {
+	cs := opts.IO.ColorScheme()
+	cfg, err := opts.Config()
+	if err != nil {
+		return err
+	}
+
+	if err := cfg.SetValue(opts.Key, opts.Value); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(opts.IO.Out, "%s Set %s in context %s\n", cs.SuccessIcon(), opts.Key, cs.Bold(cfg.CurrentContext()))
+	return nil
+}
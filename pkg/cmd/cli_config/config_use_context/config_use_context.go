@@ -0,0 +1,55 @@
+package config_use_context
+
+import (
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/internal/config"
+	"github.com/logfire-sh/cli/pkg/cmdutil"
+	"github.com/logfire-sh/cli/pkg/iostreams"
+	"github.com/spf13/cobra"
+)
+
+type UseContextOptions struct {
+	IO     *iostreams.IOStreams
+	Config func() (config.Config, error)
+
+	Name string
+}
+
+func NewUseContextCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &UseContextOptions{
+		IO:     f.IOStreams,
+		Config: f.Config,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "use-context <name>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Switch the current context",
+		Example: heredoc.Doc(`
+			$ logfire config use-context production
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Name = args[0]
+			return useContextRun(opts)
+		},
+	}
+
+	return cmd
+}
+
+func useContextRun(opts *UseContextOptions) error {
+	cs := opts.IO.ColorScheme()
+	cfg, err := opts.Config()
+	if err != nil {
+		return err
+	}
+
+	if err := cfg.UseContext(opts.Name); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(opts.IO.Out, "%s Switched to context %s\n", cs.SuccessIcon(), cs.Bold(opts.Name))
+	return nil
+}
@@ -0,0 +1,20 @@
+package dev
+
+import (
+	"github.com/logfire-sh/cli/pkg/cmd/dev/dev_server"
+	"github.com/logfire-sh/cli/pkg/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdDev(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dev <command>",
+		Short: "Tools for developing against Logfire offline",
+		Annotations: map[string]string{
+			"skipAuthCheck": "true",
+		},
+	}
+
+	cmd.AddCommand(dev_server.NewDevServerCmd(f))
+	return cmd
+}
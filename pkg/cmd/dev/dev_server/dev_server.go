@@ -0,0 +1,102 @@
+package dev_server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/internal/devserver"
+	"github.com/logfire-sh/cli/pkg/cmdutil"
+	"github.com/logfire-sh/cli/pkg/iostreams"
+	"github.com/spf13/cobra"
+)
+
+type DevServerOptions struct {
+	IO *iostreams.IOStreams
+
+	HTTPAddr string
+	GRPCAddr string
+	DataFile string
+}
+
+func NewDevServerCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &DevServerOptions{
+		IO: f.IOStreams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "server",
+		Args:  cobra.NoArgs,
+		Short: "Run a local mock of the Logfire API",
+		Long: heredoc.Docf(`
+			Run a local stand-in for the Logfire API until interrupted.
+
+			The server answers the REST routes used by the CLI, accepts logs on its ingest
+			endpoint and serves the FilterService and MetaService gRPC services from the
+			ingested records. Everything is kept in memory, or in the JSON file given with
+			--data so it survives restarts.
+
+			A new server has the user %[1]s%[2]s%[1]s with password %[1]s%[3]s%[1]s, the team %[1]s%[4]s%[1]s
+			and the source %[1]s%[5]s%[1]s. Point the CLI at the server with the environment variables
+			it prints, and sign in under a separate --profile to keep the session of your
+			account. Queries sent with %[1]slogfire sql%[1]s are not interpreted; they return the
+			records of the sources in the date range.
+		`, "`", devserver.DevEmail, devserver.DevPassword, devserver.DevTeam, devserver.DevSource),
+		Example: heredoc.Doc(`
+			$ logfire dev server --data ./logfire-dev.json
+			$ export LOGFIRE_ENDPOINT=http://127.0.0.1:8080/ LOGFIRE_GRPC_ENDPOINT=127.0.0.1:50051
+			$ logfire --profile dev login --email dev@logfire.local --password logfire
+			$ logfire --profile dev tail
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return devServerRun(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.HTTPAddr, "http-addr", "127.0.0.1:8080", "Address of the REST API and ingest endpoint.")
+	cmd.Flags().StringVar(&opts.GRPCAddr, "grpc-addr", "127.0.0.1:50051", "Address of the gRPC services.")
+	cmd.Flags().StringVar(&opts.DataFile, "data", "", "JSON file keeping the data between runs (Default: in memory).")
+
+	return cmd
+}
+
+func devServerRun(opts *DevServerOptions) error {
+	cs := opts.IO.ColorScheme()
+
+	store := devserver.NewStore()
+	if opts.DataFile != "" {
+		var err error
+		store, err = devserver.OpenStore(opts.DataFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	server := devserver.New(store)
+	if err := server.Start(opts.HTTPAddr, opts.GRPCAddr); err != nil {
+		return err
+	}
+	defer server.Close()
+
+	fmt.Fprintf(opts.IO.ErrOut, "%s Logfire dev server running, use it with:\n\n", cs.SuccessIcon())
+	fmt.Fprintf(opts.IO.Out, "export LOGFIRE_ENDPOINT=%s\n", server.Endpoint())
+	fmt.Fprintf(opts.IO.Out, "export LOGFIRE_GRPC_ENDPOINT=%s\n", server.GrpcEndpoint())
+	fmt.Fprintf(opts.IO.Out, "export LOGFIRE_GRPC_INGESTION=%s\n", server.IngestionEndpoint())
+
+	fmt.Fprintf(opts.IO.ErrOut, "\nSign in with: logfire --profile dev login --email %s --password %s\n", devserver.DevEmail, devserver.DevPassword)
+	for _, team := range store.ListTeams() {
+		for _, source := range store.ListSources(team.ID) {
+			fmt.Fprintf(opts.IO.ErrOut, "Source %s of team %s has the token %s\n", cs.Bold(source.Name), cs.Bold(team.Name), source.SourceToken)
+		}
+	}
+	fmt.Fprintf(opts.IO.ErrOut, "Press Ctrl+C to stop.\n")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	<-ctx.Done()
+
+	fmt.Fprintf(opts.IO.ErrOut, "\n%s Stopped the dev server\n", cs.SuccessIcon())
+	return nil
+}
@@ -1,45 +1,74 @@
 package factory
 
 import (
+	"fmt"
 	"net/http"
 	"time"
 
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
+	"github.com/logfire-sh/cli/internal/debug"
 	"github.com/logfire-sh/cli/internal/prompter"
+	"github.com/logfire-sh/cli/internal/theme"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
 	"github.com/logfire-sh/cli/pkg/iostreams"
 )
 
 func New() *cmdutil.Factory {
 	f := &cmdutil.Factory{
-		Config: configFunc(), // No factory dependencies
+		Exporter: &cmdutil.Exporter{},
 	}
 
-	f.IOStreams = ioStreams()       // No dependencies
-	f.HttpClient = httpClientFunc() // No dependencies
-	f.Prompter = newPrompter(f)     // Depends on IOStreams
+	f.Config = configFunc(f)         // Depends on Profile and ConfigOverrides
+	f.IOStreams = ioStreams()        // No dependencies
+	f.HttpClient = httpClientFunc(f) // Depends on Config
+	f.Prompter = newPrompter(f)      // Depends on IOStreams
 
 	return f
 }
 
-func configFunc() func() (config.Config, error) {
+func configFunc(f *cmdutil.Factory) func() (config.Config, error) {
 	var cachedConfig config.Config
 	var configError error
 	return func() (config.Config, error) {
 		if cachedConfig != nil || configError != nil {
 			return cachedConfig, configError
 		}
-		cachedConfig, configError = config.NewConfig()
+		cachedConfig, configError = config.Load(config.Options{
+			Context:   f.Profile,
+			Create:    f.CreateProfile,
+			Overrides: config.EnvOverrides().Merge(f.ConfigOverrides),
+		})
 		return cachedConfig, configError
 	}
 }
 
 func ioStreams() *iostreams.IOStreams {
 	io := iostreams.System()
+	io.SetPalette(loadPalette(io))
 	return io
 }
 
-func httpClientFunc() func() *http.Client {
+// loadPalette reads the theme of the config file, changed by the theme file. The config file is
+// read on its own, as the theme is shared by all contexts and the context is not known yet.
+func loadPalette(io *iostreams.IOStreams) func() *theme.Palette {
+	return func() *theme.Palette {
+		base := ""
+		if cfg, err := config.Load(config.Options{}); err == nil {
+			base = cfg.Get().Theme
+		}
+
+		palette, err := theme.Load(base, theme.DefaultPath())
+		if err != nil {
+			// io.ColorScheme would load the palette again: warn with a scheme of its own.
+			cs := iostreams.NewColorScheme(io.ColorEnabled(), io.ColorSupport256(), io.HasTrueColor())
+			fmt.Fprintf(io.ErrOut, "%s %s\n", cs.WarningIcon(), err)
+		}
+		return palette
+	}
+}
+
+func httpClientFunc(f *cmdutil.Factory) func() *http.Client {
 	return func() *http.Client {
 		transport := http.Transport{
 			IdleConnTimeout:   30 * time.Second,
@@ -48,10 +77,18 @@ func httpClientFunc() func() *http.Client {
 			DisableKeepAlives: false,
 		}
 
+		// Traces requests when --debug is set, including the retry after a token refresh.
+		traced := &debug.Transport{Base: &transport}
+
 		client := http.Client{
-			Transport: &transport,
+			Transport: traced,
 			Timeout:   10 * time.Second,
 		}
+
+		// Refresh expired access tokens transparently once a config is available.
+		if cfg, err := f.Config(); err == nil {
+			client.Transport = &api.AuthTransport{Base: traced, Config: cfg}
+		}
 		return &client
 	}
 }
@@ -7,10 +7,10 @@ import (
 	"strings"
 
 	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	"github.com/logfire-sh/cli/pkg/cmdutil/helpers"
 	"github.com/logfire-sh/cli/pkg/cmdutil/pre_defined_prompters"
 	"github.com/logfire-sh/cli/pkg/iostreams"
@@ -81,10 +81,10 @@ func CreateIntegrationRun(opts *CreateIntegrationOptions) {
 		fmt.Fprintf(opts.IO.ErrOut, "%s Failed to read config\n", cs.FailureIcon())
 	}
 
-	client := http.Client{}
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
 
 	if opts.TeamId != "" {
-		teamId := helpers.TeamNameToTeamId(&client, cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+		teamId := helpers.TeamNameToTeamId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 
 		if teamId == "" {
 			fmt.Fprintf(opts.IO.ErrOut, "%s no team with name: %s found.\n", cs.FailureIcon(), opts.TeamId)
@@ -95,7 +95,7 @@ func CreateIntegrationRun(opts *CreateIntegrationOptions) {
 	}
 
 	if opts.Interactive && opts.TeamId == "" && opts.Name == "" && opts.Description == "" && opts.IntegrationType == "" && opts.Id == "" {
-		opts.TeamId, _ = pre_defined_prompters.AskTeamId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter)
+		opts.TeamId, _ = pre_defined_prompters.AskTeamId(client, opts.IO, cs, opts.Prompter)
 
 		opts.Name, err = opts.Prompter.Input("Enter a name for the alert:", "")
 		if err != nil {
@@ -141,7 +141,7 @@ func CreateIntegrationRun(opts *CreateIntegrationOptions) {
 		}
 	}
 
-	err = APICalls.CreateIntegration(opts.HttpClient(), cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId,
+	err = client.CreateIntegration(opts.TeamId,
 		opts.Name, opts.Description, opts.Id, strings.ToLower(opts.IntegrationType))
 	if err != nil {
 		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
@@ -6,10 +6,10 @@ import (
 	"os"
 
 	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	"github.com/logfire-sh/cli/pkg/cmdutil/helpers"
 	"github.com/logfire-sh/cli/pkg/cmdutil/pre_defined_prompters"
 	"github.com/logfire-sh/cli/pkg/iostreams"
@@ -74,10 +74,10 @@ func UpdateIntegrationRun(opts *UpdateIntegrationOptions) {
 		fmt.Fprintf(opts.IO.ErrOut, "%s Failed to read config\n", cs.FailureIcon())
 	}
 
-	client := http.Client{}
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
 
 	if opts.TeamId != "" {
-		teamId := helpers.TeamNameToTeamId(&client, cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+		teamId := helpers.TeamNameToTeamId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 
 		if teamId == "" {
 			fmt.Fprintf(opts.IO.ErrOut, "%s no team with name: %s found.\n", cs.FailureIcon(), opts.TeamId)
@@ -89,9 +89,9 @@ func UpdateIntegrationRun(opts *UpdateIntegrationOptions) {
 
 	if opts.Interactive {
 		if opts.TeamId == "" && opts.IntegrationId == "" && opts.Name == "" && opts.Description == "" {
-			opts.TeamId, _ = pre_defined_prompters.AskTeamId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter)
+			opts.TeamId, _ = pre_defined_prompters.AskTeamId(client, opts.IO, cs, opts.Prompter)
 
-			opts.IntegrationId, _ = pre_defined_prompters.AskIntegrationId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+			opts.IntegrationId, _ = pre_defined_prompters.AskIntegrationId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 
 			updateName, _ := opts.Prompter.Confirm("Do you want to update the alert name?", false)
 			if updateName {
@@ -100,7 +100,7 @@ func UpdateIntegrationRun(opts *UpdateIntegrationOptions) {
 
 			updateDescription, _ := opts.Prompter.Confirm("Do you want to update the Description?", false)
 			if updateDescription {
-				opts.Description, _ = pre_defined_prompters.AskViewId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+				opts.Description, _ = pre_defined_prompters.AskViewId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 			}
 		}
 	} else {
@@ -114,7 +114,7 @@ func UpdateIntegrationRun(opts *UpdateIntegrationOptions) {
 		}
 	}
 
-	err = APICalls.UpdateIntegration(opts.HttpClient(), cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId, opts.IntegrationId,
+	err = client.UpdateIntegration(opts.TeamId, opts.IntegrationId,
 		opts.Name, opts.Description)
 	if err != nil {
 		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
@@ -1,21 +1,14 @@
 package login
 
 import (
-	"bytes"
-	"encoding/json"
-	"errors"
 	"fmt"
-	"io"
-	"io/ioutil"
 	"net/http"
 	"os"
 	"regexp"
 	"strings"
 
-	"github.com/logfire-sh/cli/pkg/cmd/login/models"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
-
 	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
@@ -101,8 +94,11 @@ func loginRun(opts *LoginOptions) {
 		grpc_endpoint := "staging.api.logfire.ai:443"
 		grpc_ingestion := "https://staging.in.logfire.ai"
 
-		err = cfg.UpdateConfig(nil, nil, nil, nil,
-			nil, nil, nil, &endpoint, &grpc_endpoint, &grpc_ingestion, nil)
+		err = cfg.UpdateConfig(func(c *config.AuthConfig) {
+			c.EndPoint = endpoint
+			c.GrpcEndpoint = grpc_endpoint
+			c.GrpcIngestion = grpc_ingestion
+		})
 		if err != nil {
 			return
 		}
@@ -113,13 +109,18 @@ func loginRun(opts *LoginOptions) {
 		grpc_endpoint := "localhost:8888"
 		grpc_ingestion := "http://localhost:8888/logfire.sh"
 
-		err = cfg.UpdateConfig(nil, nil, nil, nil,
-			nil, nil, nil, &endpoint, &grpc_endpoint, &grpc_ingestion, nil)
+		err = cfg.UpdateConfig(func(c *config.AuthConfig) {
+			c.EndPoint = endpoint
+			c.GrpcEndpoint = grpc_endpoint
+			c.GrpcIngestion = grpc_ingestion
+		})
 		if err != nil {
 			return
 		}
 	}
 
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
+
 	var choiceList = []string{"Magic link", "Password", "Exit"}
 
 	if opts.Interactive && opts.Token == "" && opts.Email == "" && opts.Password == "" {
@@ -137,9 +138,9 @@ func loginRun(opts *LoginOptions) {
 				return
 			}
 
-			err = APICalls.SendMagicLink(cfg.Get().EndPoint, opts.Email)
+			err = client.SendMagicLink(opts.Email)
 			if err != nil {
-				if strings.Contains(err.Error(), "no such host") {
+				if api.IsConnectionError(err) {
 					fmt.Fprintf(opts.IO.ErrOut, "\n%s Error: Connection failed (Server down or no internet)\n", cs.FailureIcon())
 					return
 				}
@@ -156,7 +157,7 @@ func loginRun(opts *LoginOptions) {
 			}
 
 			opts.IO.StartProgressIndicatorWithLabel("Logging in to logfire.ai")
-			TokenSignin(opts.IO, cfg, cs, opts.Token, cfg.Get().EndPoint)
+			TokenSignin(opts.IO, client, cfg, cs, opts.Token)
 
 		case "Password":
 			email, err := opts.Prompter.Input("Enter your email:", "")
@@ -174,9 +175,9 @@ func loginRun(opts *LoginOptions) {
 			opts.Password = password
 
 			opts.IO.StartProgressIndicatorWithLabel("Logging in to logfire.ai")
-			err = PasswordSignin(opts.IO, cfg, cs, opts.Email, opts.Password, cfg.Get().EndPoint)
+			err = PasswordSignin(opts.IO, client, cfg, cs, opts.Email, opts.Password)
 			if err != nil {
-				if strings.Contains(err.Error(), "no such host") {
+				if api.IsConnectionError(err) {
 					fmt.Fprintf(opts.IO.ErrOut, "\n%s Error: Connection failed (Server down or no internet)\n", cs.FailureIcon())
 					return
 				} else if strings.Contains(err.Error(), "Password didn't match") {
@@ -184,7 +185,7 @@ func loginRun(opts *LoginOptions) {
 					return
 				}
 
-				fmt.Fprintf(opts.IO.ErrOut, "\n%s Failed to sign in\n", cs.FailureIcon())
+				fmt.Fprintf(opts.IO.ErrOut, "\n%s %s\n", cs.FailureIcon(), err.Error())
 				return
 			}
 
@@ -199,13 +200,13 @@ func loginRun(opts *LoginOptions) {
 		switch {
 		case !isEmpty(opts.Token) && isEmpty(opts.Email) && isEmpty(opts.Password):
 			opts.IO.StartProgressIndicatorWithLabel("Logging in to logfire.ai")
-			TokenSignin(opts.IO, cfg, cs, opts.Token, cfg.Get().EndPoint)
+			TokenSignin(opts.IO, client, cfg, cs, opts.Token)
 
 		case !isEmpty(opts.Email) && isEmpty(opts.Token):
 			if isEmpty(opts.Password) {
-				err = APICalls.SendMagicLink(cfg.Get().EndPoint, opts.Email)
+				err = client.SendMagicLink(opts.Email)
 				if err != nil {
-					if strings.Contains(err.Error(), "no such host") {
+					if api.IsConnectionError(err) {
 						fmt.Fprintf(opts.IO.ErrOut, "\n%s Error: Connection failed (Server down or no internet)\n", cs.FailureIcon())
 						return
 					}
@@ -215,9 +216,9 @@ func loginRun(opts *LoginOptions) {
 				fmt.Fprintf(opts.IO.Out, "%s Magic link sent to %s \n%s Use \"logfire login --token <token>\" to sign-in using received token\n", cs.SuccessIcon(), opts.Email, cs.IntermediateIcon())
 			} else {
 				opts.IO.StartProgressIndicatorWithLabel("Logging in to logfire.ai")
-				err = PasswordSignin(opts.IO, cfg, cs, opts.Email, opts.Password, cfg.Get().EndPoint)
+				err = PasswordSignin(opts.IO, client, cfg, cs, opts.Email, opts.Password)
 				if err != nil {
-					if strings.Contains(err.Error(), "no such host") {
+					if api.IsConnectionError(err) {
 						fmt.Fprintf(opts.IO.ErrOut, "\n%s Error: Connection failed (Server down or no internet)\n", cs.FailureIcon())
 						return
 					} else if strings.Contains(err.Error(), "Password didn't match") {
@@ -225,7 +226,7 @@ func loginRun(opts *LoginOptions) {
 						return
 					}
 
-					fmt.Fprintf(opts.IO.ErrOut, "\n%s Failed to sign in\n", cs.FailureIcon())
+					fmt.Fprintf(opts.IO.ErrOut, "\n%s %s\n", cs.FailureIcon(), err.Error())
 					return
 				}
 			}
@@ -245,58 +246,14 @@ func loginRun(opts *LoginOptions) {
 	}
 }
 
-func PasswordSignin(io *iostreams.IOStreams, cfg config.Config, cs *iostreams.ColorScheme, email, password string, endpoint string) error {
-	var response models.Response
-	signinReq := models.SigninRequest{
-		Email:      email,
-		AuthType:   2,
-		Credential: password,
-	}
-
-	client := &http.Client{}
-
-	reqBody, err := json.Marshal(signinReq)
-	if err != nil {
-		return err
-	}
-
-	url := endpoint + "api/auth/signin"
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(reqBody))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("User-Agent", "Logfire-cli")
-	req.Header.Add("Content-Type", "application/json")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		if strings.Contains(err.Error(), "no such host") {
-			fmt.Printf("\nError: Connection failed (Server down or no internet)\n")
-			os.Exit(1)
-		}
-
-		return err
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return err
-	}
-
-	err = json.Unmarshal(body, &response)
+func PasswordSignin(io *iostreams.IOStreams, client *api.Client, cfg config.Config, cs *iostreams.ColorScheme, email, password string) error {
+	response, err := client.SignInWithPassword(email, password)
+	io.StopProgressIndicator()
 	if err != nil {
 		return err
 	}
 
-	if !response.IsSuccessful {
-		fmt.Fprintf(io.ErrOut, "\n%s %s\n", cs.FailureIcon(), response.Message[0])
-		os.Exit(0)
-	}
-
-	io.StopProgressIndicator()
-
-	err = cfg.UpdateConfig(&response.UserBody.Email, &response.UserBody.Role, &response.BearerToken.AccessToken, &response.UserBody.ProfileID,
-		&response.BearerToken.RefreshToken, &response.TeamBody.Id, &response.UserBody.AccountID, nil, nil, nil, nil)
+	err = api.SaveSession(cfg, response)
 	if err != nil {
 		return err
 	}
@@ -309,66 +266,19 @@ func PasswordSignin(io *iostreams.IOStreams, cfg config.Config, cs *iostreams.Co
 	return nil
 }
 
-func TokenSignin(IO *iostreams.IOStreams, cfg config.Config, cs *iostreams.ColorScheme, token, endpoint string) error {
-	var response models.Response
-
-	signinReq := models.SigninRequest{
-		AuthType:   1,
-		Credential: strings.TrimSpace(token),
-	}
-
-	client := &http.Client{}
-
-	reqBody, err := json.Marshal(signinReq)
-	if err != nil {
-		fmt.Printf("Failed to marshal request body: %v\n", err)
-		return err
-	}
-
-	url := endpoint + "api/auth/signin"
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(reqBody))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("User-Agent", "Logfire-cli")
-	req.Header.Add("Content-Type", "application/json")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		if strings.Contains(err.Error(), "no such host") {
-			fmt.Printf("\nError: Connection failed (Server down or no internet)\n")
-			os.Exit(1)
-		}
-
-		return err
-	}
-
+func TokenSignin(IO *iostreams.IOStreams, client *api.Client, cfg config.Config, cs *iostreams.ColorScheme, token string) error {
+	response, err := client.SignInWithToken(token)
 	IO.StopProgressIndicator()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		fmt.Printf("Failed to read response body: %v\n", err)
-		return err
-	}
-
-	err = json.Unmarshal(body, &response)
 	if err != nil {
-		fmt.Printf("Failed to unmarshal JSON: %v\n", err)
-		return err
-	}
-
-	if !response.IsSuccessful {
-		if ok, _ := regexp.MatchString("invalid UUID", response.Message[0]); ok {
+		if ok, _ := regexp.MatchString("invalid UUID", err.Error()); ok {
 			fmt.Fprintf(IO.ErrOut, "\n%s %s\n", cs.FailureIcon(), "invalid token")
 		} else {
-			fmt.Fprintf(IO.ErrOut, "\n%s %s\n", cs.FailureIcon(), response.Message[0])
+			fmt.Fprintf(IO.ErrOut, "\n%s %s\n", cs.FailureIcon(), err.Error())
 		}
-		return errors.New(response.Message[0])
+		return err
 	}
 
-	err = cfg.UpdateConfig(&response.UserBody.Email, &response.UserBody.Role, &response.BearerToken.AccessToken, &response.UserBody.ProfileID,
-		&response.BearerToken.RefreshToken, &response.TeamBody.Id, &response.UserBody.AccountID, nil, nil, nil, nil)
+	err = api.SaveSession(cfg, response)
 	if err != nil {
 		return err
 	}
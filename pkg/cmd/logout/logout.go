@@ -1,15 +1,12 @@
 package logout
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
-	"strings"
 
 	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
@@ -76,9 +73,10 @@ func logoutRun(opts *LogoutOptions) {
 
 	user := cfg.Get().Username
 
-	err = logout(opts.HttpClient(), cfg.Get().Token, cfg.Get().EndPoint, cfg.Get().RefreshToken)
+	// A session the server refuses to revoke has expired anyway, so only a connection failure stops the logout.
+	err = api.NewClientFromConfig(opts.HttpClient(), cfg).SignOut(cfg.Get().RefreshToken)
 	if err != nil {
-		if strings.Contains(err.Error(), "no such host") {
+		if api.IsConnectionError(err) {
 			fmt.Fprintf(opts.IO.ErrOut, "%s Error: Connection failed (Server down or no internet)\n", cs.FailureIcon())
 			os.Exit(0)
 			return
@@ -93,44 +91,3 @@ func logoutRun(opts *LogoutOptions) {
 
 	fmt.Fprintf(opts.IO.Out, "%s User %s successfully logged out.\n", cs.SuccessIcon(), user)
 }
-
-func logout(client *http.Client, token string, endpoint string, refreshToken string) error {
-	url := endpoint + "api/auth/signout"
-
-	reqBody := map[string]string{
-		"AccessToken":  token,
-		"RefreshToken": refreshToken,
-	}
-
-	jsonValue, _ := json.Marshal(reqBody)
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonValue))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("User-Agent", "Logfire-cli")
-
-	req.Header.Set("Authorization", "Bearer "+token)
-	resp, err := client.Do(req)
-	if err != nil {
-		if strings.Contains(err.Error(), "no such host") {
-			fmt.Printf("\nError: Connection failed (Server down or no internet)\n")
-			os.Exit(1)
-		}
-
-		return err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return err
-	}
-
-	if strings.Contains(string(body), "false") {
-		// return errors.New("request can't be completed")
-		return nil
-	}
-
-	return nil
-}
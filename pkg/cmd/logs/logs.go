@@ -0,0 +1,20 @@
+package logs
+
+import (
+	"github.com/logfire-sh/cli/pkg/cmd/logs/logs_histogram"
+	"github.com/logfire-sh/cli/pkg/cmd/logs/logs_search"
+	"github.com/logfire-sh/cli/pkg/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdLogs(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "logs <command>",
+		Short:   "Query stored logs",
+		GroupID: "core",
+	}
+
+	cmd.AddCommand(logs_search.NewLogsSearchCmd(f))
+	cmd.AddCommand(logs_histogram.NewLogsHistogramCmd(f))
+	return cmd
+}
@@ -0,0 +1,187 @@
+package logs_histogram
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/api"
+	"github.com/logfire-sh/cli/internal/config"
+	"github.com/logfire-sh/cli/internal/prompter"
+	"github.com/logfire-sh/cli/pkg/cmdutil"
+	"github.com/logfire-sh/cli/pkg/cmdutil/filters"
+	"github.com/logfire-sh/cli/pkg/cmdutil/grpcutil"
+	"github.com/logfire-sh/cli/pkg/cmdutil/helpers"
+	"github.com/logfire-sh/cli/pkg/cmdutil/logformat"
+	"github.com/logfire-sh/cli/pkg/cmdutil/sourceref"
+	"github.com/logfire-sh/cli/pkg/iostreams"
+	pb "github.com/logfire-sh/cli/services/flink-service"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type LogsHistogramOptions struct {
+	IO       *iostreams.IOStreams
+	Prompter prompter.Prompter
+
+	HttpClient func() *http.Client
+	Config     func() (config.Config, error)
+	Exporter   *cmdutil.Exporter
+
+	TeamId       string
+	SourceFilter []string
+	Filter       string
+	StartDate    string
+	EndDate      string
+	By           string
+	Width        int
+	ASCII        bool
+
+	query      *filters.Query
+	start, end time.Time
+	histogram  *logformat.Histogram
+}
+
+func NewLogsHistogramCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &LogsHistogramOptions{
+		IO:         f.IOStreams,
+		Prompter:   f.Prompter,
+		HttpClient: f.HttpClient,
+		Config:     f.Config,
+		Exporter:   f.Exporter,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "histogram",
+		Args:  cobra.NoArgs,
+		Short: "Chart the volume of logs over time (experimental)",
+		Long: heredoc.Docf(`
+			Count the stored logs matching a filter between two dates and draw them as a bar
+			chart, one bar per time bucket, split by level or by source.
+
+			Dates and the filter are those of %[1]slogs search%[1]s, except that the filter only
+			compares fields, such as %[1]slevel=error status>=500%[1]s: text searches are not counted
+			by the server. Bars are drawn with Unicode blocks, or with ASCII characters with
+			--ascii. The buckets are printed as documents with --output, --jq or --template.
+
+			This command is experimental: it reads the bar graph in the format served by
+			%[1]slogfire dev server%[1]s, and fails on a server answering in another format.
+		`, "`"),
+		Example: heredoc.Doc(`
+			# errors and warnings of the last hour, by level
+			$ logfire logs histogram --start-date now-1h
+
+			# which source got busy last night
+			$ logfire logs histogram --start-date 2024-05-01T22:00:00Z --end-date 2024-05-02T02:00:00Z --by source
+
+			# the busiest bucket of the 5xx responses of the api sources
+			$ logfire logs histogram --source-id 'api-*' --filter 'status>=500' --output json --jq 'max_by(.count)'
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if opts.query, err = filters.ParseQuery(opts.Filter); err != nil {
+				return cmdutil.FlagErrorWrap(err)
+			}
+			if len(opts.query.SearchQueries) > 0 {
+				return cmdutil.FlagErrorf("--filter can only compare fields, such as level=error, not search for %q", opts.query.SearchQueries[0])
+			}
+
+			if opts.start, opts.end, err = filters.ParseTimeRange(opts.StartDate, opts.EndDate, time.Now()); err != nil {
+				return cmdutil.FlagErrorWrap(err)
+			}
+			if opts.start.IsZero() || opts.end.IsZero() {
+				return cmdutil.FlagErrorf("--start-date and --end-date must not be empty")
+			}
+
+			if opts.Width < 0 {
+				return cmdutil.FlagErrorf("--width must not be negative")
+			}
+			if opts.Width == 0 {
+				opts.Width = opts.IO.TerminalWidth()
+			}
+			if opts.histogram, err = logformat.NewHistogram(opts.IO.ColorScheme(), opts.By, opts.Width, opts.ASCII); err != nil {
+				return cmdutil.FlagErrorf("--by: %s", err)
+			}
+
+			return logsHistogramRun(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.TeamId, "team-name", "t", "", "Team of the sources (Default: the team of the current context).")
+	cmd.Flags().StringSliceVarP(&opts.SourceFilter, "source-id", "s", nil, "Count only these sources, "+sourceref.Help+". (Default: all sources of the team)")
+	cmd.Flags().StringVar(&opts.Filter, "filter", "", "Count only the logs whose fields match an expression, such as 'level=error status>=500'.")
+	cmd.Flags().StringVar(&opts.StartDate, "start-date", "now-1h", "Oldest time of the logs.")
+	cmd.Flags().StringVar(&opts.EndDate, "end-date", "now", "Newest time of the logs.")
+	cmd.Flags().StringVar(&opts.By, "by", logformat.SplitLevel, "Split the bars by: {level|source|none}.")
+	cmd.Flags().IntVar(&opts.Width, "width", 0, "Width of the chart in columns. (Default: the width of the terminal)")
+	cmd.Flags().BoolVar(&opts.ASCII, "ascii", false, "Draw the bars with ASCII characters instead of Unicode blocks.")
+
+	_ = cmd.RegisterFlagCompletionFunc("source-id", sourceref.CompletionFunc(f, "team-name"))
+	_ = cmd.RegisterFlagCompletionFunc("by", cobra.FixedCompletions(logformat.Splits, cobra.ShellCompDirectiveNoFileComp))
+
+	cmdutil.EnableExport(cmd)
+
+	return cmd
+}
+
+func logsHistogramRun(opts *LogsHistogramOptions) error {
+	cfg, err := opts.Config()
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
+	cs := opts.IO.ColorScheme()
+
+	teamId := cfg.Get().TeamId
+	if opts.TeamId != "" {
+		teamId = helpers.TeamNameToTeamId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
+		if teamId == "" {
+			return fmt.Errorf("no team with name: %s found", opts.TeamId)
+		}
+	}
+	if teamId == "" {
+		return cmdutil.FlagErrorf("--team-name is required")
+	}
+
+	sources, err := client.ListSources(teamId)
+	if err != nil {
+		return err
+	}
+	if opts.SourceFilter != nil {
+		if sources, err = sourceref.Resolve(sources, opts.SourceFilter); err != nil {
+			return err
+		}
+	}
+
+	request := &pb.GraphRequest{
+		TeamID:    teamId,
+		AccountID: cfg.Get().AccountId,
+		Sources:   grpcutil.CreateGrpcSource(sources),
+		DateTimeFilter: &pb.DateTimeFilter{
+			StartTimeStamp: timestamppb.New(opts.start),
+			EndTimeStamp:   timestamppb.New(opts.end),
+		},
+		FieldBasedFilters: opts.query.FieldFilters,
+	}
+
+	metaService := grpcutil.NewMetaService(cfg)
+	defer metaService.CloseConnection()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	buckets, err := metaService.BarGraph(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	if opts.Exporter.Enabled() {
+		return opts.Exporter.Write(opts.IO.Out, buckets)
+	}
+	return opts.histogram.Write(opts.IO.Out, buckets)
+}
@@ -0,0 +1,198 @@
+package logs_search
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/api"
+	"github.com/logfire-sh/cli/internal/config"
+	"github.com/logfire-sh/cli/internal/prompter"
+	"github.com/logfire-sh/cli/pkg/cmdutil"
+	"github.com/logfire-sh/cli/pkg/cmdutil/filters"
+	"github.com/logfire-sh/cli/pkg/cmdutil/grpcutil"
+	"github.com/logfire-sh/cli/pkg/cmdutil/helpers"
+	"github.com/logfire-sh/cli/pkg/cmdutil/logformat"
+	"github.com/logfire-sh/cli/pkg/cmdutil/sourceref"
+	"github.com/logfire-sh/cli/pkg/iostreams"
+	pb "github.com/logfire-sh/cli/services/flink-service"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type LogsSearchOptions struct {
+	IO       *iostreams.IOStreams
+	Prompter prompter.Prompter
+
+	HttpClient func() *http.Client
+	Config     func() (config.Config, error)
+	Exporter   *cmdutil.Exporter
+
+	TeamId       string
+	SourceFilter []string
+	Filter       string
+	StartDate    string
+	EndDate      string
+	Limit        int
+	Reverse      bool
+	BatchSize    int
+	Format       string
+	Fields       []string
+	Pretty       bool
+
+	query      *filters.Query
+	start, end time.Time
+	printer    *logformat.Printer
+}
+
+func NewLogsSearchCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &LogsSearchOptions{
+		IO:         f.IOStreams,
+		Prompter:   f.Prompter,
+		HttpClient: f.HttpClient,
+		Config:     f.Config,
+		Exporter:   f.Exporter,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "search",
+		Args:  cobra.NoArgs,
+		Short: "Search the logs of a time range",
+		Long: heredoc.Docf(`
+			Print the stored logs matching a filter between two dates, then exit.
+
+			Logs are printed oldest first, or newest first with --reverse, and the search
+			stops after --limit records. Dates are relative, such as %[1]snow-2h%[1]s, %[1]snow-1h30m%[1]s
+			or %[1]syesterday%[1]s, dates such as %[1]s2024-05-01 22:00%[1]s or RFC 3339 timestamps, and
+			may end with a zone such as %[1]sUTC%[1]s or %[1]sEurope/Berlin%[1]s. The filter uses the
+			expressions of %[1]stail --filter%[1]s.
+		`, "`"),
+		Example: heredoc.Doc(`
+			# errors of last night's incident, oldest first
+			$ logfire logs search --start-date 2024-05-01T22:00:00Z --end-date 2024-05-02T02:00:00Z --filter 'level=error'
+
+			# the 20 most recent timeouts of the api source, as JSON
+			$ logfire logs search --source-id api --filter 'msg:timeout' --reverse --limit 20 --output ndjson
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Limit < 0 {
+				return cmdutil.FlagErrorf("--limit must not be negative")
+			}
+			if opts.BatchSize <= 0 {
+				return cmdutil.FlagErrorf("--batch-size must be greater than 0")
+			}
+
+			var err error
+			if opts.query, err = filters.ParseQuery(opts.Filter); err != nil {
+				return cmdutil.FlagErrorWrap(err)
+			}
+
+			if opts.start, opts.end, err = filters.ParseTimeRange(opts.StartDate, opts.EndDate, time.Now()); err != nil {
+				return cmdutil.FlagErrorWrap(err)
+			}
+			if opts.start.IsZero() || opts.end.IsZero() {
+				return cmdutil.FlagErrorf("--start-date and --end-date must not be empty")
+			}
+
+			if opts.printer, err = logformat.NewPrinter(opts.IO.ColorScheme(), opts.Format, opts.Fields, opts.Pretty); err != nil {
+				return cmdutil.FlagErrorWrap(err)
+			}
+			if opts.Exporter.Enabled() && (opts.Format != "" || opts.Fields != nil || opts.Pretty) {
+				return cmdutil.FlagErrorf("--format, --fields and --pretty cannot be used with --output, --jq or --template")
+			}
+
+			return logsSearchRun(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.TeamId, "team-name", "t", "", "Team of the sources (Default: the team of the current context).")
+	cmd.Flags().StringSliceVarP(&opts.SourceFilter, "source-id", "s", nil, "Search only these sources, "+sourceref.Help+". (Default: all sources of the team)")
+	cmd.Flags().StringVar(&opts.Filter, "filter", "", "Filter logs by an expression, such as 'level=error status>=500 -path:/health'.")
+	cmd.Flags().StringVar(&opts.StartDate, "start-date", "now-1h", "Oldest time of the logs.")
+	cmd.Flags().StringVar(&opts.EndDate, "end-date", "now", "Newest time of the logs.")
+	cmd.Flags().IntVar(&opts.Limit, "limit", 100, "Most records printed, 0 for all of the time range.")
+	cmd.Flags().BoolVar(&opts.Reverse, "reverse", false, "Print the newest logs first.")
+	cmd.Flags().IntVar(&opts.BatchSize, "batch-size", 100, "Records requested per source and page.")
+	cmd.Flags().StringVar(&opts.Format, "format", "", "Line format: {default|short|full|raw|logfmt} or a Go template such as '{{.Clock}} {{.Field \"user_id\"}} {{.Message}}'.")
+	cmd.Flags().StringSliceVar(&opts.Fields, "fields", nil, "Print these fields of JSON or logfmt messages as aligned columns, such as dt,level,user.id,message.")
+	cmd.Flags().BoolVar(&opts.Pretty, "pretty", false, "Print the fields of JSON or logfmt messages on indented lines below each record.")
+
+	_ = cmd.RegisterFlagCompletionFunc("source-id", sourceref.CompletionFunc(f, "team-name"))
+
+	cmdutil.EnableExport(cmd)
+
+	return cmd
+}
+
+func logsSearchRun(opts *LogsSearchOptions) error {
+	cfg, err := opts.Config()
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
+	cs := opts.IO.ColorScheme()
+
+	teamId := cfg.Get().TeamId
+	if opts.TeamId != "" {
+		teamId = helpers.TeamNameToTeamId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
+		if teamId == "" {
+			return fmt.Errorf("no team with name: %s found", opts.TeamId)
+		}
+	}
+	if teamId == "" {
+		return cmdutil.FlagErrorf("--team-name is required")
+	}
+
+	sources, err := client.ListSources(teamId)
+	if err != nil {
+		return err
+	}
+	if opts.SourceFilter != nil {
+		if sources, err = sourceref.Resolve(sources, opts.SourceFilter); err != nil {
+			return err
+		}
+	}
+
+	request := &pb.FilterRequest{
+		DateTimeFilter: &pb.DateTimeFilter{
+			StartTimeStamp: timestamppb.New(opts.start),
+			EndTimeStamp:   timestamppb.New(opts.end),
+		},
+		FieldBasedFilters: []*pb.FieldBasedFilter{},
+		SearchQueries:     []string{},
+		Sources:           grpcutil.CreateGrpcSource(sources),
+		BatchSize:         uint32(opts.BatchSize),
+		AccountID:         cfg.Get().AccountId,
+		TeamID:            teamId,
+	}
+	opts.query.Apply(request)
+
+	filterService := grpcutil.NewFilterService(cfg)
+	defer filterService.CloseConnection()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return filterService.SearchRecords(ctx, request, opts.Limit, opts.Reverse, func(records []*pb.FilteredRecord) error {
+		return showLogs(opts.IO, opts.Exporter, opts.printer, records)
+	})
+}
+
+// showLogs prints records like tail, or one document per record when structured output is requested.
+func showLogs(io *iostreams.IOStreams, exporter *cmdutil.Exporter, printer *logformat.Printer, records []*pb.FilteredRecord) error {
+	if exporter.Enabled() {
+		for _, record := range records {
+			if err := exporter.WriteRecord(io.Out, record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return printer.Print(io.Out, records)
+}
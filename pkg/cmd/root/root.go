@@ -5,14 +5,18 @@ import (
 	"os"
 
 	"github.com/logfire-sh/cli/pkg/cmd/delete_profile"
+	"github.com/logfire-sh/cli/pkg/cmd/dev"
 	"github.com/logfire-sh/cli/pkg/cmd/set_password"
 
+	"github.com/logfire-sh/cli/pkg/cmd/send"
 	"github.com/logfire-sh/cli/pkg/cmd/settings"
 
+	"github.com/logfire-sh/cli/internal/debug"
 	"github.com/logfire-sh/cli/internal/prompter"
 	"github.com/logfire-sh/cli/pkg/cmd/alerts"
 	"github.com/logfire-sh/cli/pkg/cmd/bootstrap"
 	"github.com/logfire-sh/cli/pkg/cmd/check_endpoint"
+	"github.com/logfire-sh/cli/pkg/cmd/cli_config"
 	"github.com/logfire-sh/cli/pkg/cmd/integrations"
 	"github.com/logfire-sh/cli/pkg/cmd/reset_password"
 	"github.com/logfire-sh/cli/pkg/cmd/roundtrip"
@@ -24,6 +28,7 @@ import (
 	"github.com/MakeNowJust/heredoc"
 	"github.com/logfire-sh/cli/pkg/cmd/login"
 	"github.com/logfire-sh/cli/pkg/cmd/logout"
+	"github.com/logfire-sh/cli/pkg/cmd/logs"
 	"github.com/logfire-sh/cli/pkg/cmd/signup"
 	"github.com/logfire-sh/cli/pkg/cmd/sources"
 	"github.com/logfire-sh/cli/pkg/cmd/stream"
@@ -54,11 +59,6 @@ func NewCmdRoot(f *cmdutil.Factory, cmdCh chan bool) (*cobra.Command, error) {
 		Prompter: f.Prompter,
 	}
 
-	cfg, err := f.Config()
-	if err != nil {
-		return nil, fmt.Errorf("failed to read configuration: %s", err)
-	}
-
 	cmd := &cobra.Command{
 		Use: "logfire <command> <subcommand> [flags]",
 		//Args:  cobra.ExactArgs(1),
@@ -70,6 +70,22 @@ func NewCmdRoot(f *cmdutil.Factory, cmdCh chan bool) (*cobra.Command, error) {
 
 		`),
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if f.Debug || debug.EnvEnabled() {
+				debug.Enable(f.IOStreams.ErrOut)
+			}
+			if err := f.Exporter.Validate(); err != nil {
+				return err
+			}
+			if f.Exporter.Enabled() && !cmdutil.IsExportEnabled(cmd) {
+				return cmdutil.FlagErrorf("--output, --jq and --template are not supported by `%s`", cmd.CommandPath())
+			}
+
+			f.CreateProfile = cmd.Annotations["createContext"] == "true"
+			cfg, err := f.Config()
+			if err != nil {
+				return fmt.Errorf("failed to read configuration: %s", err)
+			}
+
 			// require that the user is authenticated before running most commands
 			if opts.IO.CanPrompt() {
 				opts.Interactive = true
@@ -148,6 +164,13 @@ func NewCmdRoot(f *cmdutil.Factory, cmdCh chan bool) (*cobra.Command, error) {
 	}
 
 	cmd.PersistentFlags().Bool("help", false, "Show help for command")
+	cmd.PersistentFlags().StringVar(&f.Profile, "profile", "", "Use the named config context instead of the current one")
+	cmd.PersistentFlags().StringVar(&f.ConfigOverrides.EndPoint, "endpoint", "", "Override the API endpoint of the config context")
+	cmd.PersistentFlags().StringVar(&f.ConfigOverrides.GrpcEndpoint, "grpc-endpoint", "", "Override the gRPC endpoint of the config context")
+	cmd.PersistentFlags().BoolVar(&f.Debug, "debug", false, "Log HTTP and gRPC traffic to stderr (also enabled by LOGFIRE_DEBUG)")
+	cmd.PersistentFlags().StringVarP(&f.Exporter.Format, "output", "o", "", "Output format: {table|json|ndjson|csv|yaml}")
+	cmd.PersistentFlags().StringVar(&f.Exporter.JQ, "jq", "", "Filter structured output using a jq expression")
+	cmd.PersistentFlags().StringVar(&f.Exporter.Template, "template", "", "Format structured output using a Go template")
 
 	cmd.SilenceErrors = true
 	cmd.SilenceUsage = true
@@ -175,6 +198,8 @@ func NewCmdRoot(f *cmdutil.Factory, cmdCh chan bool) (*cobra.Command, error) {
 	cmd.AddCommand(sources.NewCmdSource(f))
 	cmd.AddCommand(teams.NewCmdTeam(f))
 	cmd.AddCommand(tail.NewTailCmd(f))
+	cmd.AddCommand(logs.NewCmdLogs(f))
+	cmd.AddCommand(send.NewSendCmd(f))
 	cmd.AddCommand(stream.NewCmdStream(f))
 	cmd.AddCommand(views.NewCmdViews(f))
 	cmd.AddCommand(alerts.NewCmdAlerts(f))
@@ -186,6 +211,8 @@ func NewCmdRoot(f *cmdutil.Factory, cmdCh chan bool) (*cobra.Command, error) {
 	cmd.AddCommand(roundtrip.NewCmdRoundTrip(f))
 	cmd.AddCommand(settings.SettingsCmd(f))
 	cmd.AddCommand(delete_profile.DeleteProfileCmd(f))
+	cmd.AddCommand(cli_config.NewCmdConfig(f))
+	cmd.AddCommand(dev.NewCmdDev(f))
 
 	// go func() {
 	// 	for range cmdCh {
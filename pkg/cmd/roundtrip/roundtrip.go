@@ -10,13 +10,14 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	"github.com/logfire-sh/cli/pkg/cmdutil/grpcutil"
 	"github.com/logfire-sh/cli/pkg/cmdutil/helpers"
 	"github.com/logfire-sh/cli/pkg/cmdutil/pre_defined_prompters"
+	"github.com/logfire-sh/cli/pkg/cmdutil/sourceref"
 	"github.com/logfire-sh/cli/pkg/iostreams"
 	"github.com/spf13/cobra"
 )
@@ -96,8 +97,9 @@ func NewCmdRoundTrip(f *cmdutil.Factory) *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&opts.TeamId, "team-name", "t", "", "Team name for which the sources will be fetched.")
-	cmd.Flags().StringVarP(&opts.SourceId, "source-id", "s", "", "Source ID for which the roundtrip is tested)")
+	cmd.Flags().StringVarP(&opts.SourceId, "source-id", "s", "", "Source for which the roundtrip is tested, "+sourceref.Help+".")
 	cmd.Flags().IntVarP(&opts.Run, "run", "r", 0, "Number of rounds")
+	_ = cmd.RegisterFlagCompletionFunc("source-id", sourceref.CompletionFunc(f, "team-name"))
 
 	return cmd
 }
@@ -107,10 +109,10 @@ func PromptRoundTripRun(opts *PromptRoundTripOptions) {
 	cfg, _ := opts.Config()
 	cs := opts.IO.ColorScheme()
 
-	client := http.Client{}
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
 
 	if opts.TeamId != "" {
-		teamId := helpers.TeamNameToTeamId(&client, cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+		teamId := helpers.TeamNameToTeamId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 
 		if teamId == "" {
 			fmt.Fprintf(opts.IO.ErrOut, "%s no team with name: %s found.\n", cs.FailureIcon(), opts.TeamId)
@@ -122,10 +124,16 @@ func PromptRoundTripRun(opts *PromptRoundTripOptions) {
 
 	if opts.TeamId != "" && opts.SourceId != "" {
 
-		source, err := APICalls.GetSource(cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId, opts.SourceId)
+		sources, err := client.ListSources(opts.TeamId)
 		if err != nil {
 			log.Fatal(err)
 		}
+		source, err := sourceref.ResolveOne(sources, opts.SourceId)
+		if err != nil {
+			fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+			return
+		}
+		opts.SourceId = source.ID
 
 		id := uuid.New()
 
@@ -180,9 +188,9 @@ func PromptRoundTripRun(opts *PromptRoundTripOptions) {
 
 		fmt.Printf("The round trip took: %s\n", elapsed)
 	} else {
-		opts.TeamId, _ = pre_defined_prompters.AskTeamId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter)
+		opts.TeamId, _ = pre_defined_prompters.AskTeamId(client, opts.IO, cs, opts.Prompter)
 
-		sourceList, err := APICalls.GetAllSources(opts.HttpClient(), cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId)
+		sourceList, err := client.ListSources(opts.TeamId)
 		if err != nil {
 			fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err)
 			return
@@ -241,7 +249,7 @@ func PromptRoundTripRun(opts *PromptRoundTripOptions) {
 				return
 			}
 
-			source, err := APICalls.CreateSource(cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId, opts.SourceName, opts.Platform)
+			source, err := client.CreateSource(opts.TeamId, opts.SourceName, opts.Platform)
 			if err != nil {
 				fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
 				return
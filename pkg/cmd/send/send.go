@@ -0,0 +1,357 @@
+package send
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/api"
+	"github.com/logfire-sh/cli/internal/config"
+	"github.com/logfire-sh/cli/internal/prompter"
+	"github.com/logfire-sh/cli/pkg/cmdutil"
+	"github.com/logfire-sh/cli/pkg/cmdutil/helpers"
+	"github.com/logfire-sh/cli/pkg/cmdutil/ingest"
+	"github.com/logfire-sh/cli/pkg/iostreams"
+	"github.com/spf13/cobra"
+)
+
+type SendOptions struct {
+	IO       *iostreams.IOStreams
+	Prompter prompter.Prompter
+
+	HttpClient func() *http.Client
+	Config     func() (config.Config, error)
+
+	TeamId        string
+	Source        string
+	SourceToken   string
+	Format        string
+	Follow        bool
+	BatchSize     int
+	FlushInterval time.Duration
+	Files         []string
+
+	NoSpool     bool
+	SpoolDir    string
+	SpoolMaxMB  int64
+	SpoolMaxAge time.Duration
+}
+
+func NewSendCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &SendOptions{
+		IO:         f.IOStreams,
+		Prompter:   f.Prompter,
+		HttpClient: f.HttpClient,
+		Config:     f.Config,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "send [<file>...]",
+		Aliases: []string{"pipe"},
+		Short:   "Send local logs to a source",
+		Long: heredoc.Docf(`
+			Send log lines from standard input or files to a Logfire source.
+
+			Every line becomes one record. JSON and logfmt lines keep their fields; their
+			timestamp (dt, timestamp, time, ts or @timestamp) and message (message, msg or log)
+			are used for the record. Other lines are sent as plain messages stamped with the
+			time they were read. Use "-" to read standard input along with files.
+
+			The source token is looked up from --source, or taken from --source-token or
+			$LOGFIRE_SOURCE_TOKEN without any API call.
+
+			Batches are written to a spool directory on disk before they are sent, and only
+			removed from it once the ingestion endpoint acknowledged them. Batches that could
+			not be sent, because the network is down or the command was interrupted, are sent
+			by the next run for the same source. The oldest batches are dropped, with a
+			warning, once the spool exceeds --spool-max-size or --spool-max-age.
+
+			A batch the endpoint rejects is moved, with a warning, to %[1]s%[2]s%[1]s in the
+			spool directory, one record per line, so that the batches behind it are still sent.
+		`, "`", ingest.RejectedFile),
+		Example: heredoc.Doc(`
+			$ kubectl logs -f deploy/api | logfire send --source api
+			$ logfire send --source api --follow /var/log/app.log
+			$ logfire pipe --source-token <token> --format json < events.ndjson
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Files = args
+
+			if err := ingest.ValidateFormat(opts.Format); err != nil {
+				return cmdutil.FlagErrorWrap(err)
+			}
+			if opts.BatchSize <= 0 {
+				return cmdutil.FlagErrorf("--batch-size must be greater than 0")
+			}
+			if opts.SourceToken == "" {
+				opts.SourceToken = os.Getenv("LOGFIRE_SOURCE_TOKEN")
+			}
+			if opts.SourceToken == "" && opts.Source == "" {
+				return cmdutil.FlagErrorf("--source or --source-token is required")
+			}
+			if len(opts.Files) == 0 && opts.IO.IsStdinTTY() {
+				return cmdutil.FlagErrorf("no input: pipe logs into the command or pass files")
+			}
+
+			return sendRun(opts)
+		},
+		GroupID: "core",
+		// Sending with a source token needs no login; looking up --source reports a missing one itself.
+		Annotations: map[string]string{
+			"skipAuthCheck": "true",
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.TeamId, "team-name", "", "Team of the source (Default: the team of the current context).")
+	cmd.Flags().StringVarP(&opts.Source, "source", "s", "", "Name or ID of the source receiving the logs.")
+	cmd.Flags().StringVar(&opts.SourceToken, "source-token", "", "Token of the source receiving the logs.")
+	cmd.Flags().StringVar(&opts.Format, "format", ingest.FormatAuto, "Line format: {auto|text|json|logfmt}")
+	cmd.Flags().BoolVarP(&opts.Follow, "follow", "f", false, "Keep sending lines appended to the files.")
+	cmd.Flags().IntVar(&opts.BatchSize, "batch-size", 500, "Most records sent in one request.")
+	cmd.Flags().DurationVar(&opts.FlushInterval, "flush-interval", time.Second, "Longest time a record waits to be sent.")
+	cmd.Flags().BoolVar(&opts.NoSpool, "no-spool", false, "Send batches directly instead of spooling them on disk first.")
+	cmd.Flags().StringVar(&opts.SpoolDir, "spool-dir", "", "Directory of the spool (Default: a directory per source in the user cache directory).")
+	cmd.Flags().Int64Var(&opts.SpoolMaxMB, "spool-max-size", 256, "Most megabytes of unsent batches kept in the spool.")
+	cmd.Flags().DurationVar(&opts.SpoolMaxAge, "spool-max-age", 72*time.Hour, "Longest time unsent batches are kept in the spool.")
+
+	return cmd
+}
+
+func sendRun(opts *SendOptions) error {
+	cs := opts.IO.ColorScheme()
+	cfg, err := opts.Config()
+	if err != nil {
+		return err
+	}
+
+	if opts.SourceToken == "" {
+		opts.SourceToken, err = sourceToken(opts, cfg)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The ingestion endpoint authenticates with the source token, so it must not go through the
+	// transport of the factory, which would replace it with the access token of the user.
+	client := api.NewClient(nil, cfg.Get().GrpcIngestion, opts.SourceToken).WithTimeout(30 * time.Second)
+
+	onRetry := func(err error, attempt int) {
+		fmt.Fprintf(opts.IO.ErrOut, "%s Sending failed (attempt %d): %s, retrying\n", cs.WarningIcon(), attempt, err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	records, readErr := readRecords(ctx, opts)
+
+	batcher := ingest.NewBatcher(client)
+	batcher.BatchSize = opts.BatchSize
+	batcher.FlushInterval = opts.FlushInterval
+	batcher.OnRetry = onRetry
+
+	if opts.NoSpool {
+		stats, err := batcher.Run(records)
+		if err != nil {
+			cancel()
+			drain(records)
+			return fmt.Errorf("sent %d records before failing: %w", stats.Records, err)
+		}
+		if err := readErr(); err != nil {
+			return err
+		}
+		printSent(opts, stats)
+		return nil
+	}
+
+	if opts.SpoolDir == "" {
+		opts.SpoolDir, err = defaultSpoolDir(cfg.Get().GrpcIngestion, opts.SourceToken)
+		if err != nil {
+			return err
+		}
+	}
+
+	spool, err := ingest.OpenSpool(opts.SpoolDir, ingest.SpoolOptions{
+		MaxBytes:     opts.SpoolMaxMB * 1024 * 1024,
+		MaxAge:       opts.SpoolMaxAge,
+		SegmentBytes: ingest.DefaultSpoolOptions.SegmentBytes,
+		OnDrop: func(segment string, batches int) {
+			fmt.Fprintf(opts.IO.ErrOut, "%s Spool limit reached, dropped %d unsent batches of %s\n", cs.FailureIcon(), batches, segment)
+		},
+		OnReject: func(err error, records int) {
+			fmt.Fprintf(opts.IO.ErrOut, "%s A batch of %d records was rejected and moved to %s: %s\n",
+				cs.WarningIcon(), records, filepath.Join(opts.SpoolDir, ingest.RejectedFile), err)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to open spool %s: %w", opts.SpoolDir, err)
+	}
+	defer spool.Close()
+
+	if pending, err := spool.Pending(); err == nil && pending > 0 {
+		fmt.Fprintf(opts.IO.ErrOut, "%s Sending %d batches left over in %s\n", cs.IntermediateIcon(), pending, opts.SpoolDir)
+	}
+
+	shipped := make(chan error, 1)
+	go func() {
+		err := spool.Ship(ctx, client, batcher.RetryDelay, onRetry)
+		if err != nil {
+			// Stop reading input that could not be delivered anyway.
+			cancel()
+		}
+		shipped <- err
+	}()
+
+	batcher.Sender = spool
+	stats, err := batcher.Run(records)
+	if err != nil {
+		cancel()
+		drain(records)
+		return fmt.Errorf("failed to write to spool %s: %w", opts.SpoolDir, err)
+	}
+	spool.CloseWrite()
+
+	if err := <-shipped; err != nil {
+		pending, _ := spool.Pending()
+		if errors.Is(err, context.Canceled) {
+			err = errors.New("interrupted")
+		}
+		return fmt.Errorf("%w; %d batches are kept in %s and will be sent by the next run", err, pending, opts.SpoolDir)
+	}
+	if err := readErr(); err != nil {
+		return err
+	}
+
+	printSent(opts, stats)
+	return nil
+}
+
+// readRecords reads and parses the lines of all inputs until they end or ctx is done. The returned
+// function reports the first read error once the channel is closed.
+func readRecords(ctx context.Context, opts *SendOptions) (<-chan ingest.Record, func() error) {
+	lines := make(chan string, opts.BatchSize)
+	records := make(chan ingest.Record, opts.BatchSize)
+
+	var readErr error
+	var readErrOnce sync.Once
+
+	var readers sync.WaitGroup
+	for _, input := range inputs(opts.Files) {
+		readers.Add(1)
+		go func(input string) {
+			defer readers.Done()
+
+			var err error
+			switch {
+			case input == "-":
+				err = ingest.ReadLines(ctx, opts.IO.In, lines)
+			case opts.Follow:
+				err = ingest.FollowFile(ctx, input, false, lines)
+			default:
+				err = readFile(ctx, input, lines)
+			}
+			if err != nil {
+				readErrOnce.Do(func() { readErr = fmt.Errorf("%s: %w", input, err) })
+			}
+		}(input)
+	}
+
+	go func() {
+		readers.Wait()
+		close(lines)
+	}()
+
+	go func() {
+		defer close(records)
+		for line := range lines {
+			if line == "" {
+				continue
+			}
+			records <- ingest.Parse(line, opts.Format, time.Now())
+		}
+	}()
+
+	return records, func() error { return readErr }
+}
+
+// drain lets the readers notice a cancellation instead of blocking on a full channel.
+func drain(records <-chan ingest.Record) {
+	go func() {
+		for range records {
+		}
+	}()
+}
+
+func printSent(opts *SendOptions, stats ingest.Stats) {
+	if opts.IO.IsStderrTTY() {
+		cs := opts.IO.ColorScheme()
+		fmt.Fprintf(opts.IO.ErrOut, "%s Sent %d records in %d batches\n", cs.SuccessIcon(), stats.Records, stats.Batches)
+	}
+}
+
+// defaultSpoolDir keeps one spool per ingestion endpoint and source, so batches left over by a run
+// are only ever replayed to the source they were meant for.
+func defaultSpoolDir(endpoint, sourceToken string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(endpoint + "\n" + sourceToken))
+	return filepath.Join(cacheDir, "logfire", "spool", hex.EncodeToString(sum[:8])), nil
+}
+
+// sourceToken looks up the token of the source named by --source in the team of --team-name.
+func sourceToken(opts *SendOptions, cfg config.Config) (string, error) {
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
+	cs := opts.IO.ColorScheme()
+
+	teamId := cfg.Get().TeamId
+	if opts.TeamId != "" {
+		teamId = helpers.TeamNameToTeamId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
+		if teamId == "" {
+			return "", fmt.Errorf("no team with name: %s found", opts.TeamId)
+		}
+	}
+	if teamId == "" {
+		return "", cmdutil.FlagErrorf("--team-name is required")
+	}
+
+	sources, err := client.ListSources(teamId)
+	if err != nil {
+		return "", err
+	}
+
+	for _, source := range sources {
+		if source.ID == opts.Source || source.Name == opts.Source {
+			return source.SourceToken, nil
+		}
+	}
+	return "", fmt.Errorf("no source with name or id: %s found", opts.Source)
+}
+
+func inputs(files []string) []string {
+	if len(files) == 0 {
+		return []string{"-"}
+	}
+	return files
+}
+
+func readFile(ctx context.Context, path string, lines chan<- string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return ingest.ReadLines(ctx, file, lines)
+}
@@ -4,13 +4,12 @@ import (
 	"fmt"
 	"net/http"
 	"os"
-	"strings"
 
 	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	"github.com/logfire-sh/cli/pkg/iostreams"
 	"github.com/spf13/cobra"
 )
@@ -69,6 +68,8 @@ func SetPasswordRun(opts *SetPasswordOptions) {
 		fmt.Fprintf(opts.IO.ErrOut, "%s Failed to read config\n", cs.FailureIcon())
 	}
 
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
+
 	if opts.Interactive && opts.Password == "" {
 		opts.Password, err = opts.Prompter.Password("Enter a password:")
 		if err != nil {
@@ -82,9 +83,9 @@ func SetPasswordRun(opts *SetPasswordOptions) {
 		}
 	}
 
-	err = APICalls.SetPassword(cfg.Get().Token, cfg.Get().EndPoint, cfg.Get().ProfileID, opts.Password)
+	err = client.SetPassword(cfg.Get().ProfileID, opts.Password)
 	if err != nil {
-		if strings.Contains(err.Error(), "no such host") {
+		if api.IsConnectionError(err) {
 			fmt.Fprintf(opts.IO.ErrOut, "%s Error: Connection failed (Server down or no internet)\n", cs.FailureIcon())
 			os.Exit(0)
 			return
@@ -7,10 +7,10 @@ import (
 	"strings"
 
 	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	"github.com/logfire-sh/cli/pkg/cmdutil/helpers"
 	"github.com/logfire-sh/cli/pkg/cmdutil/pre_defined_prompters"
 	"github.com/logfire-sh/cli/pkg/iostreams"
@@ -76,12 +76,12 @@ func SettingsRun(opts *SettingsOptions) {
 		fmt.Fprintf(opts.IO.ErrOut, "%s Failed to read config\n", cs.FailureIcon())
 		return
 	}
-	client := http.Client{}
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
 
 	if opts.TeamId != "" {
-		opts.TeamId = helpers.TeamNameToTeamId(&client, cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+		opts.TeamId = helpers.TeamNameToTeamId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 
-		err = APICalls.UpdateFlag(cfg, cfg.Get().ProfileID, opts.TeamId, cfg.Get().EndPoint)
+		err = client.UpdateFlags(cfg.Get().ProfileID, opts.TeamId)
 		if err != nil {
 			fmt.Fprintf(opts.IO.ErrOut, "%s Failed to update default team\n", cs.FailureIcon())
 			return
@@ -111,9 +111,9 @@ func SettingsRun(opts *SettingsOptions) {
 	}
 
 	if opts.Interactive && opts.Choice == "Change default team" {
-		opts.TeamId, _ = pre_defined_prompters.AskTeamId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter)
+		opts.TeamId, _ = pre_defined_prompters.AskTeamId(client, opts.IO, cs, opts.Prompter)
 
-		err = APICalls.UpdateFlag(cfg, cfg.Get().ProfileID, opts.TeamId, cfg.Get().EndPoint)
+		err = client.UpdateFlags(cfg.Get().ProfileID, opts.TeamId)
 		if err != nil {
 			fmt.Fprintf(opts.IO.ErrOut, "%s Failed to update default team\n", cs.FailureIcon())
 			return
@@ -127,7 +127,9 @@ func SettingsRun(opts *SettingsOptions) {
 
 	loweredTheme := strings.ToLower(opts.Theme)
 
-	err = cfg.UpdateConfig(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, &loweredTheme)
+	err = cfg.UpdateConfig(func(c *config.AuthConfig) {
+		c.Theme = loweredTheme
+	})
 	if err != nil {
 		fmt.Fprintf(opts.IO.ErrOut, "%s Failed to apply settings\n", cs.FailureIcon())
 		return
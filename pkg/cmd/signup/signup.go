@@ -4,14 +4,13 @@ import (
 	"fmt"
 	"net/http"
 	"os"
-	"strings"
 
 	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
 	"github.com/logfire-sh/cli/pkg/cmd/login"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	"github.com/logfire-sh/cli/pkg/iostreams"
 	"github.com/spf13/cobra"
 )
@@ -82,6 +81,8 @@ func SignupRun(opts *SignupOptions) {
 		return
 	}
 
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
+
 	var email string
 	var interactive bool
 
@@ -117,9 +118,9 @@ func SignupRun(opts *SignupOptions) {
 		os.Exit(0)
 	}
 
-	msg, err := APICalls.SignupFlow(opts.Email, cfg.Get().EndPoint)
+	msg, err := client.Signup(opts.Email)
 	if err != nil {
-		if strings.Contains(err.Error(), "no such host") {
+		if api.IsConnectionError(err) {
 			fmt.Fprintf(opts.IO.ErrOut, "%s Error: Connection failed (Server down or no internet)\n", cs.FailureIcon())
 			os.Exit(0)
 		}
@@ -137,9 +138,9 @@ func SignupRun(opts *SignupOptions) {
 			}
 		}
 
-		err = login.TokenSignin(opts.IO, cfg, cs, opts.credentialToken, cfg.Get().EndPoint)
+		err = login.TokenSignin(opts.IO, client, cfg, cs, opts.credentialToken)
 		if err != nil {
-			if strings.Contains(err.Error(), "no such host") {
+			if api.IsConnectionError(err) {
 				fmt.Fprintf(opts.IO.ErrOut, "%s Error: Connection failed (Server down or no internet)\n", cs.FailureIcon())
 				os.Exit(0)
 				return
@@ -188,7 +189,9 @@ func OnboardingRun(opts *SignupOptions) error {
 		}
 	}
 
-	err = login.TokenSignin(opts.IO, cfg, cs, opts.credentialToken, cfg.Get().EndPoint)
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
+
+	err = login.TokenSignin(opts.IO, client, cfg, cs, opts.credentialToken)
 	if err != nil {
 		fmt.Fprintf(opts.IO.ErrOut, "%s Unable to sign in with token \n", cs.FailureIcon())
 		return err
@@ -215,7 +218,7 @@ func OnboardingRun(opts *SignupOptions) error {
 		}
 	}
 
-	err = APICalls.OnboardingFlow(cfg.Get().ProfileID, cfg.Get().Token, cfg.Get().EndPoint, opts.FirstName, opts.LastName, opts.Role)
+	err = client.Onboard(cfg.Get().ProfileID, opts.FirstName, opts.LastName)
 	if err != nil {
 		fmt.Fprintf(opts.IO.ErrOut, "\n%s %s", cs.FailureIcon(), err.Error())
 		return err
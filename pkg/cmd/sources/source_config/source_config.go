@@ -10,7 +10,7 @@ import (
 	"strings"
 
 	"github.com/fatih/color"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/pkg/cmdutil/pre_defined_prompters"
 
 	"github.com/MakeNowJust/heredoc"
@@ -79,8 +79,10 @@ func sourceListRun(opts *SourceConfigurationOptions) {
 		return
 	}
 
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
+
 	if opts.Interactive && opts.TeamId == "" && opts.SourceId == "" {
-		opts.TeamId, _ = pre_defined_prompters.AskTeamId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter)
+		opts.TeamId, _ = pre_defined_prompters.AskTeamId(client, opts.IO, cs, opts.Prompter)
 	} else {
 		if opts.TeamId == "" {
 			fmt.Fprintf(opts.IO.ErrOut, "%s team-name is required.\n", cs.FailureIcon())
@@ -93,11 +95,11 @@ func sourceListRun(opts *SourceConfigurationOptions) {
 		}
 	}
 
-	opts.SourceId, _ = pre_defined_prompters.AskSourceId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+	opts.SourceId, _ = pre_defined_prompters.AskSourceId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 
-	source, err := APICalls.GetSource(cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId, opts.SourceId)
+	source, err := client.GetSource(opts.TeamId, opts.SourceId)
 	if err != nil {
-		if strings.Contains(err.Error(), "no such host") {
+		if api.IsConnectionError(err) {
 			fmt.Fprintf(opts.IO.ErrOut, "\n%s Error: Connection failed (Server down or no internet)\n", cs.FailureIcon())
 			return
 		}
@@ -105,7 +107,7 @@ func sourceListRun(opts *SourceConfigurationOptions) {
 		return
 	}
 
-	configuration, err := APICalls.GetConfiguration(cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId, opts.SourceId)
+	configuration, err := client.GetSourceConfiguration(opts.TeamId, opts.SourceId)
 	if err != nil {
 		log.Fatal(err)
 	}
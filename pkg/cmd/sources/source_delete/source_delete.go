@@ -1,21 +1,18 @@
 package source_delete
 
 import (
-	"encoding/json"
-	"errors"
 	"fmt"
-	"io"
 	"net/http"
-	"os"
 	"strings"
 
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/pkg/cmdutil/helpers"
 	"github.com/logfire-sh/cli/pkg/cmdutil/pre_defined_prompters"
+	"github.com/logfire-sh/cli/pkg/cmdutil/sourceref"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
-	"github.com/logfire-sh/cli/pkg/cmd/sources/models"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
 	"github.com/logfire-sh/cli/pkg/iostreams"
 	"github.com/spf13/cobra"
@@ -58,6 +55,9 @@ func NewSourceDeleteCmd(f *cmdutil.Factory) *cobra.Command {
 
 			# start argument setup
 			$ logfire sources delete --team-name <team-name> --source-id <source-id>
+
+			# delete a source by name
+			$ logfire sources delete --team-name <team-name> --source-id staging-api
 		`),
 		Run: func(cmd *cobra.Command, args []string) {
 			if opts.IO.CanPrompt() {
@@ -81,7 +81,8 @@ func NewSourceDeleteCmd(f *cmdutil.Factory) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&opts.TeamId, "team-name", "", "Team ID for which the source is to be deleted.")
-	cmd.Flags().StringVar(&opts.SourceId, "source-id", "", "Source ID for which the source is to be deleted.")
+	cmd.Flags().StringVar(&opts.SourceId, "source-id", "", "Source to delete, given by ID or name.")
+	_ = cmd.RegisterFlagCompletionFunc("source-id", sourceref.CompletionFunc(f, "team-name"))
 	return cmd
 }
 
@@ -94,10 +95,10 @@ func sourceDeleteRun(opts *SourceDeleteOptions) {
 		return
 	}
 
-	client := http.Client{}
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
 
 	if opts.TeamId != "" {
-		teamId := helpers.TeamNameToTeamId(&client, cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+		teamId := helpers.TeamNameToTeamId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 
 		if teamId == "" {
 			fmt.Fprintf(opts.IO.ErrOut, "%s no team with name: %s found.\n", cs.FailureIcon(), opts.TeamId)
@@ -108,9 +109,9 @@ func sourceDeleteRun(opts *SourceDeleteOptions) {
 	}
 
 	if opts.Interactive && opts.TeamId == "" && opts.SourceId == "" {
-		opts.TeamId, _ = pre_defined_prompters.AskTeamId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter)
+		opts.TeamId, _ = pre_defined_prompters.AskTeamId(client, opts.IO, cs, opts.Prompter)
 
-		opts.SourceId, _ = pre_defined_prompters.AskSourceId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+		opts.SourceId, _ = pre_defined_prompters.AskSourceId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 
 	} else {
 		if opts.TeamId == "" || opts.SourceId == "" {
@@ -118,50 +119,31 @@ func sourceDeleteRun(opts *SourceDeleteOptions) {
 		}
 	}
 
-	err = deleteSource(opts.HttpClient(), cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId, opts.SourceId)
-	if err != nil {
-		fmt.Fprintf(opts.IO.ErrOut, "%s %s.\n", cs.FailureIcon(), err.Error())
-	}
-
-	fmt.Fprintf(opts.IO.Out, "%s Source deleted successfully.\n", cs.SuccessIcon())
-}
-
-func deleteSource(client *http.Client, token, endpoint string, teamId, sourceId string) error {
-	url := fmt.Sprintf(endpoint+"api/team/%s/source/%s", teamId, sourceId)
-	req, err := http.NewRequest("DELETE", url, nil)
-	if err != nil {
-		return err
-	}
-	req.Header.Set("User-Agent", "Logfire-cli")
-
-	req.Header.Set("Authorization", "Bearer "+token)
-
-	resp, err := client.Do(req)
-	if err != nil {
-		if strings.Contains(err.Error(), "no such host") {
-			fmt.Printf("\nError: Connection failed (Server down or no internet)\n")
-			os.Exit(1)
+	if opts.TeamId != "" && opts.SourceId != "" {
+		// A pattern could delete another source than meant as sources come and go, so only an
+		// exact name is accepted.
+		if strings.ContainsAny(opts.SourceId, "*?[=") {
+			fmt.Fprintf(opts.IO.ErrOut, "%s source-id must be the ID or the name of a source, not a pattern.\n", cs.FailureIcon())
+			return
 		}
-
-		return err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return err
+		sources, err := client.ListSources(opts.TeamId)
+		if err != nil {
+			fmt.Fprintf(opts.IO.ErrOut, "%s %s.\n", cs.FailureIcon(), err.Error())
+			return
+		}
+		source, err := sourceref.ResolveOne(sources, opts.SourceId)
+		if err != nil {
+			fmt.Fprintf(opts.IO.ErrOut, "%s %s.\n", cs.FailureIcon(), err.Error())
+			return
+		}
+		opts.SourceId = source.ID
 	}
 
-	var sourceResp models.SourceResponse
-
-	err = json.Unmarshal(body, &sourceResp)
+	err = client.DeleteSource(opts.TeamId, opts.SourceId)
 	if err != nil {
-		return err
-	}
-
-	if !sourceResp.IsSuccessful {
-		return errors.New("api error")
+		fmt.Fprintf(opts.IO.ErrOut, "%s %s.\n", cs.FailureIcon(), err.Error())
+		return
 	}
 
-	return nil
+	fmt.Fprintf(opts.IO.Out, "%s Source deleted successfully.\n", cs.SuccessIcon())
 }
@@ -5,7 +5,7 @@ import (
 	"net/http"
 	"os"
 
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/pkg/cmdutil/helpers"
 	"github.com/logfire-sh/cli/pkg/cmdutil/pre_defined_prompters"
 	"github.com/olekukonko/tablewriter"
@@ -24,6 +24,7 @@ type SourceListOptions struct {
 
 	HttpClient func() *http.Client
 	Config     func() (config.Config, error)
+	Exporter   *cmdutil.Exporter
 
 	Interactive bool
 
@@ -36,6 +37,7 @@ func NewSourceListCmd(f *cmdutil.Factory) *cobra.Command {
 		Prompter:    f.Prompter,
 		HttpClient:  f.HttpClient,
 		Config:      f.Config,
+		Exporter:    f.Exporter,
 		Interactive: false,
 	}
 
@@ -70,6 +72,9 @@ func NewSourceListCmd(f *cmdutil.Factory) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&opts.TeamId, "team-name", "", "Team ID for which the sources will be fetched.")
+
+	cmdutil.EnableExport(cmd)
+
 	return cmd
 }
 
@@ -81,10 +86,10 @@ func sourceListRun(opts *SourceListOptions) {
 		return
 	}
 
-	client := http.Client{}
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
 
 	if opts.TeamId != "" {
-		teamId := helpers.TeamNameToTeamId(&client, cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+		teamId := helpers.TeamNameToTeamId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 
 		if teamId == "" {
 			fmt.Fprintf(opts.IO.ErrOut, "%s no team with name: %s found.\n", cs.FailureIcon(), opts.TeamId)
@@ -95,7 +100,7 @@ func sourceListRun(opts *SourceListOptions) {
 	}
 
 	if opts.Interactive && opts.TeamId == "" {
-		opts.TeamId, _ = pre_defined_prompters.AskTeamId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter)
+		opts.TeamId, _ = pre_defined_prompters.AskTeamId(client, opts.IO, cs, opts.Prompter)
 	} else {
 		if opts.TeamId == "" {
 			fmt.Fprintf(opts.IO.ErrOut, "%s team-name is required.\n", cs.FailureIcon())
@@ -103,10 +108,14 @@ func sourceListRun(opts *SourceListOptions) {
 		}
 	}
 
-	sources, err := APICalls.GetAllSources(opts.HttpClient(), cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId)
+	sources, err := client.ListSources(opts.TeamId)
 	if err != nil {
 		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
 		return
+	} else if opts.Exporter.Enabled() {
+		if err := opts.Exporter.Write(opts.IO.Out, sources); err != nil {
+			fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+		}
 	} else if len(sources) > 0 {
 		table := tablewriter.NewWriter(os.Stdout)
 		table.SetHeader([]string{"Name", "Source-Id", "Platform", "Token"})
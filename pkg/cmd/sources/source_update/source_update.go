@@ -5,10 +5,10 @@ import (
 	"net/http"
 
 	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	"github.com/logfire-sh/cli/pkg/cmdutil/helpers"
 	"github.com/logfire-sh/cli/pkg/cmdutil/pre_defined_prompters"
 	"github.com/logfire-sh/cli/pkg/iostreams"
@@ -92,10 +92,10 @@ func sourceUpdateRun(opts *SourceUpdateOptions) {
 		return
 	}
 
-	client := http.Client{}
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
 
 	if opts.TeamId != "" {
-		teamId := helpers.TeamNameToTeamId(&client, cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+		teamId := helpers.TeamNameToTeamId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 
 		if teamId == "" {
 			fmt.Fprintf(opts.IO.ErrOut, "%s no team with name: %s found.\n", cs.FailureIcon(), opts.TeamId)
@@ -106,9 +106,9 @@ func sourceUpdateRun(opts *SourceUpdateOptions) {
 	}
 
 	if opts.Interactive && opts.TeamId == "" && opts.SourceId == "" && opts.SourceName == "" {
-		opts.TeamId, _ = pre_defined_prompters.AskTeamId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter)
+		opts.TeamId, _ = pre_defined_prompters.AskTeamId(client, opts.IO, cs, opts.Prompter)
 
-		opts.SourceId, _ = pre_defined_prompters.AskSourceId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+		opts.SourceId, _ = pre_defined_prompters.AskSourceId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 
 		opts.SourceName, err = opts.Prompter.Input("Enter new name for the source:", "")
 		if err != nil {
@@ -131,7 +131,7 @@ func sourceUpdateRun(opts *SourceUpdateOptions) {
 		}
 	}
 
-	source, err := APICalls.UpdateSource(opts.HttpClient(), cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId, opts.SourceId, opts.SourceName)
+	source, err := client.UpdateSource(opts.TeamId, opts.SourceId, opts.SourceName)
 	if err != nil {
 		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
 		return
@@ -10,6 +10,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/pkg/cmdutil/grpcutil"
 	"github.com/logfire-sh/cli/pkg/cmdutil/helpers"
 
@@ -19,7 +20,6 @@ import (
 	sourceModels "github.com/logfire-sh/cli/pkg/cmd/sources/models"
 	"github.com/logfire-sh/cli/pkg/cmd/sql/models"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	"github.com/logfire-sh/cli/pkg/cmdutil/pre_defined_prompters"
 	"github.com/logfire-sh/cli/pkg/iostreams"
 	pb "github.com/logfire-sh/cli/services/flink-service"
@@ -33,6 +33,7 @@ type SQLQueryOptions struct {
 	HttpClient func() *http.Client
 	Prompter   prompter.Prompter
 	Config     func() (config.Config, error)
+	Exporter   *cmdutil.Exporter
 
 	Interactive bool
 	TeamId      string
@@ -47,6 +48,7 @@ func NewCmdSql(f *cmdutil.Factory) *cobra.Command {
 		HttpClient: f.HttpClient,
 		Prompter:   f.Prompter,
 		Config:     f.Config,
+		Exporter:   f.Exporter,
 	}
 
 	cmd := &cobra.Command{
@@ -72,13 +74,16 @@ func NewCmdSql(f *cmdutil.Factory) *cobra.Command {
 	cmd.Flags().StringVarP(&opts.TeamId, "team-name", "t", "", "Team name to be queried.")
 	cmd.Flags().StringVarP(&opts.SQLQuery, "query", "q", "", "SQL Query.")
 
+	cmdutil.EnableExport(cmd)
+
 	return cmd
 }
 
 func GetRecommendations(opts *SQLQueryOptions, cfg config.Config) {
 	opts.IO.StartProgressIndicatorWithLabel("Getting recommendations, please wait...")
 
-	recommendations, err := APICalls.GetRecommendations(cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId, cfg.Get().Role)
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
+	recommendations, err := client.GetRecommendations(opts.TeamId, cfg.Get().Role)
 	if err != nil {
 		fmt.Fprintf(opts.IO.ErrOut, "%s", err)
 	}
@@ -138,10 +143,10 @@ func SqlQueryRun(opts *SQLQueryOptions) {
 		fmt.Fprintf(opts.IO.ErrOut, "%s Failed to read config\n", cs.FailureIcon())
 	}
 
-	client := http.Client{}
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
 
 	if opts.TeamId != "" {
-		teamId := helpers.TeamNameToTeamId(&client, cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+		teamId := helpers.TeamNameToTeamId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 
 		if teamId == "" {
 			fmt.Fprintf(opts.IO.ErrOut, "%s no team with name: %s found.\n", cs.FailureIcon(), opts.TeamId)
@@ -152,7 +157,7 @@ func SqlQueryRun(opts *SQLQueryOptions) {
 	}
 
 	if opts.Interactive && opts.TeamId == "" && opts.SQLQuery == "" {
-		opts.TeamId, _ = pre_defined_prompters.AskTeamId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter)
+		opts.TeamId, _ = pre_defined_prompters.AskTeamId(client, opts.IO, cs, opts.Prompter)
 
 		choices := []string{
 			"Receive AI-generated query recommendations.",
@@ -179,7 +184,7 @@ func SqlQueryRun(opts *SQLQueryOptions) {
 
 	var sources []sourceModels.Source
 
-	sources, err = APICalls.GetAllSources(opts.HttpClient(), cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId)
+	sources, err = client.ListSources(opts.TeamId)
 	if err != nil {
 		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
 		return
@@ -199,7 +204,7 @@ func SqlQueryRun(opts *SQLQueryOptions) {
 		TotalCount: 0,
 	}
 
-	filterService := grpcutil.NewFilterService()
+	filterService := grpcutil.NewFilterService(cfg)
 	defer filterService.CloseConnection()
 
 	response, err := filterService.Client.SubmitSQL(context.Background(), request)
@@ -210,6 +215,14 @@ func SqlQueryRun(opts *SQLQueryOptions) {
 
 	// fmt.Println("Query executed successfully.", response)
 
+	if opts.Exporter.Enabled() {
+		err = exportQuery(opts.IO, opts.Exporter, response.Data)
+		if err != nil {
+			fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+		}
+		return
+	}
+
 	showQuery(opts.IO, response.Data)
 
 }
@@ -259,6 +272,17 @@ func showQuery(_ *iostreams.IOStreams, records string) {
 	table.Render()
 }
 
+// exportQuery writes the query result records in the format selected with --output.
+func exportQuery(io *iostreams.IOStreams, exporter *cmdutil.Exporter, records string) error {
+	var parsedData models.SQLResponse
+	err := json.Unmarshal([]byte(records), &parsedData)
+	if err != nil {
+		return err
+	}
+
+	return exporter.Write(io.Out, parsedData.Records)
+}
+
 // createGrpcSource creates a proper sources to be used in grpc request
 func createGrpcSource(sources []sourceModels.Source) []*pb.Source {
 	var grpcSources []*pb.Source
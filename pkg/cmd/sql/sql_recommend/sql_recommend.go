@@ -7,10 +7,10 @@ import (
 	"regexp"
 
 	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/prompter"
-	"github.com/logfire-sh/cli/pkg/cmd/sql/models"
+	sqlcmd "github.com/logfire-sh/cli/pkg/cmd/sql"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	"github.com/logfire-sh/cli/pkg/cmdutil/pre_defined_prompters"
 	"github.com/spf13/cobra"
 )
@@ -20,11 +20,13 @@ type Temp struct {
 }
 
 func NewSQLRecommendCmd(f *cmdutil.Factory) *cobra.Command {
-	opts := &models.SQLQueryOptions{
+	opts := &sqlcmd.SQLQueryOptions{
 		IO: f.IOStreams,
 
 		HttpClient: f.HttpClient,
+		Prompter:   f.Prompter,
 		Config:     f.Config,
+		Exporter:   f.Exporter,
 	}
 
 	temp := &Temp{
@@ -48,7 +50,7 @@ func NewSQLRecommendCmd(f *cmdutil.Factory) *cobra.Command {
 				opts.Interactive = true
 			}
 
-			SqlRecommendRun(opts, temp, f)
+			SqlRecommendRun(opts, temp)
 		},
 	}
 	cmd.Flags().StringVarP(&opts.TeamId, "team-id", "t", "", "Team id to be queried.")
@@ -56,15 +58,17 @@ func NewSQLRecommendCmd(f *cmdutil.Factory) *cobra.Command {
 	return cmd
 }
 
-func SqlRecommendRun(opts *models.SQLQueryOptions, temp *Temp, f *cmdutil.Factory) {
+func SqlRecommendRun(opts *sqlcmd.SQLQueryOptions, temp *Temp) {
 	cs := opts.IO.ColorScheme()
 	cfg, err := opts.Config()
 	if err != nil {
 		fmt.Fprintf(opts.IO.ErrOut, "%s Failed to read config\n", cs.FailureIcon())
+		return
 	}
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
 
 	if opts.Interactive && opts.TeamId == "" && opts.Role == "" {
-		opts.TeamId, _ = pre_defined_prompters.AskTeamId(opts.HttpClient(), cfg, opts.IO, cs, temp.Prompter)
+		opts.TeamId, _ = pre_defined_prompters.AskTeamId(client, opts.IO, cs, temp.Prompter)
 
 		opts.Role, _ = temp.Prompter.Input("Please enter your role: example (Software Developer)", "")
 
@@ -73,7 +77,7 @@ func SqlRecommendRun(opts *models.SQLQueryOptions, temp *Temp, f *cmdutil.Factor
 			os.Exit(0)
 		}
 
-		recommendations, _ := APICalls.GetRecommendations(cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId, opts.Role)
+		recommendations, _ := client.GetRecommendations(opts.TeamId, opts.Role)
 
 		var options []string
 
@@ -102,14 +106,14 @@ Query: %s
 			log.Fatal("Query not found in the input.")
 		}
 
-		cmdutil.SqlQueryRun(opts, f)
+		sqlcmd.SqlQueryRun(opts)
 
 	} else {
 		if opts.TeamId == "" || opts.Role == "" {
 			fmt.Fprintf(opts.IO.ErrOut, "%s Team id and Role is required.\n", cs.FailureIcon())
 		} else {
 
-			recommendations, _ := APICalls.GetRecommendations(cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId, opts.Role)
+			recommendations, _ := client.GetRecommendations(opts.TeamId, opts.Role)
 
 			var options []string
 
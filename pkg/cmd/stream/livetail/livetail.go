@@ -4,19 +4,20 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"sort"
-	"strings"
+	"os"
+	"os/signal"
 	"time"
 
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/pkg/cmdutil/grpcutil"
+	"github.com/logfire-sh/cli/pkg/cmdutil/logformat"
+	"github.com/logfire-sh/cli/pkg/cmdutil/sourceref"
 
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	"github.com/logfire-sh/cli/pkg/cmdutil/filters"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
-	"github.com/logfire-sh/cli/pkg/cmd/sources/models"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
 	"github.com/logfire-sh/cli/pkg/iostreams"
 	pb "github.com/logfire-sh/cli/services/flink-service"
@@ -38,12 +39,16 @@ type LivetailOptions struct {
 	EndDateTimeFilter         string
 	SourceFilter              []string
 	SearchFilter              []string
+	Filter                    string
 	FieldBasedFilterName      string
 	FieldBasedFilterValue     string
 	FieldBasedFilterCondition string
 	SaveView                  bool
 	ViewName                  string
 	GUI                       bool
+	Format                    string
+	Fields                    []string
+	Pretty                    bool
 }
 
 func NewLivetailCmd(f *cmdutil.Factory) *cobra.Command {
@@ -66,6 +71,9 @@ func NewLivetailCmd(f *cmdutil.Factory) *cobra.Command {
 			$ logfire stream livetail --team-id <team-id> --source-id <source-id> --search <search>
 			  --field-name <field-name> --field-value <field-value> --field-condition <field-condition> 
 			  --start-date <start-date> --end-date <end-date> --save-view <true|default=false> --view-name <view-name>
+
+			# only server errors, except health checks
+			$ logfire stream livetail --team-id <team-id> --filter 'status>=500 -path:/health'
 		`),
 		Run: func(cmd *cobra.Command, args []string) {
 			if opts.IO.CanPrompt() {
@@ -77,16 +85,22 @@ func NewLivetailCmd(f *cmdutil.Factory) *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&opts.TeamId, "team-id", "t", "", "Team ID for which the sources will be fetched.")
-	cmd.Flags().StringSliceVarP(&opts.SourceFilter, "source-id", "s", nil, "Filter logs by sources. (Multiple sources can be specified)")
+	cmd.Flags().StringSliceVarP(&opts.SourceFilter, "source-id", "s", nil, "Filter logs by sources, "+sourceref.Help+". (Multiple sources can be specified)")
+	cmd.Flags().StringVar(&opts.Filter, "filter", "", "Filter logs by an expression, such as 'level=error status>=500 -path:/health'.")
 	cmd.Flags().StringSliceVarP(&opts.SearchFilter, "search", "q", nil, "Filter logs by search.  (Multiple search queries can be specified)")
 	cmd.Flags().StringVarP(&opts.FieldBasedFilterName, "field-name", "n", "", "Filter logs by Fields Name (Name, Value, Condition must be specified)")
 	cmd.Flags().StringVarP(&opts.FieldBasedFilterValue, "field-value", "v", "", "Filter logs by Fields Value (Name, Value, Condition must be specified)")
 	cmd.Flags().StringVarP(&opts.FieldBasedFilterCondition, "field-condition", "c", "", "Filter logs by Fields condition (Name, Value, Condition must be specified)")
-	cmd.Flags().StringVarP(&opts.StartDateTimeFilter, "start-date", "", "", "Filter logs by Start date (Example: --start-date now-2d) should give logs from 2Days ago.")
-	cmd.Flags().StringVarP(&opts.EndDateTimeFilter, "end-date", "e", "", "Filter logs by End date (Start date should be specified).")
+	cmd.Flags().StringVarP(&opts.StartDateTimeFilter, "start-date", "", "", "Filter logs by Start date, such as now-2d, now-1h30m, yesterday or 2024-05-01T22:00:00Z.")
+	cmd.Flags().StringVarP(&opts.EndDateTimeFilter, "end-date", "e", "", "Filter logs by End date, in the same forms as --start-date.")
 	cmd.Flags().BoolVarP(&opts.SaveView, "save-view", "", false, "Do you want to save the filters as a View. (Default: false)")
 	cmd.Flags().StringVarP(&opts.ViewName, "view-name", "", "", "Enter a name for the view.")
 	cmd.Flags().BoolVarP(&opts.GUI, "gui", "", false, "Enable GUI.")
+	cmd.Flags().StringVar(&opts.Format, "format", "", "Line format: {default|short|full|raw|logfmt} or a Go template such as '{{.Clock}} {{.Field \"user_id\"}} {{.Message}}'.")
+	cmd.Flags().StringSliceVar(&opts.Fields, "fields", nil, "Print these fields of JSON or logfmt messages as aligned columns, such as dt,level,user.id,message.")
+	cmd.Flags().BoolVar(&opts.Pretty, "pretty", false, "Print the fields of JSON or logfmt messages on indented lines below each record.")
+
+	_ = cmd.RegisterFlagCompletionFunc("source-id", sourceref.CompletionFunc(f, "team-id"))
 
 	return cmd
 }
@@ -108,10 +122,30 @@ func livetailRun(opts *LivetailOptions) {
 		return
 	}
 
-	if opts.Interactive && opts.TeamId != "" && opts.SourceFilter == nil && opts.SearchFilter == nil && opts.FieldBasedFilterName == "" &&
+	query, err := filters.FlagQuery(opts.Filter, opts.SearchFilter, opts.FieldBasedFilterName, opts.FieldBasedFilterValue, opts.FieldBasedFilterCondition)
+	if err != nil {
+		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+		return
+	}
+
+	startDate, endDate, err := filters.ParseTimeRange(opts.StartDateTimeFilter, opts.EndDateTimeFilter, time.Now())
+	if err != nil {
+		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+		return
+	}
+
+	printer, err := logformat.NewPrinter(cs, opts.Format, opts.Fields, opts.Pretty)
+	if err != nil {
+		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+		return
+	}
+
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
+
+	if opts.Interactive && opts.TeamId != "" && opts.SourceFilter == nil && opts.SearchFilter == nil && opts.Filter == "" && opts.FieldBasedFilterName == "" &&
 		opts.FieldBasedFilterValue == "" && opts.FieldBasedFilterCondition == "" && opts.StartDateTimeFilter == "" && opts.EndDateTimeFilter == "" {
 
-		//opts.TeamId, _ = pre_defined_prompters.AskTeamId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter)
+		//opts.TeamId, _ = pre_defined_prompters.AskTeamId(client, opts.IO, cs, opts.Prompter)
 		//
 		//filterChoice, _ := opts.Prompter.Confirm("Do you want apply any filter?", false)
 		//
@@ -119,7 +153,7 @@ func livetailRun(opts *LivetailOptions) {
 		//	filterBySource, _ := opts.Prompter.Confirm("Do you want to filter by source?", false)
 		//
 		//	if filterBySource {
-		//		opts.SourceFilter, _ = pre_defined_prompters.AskSourceIds(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+		//		opts.SourceFilter, _ = pre_defined_prompters.AskSourceIds(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 		//	}
 		//
 		//	filterBySearch, _ := opts.Prompter.Confirm("Do you want to filter by Text search? (You can enter multiple separate words separated by a comma)", false)
@@ -172,91 +206,52 @@ func livetailRun(opts *LivetailOptions) {
 		}
 	}
 
-	var sources []models.Source
-
-	if opts.SourceFilter == nil {
-		sources, err = APICalls.GetAllSources(opts.HttpClient(), cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId)
+	sources, err := client.ListSources(opts.TeamId)
+	if err != nil {
+		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+		return
+	}
+	if opts.SourceFilter != nil {
+		sources, err = sourceref.Resolve(sources, opts.SourceFilter)
 		if err != nil {
 			fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
 			return
 		}
-	} else {
-		for _, sourceId := range opts.SourceFilter {
-			source, err := APICalls.GetSource(cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId, sourceId)
-			if err != nil {
-				fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
-				return
-			}
-			sources = append(sources, source)
-		}
 	}
 
 	if opts.SaveView {
-		err := APICalls.CreateView(cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId, sources, opts.SearchFilter,
-			opts.FieldBasedFilterName, opts.FieldBasedFilterValue, opts.FieldBasedFilterCondition,
-			opts.StartDateTimeFilter, opts.EndDateTimeFilter, opts.ViewName)
+		err := client.CreateView(opts.TeamId, sources, query, startDate, endDate, opts.ViewName)
 		if err != nil {
 			return
 		}
 	}
 
-	if opts.StartDateTimeFilter == "" {
+	if startDate.IsZero() {
 		request.DateTimeFilter.StartTimeStamp = timestamppb.New(time.Now().Add(-1 * time.Second))
+	} else {
+		request.DateTimeFilter.StartTimeStamp = timestamppb.New(startDate)
 	}
 
-	if opts.StartDateTimeFilter != "" {
-		request.DateTimeFilter.StartTimeStamp = timestamppb.New(filters.ShortDateTimeToGoDate(opts.StartDateTimeFilter))
-
-		if opts.EndDateTimeFilter != "" {
-			request.DateTimeFilter.EndTimeStamp = timestamppb.New(filters.ShortDateTimeToGoDate(opts.EndDateTimeFilter))
-		}
-	}
-
-	if opts.SearchFilter != nil {
-		request.SearchQueries = append(request.SearchQueries, opts.SearchFilter...)
-	}
-
-	if opts.FieldBasedFilterName != "" && opts.FieldBasedFilterValue != "" && opts.FieldBasedFilterCondition != "" {
-		request.FieldBasedFilters = append(request.FieldBasedFilters, &pb.FieldBasedFilter{
-			FieldName:  opts.FieldBasedFilterName,
-			FieldValue: opts.FieldBasedFilterValue,
-			Operator:   pb.FieldBasedFilter_Operator(pb.FieldBasedFilter_Operator_value[opts.FieldBasedFilterCondition]),
-		})
+	if !endDate.IsZero() {
+		request.DateTimeFilter.EndTimeStamp = timestamppb.New(endDate)
 	}
 
-	pbSources := grpcutil.CreateGrpcSource(sources)
-	var sourcesOffset = make(map[string]uint64)
+	query.Apply(request)
 
-	request.Sources = pbSources
+	request.Sources = grpcutil.CreateGrpcSource(sources)
 	request.AccountID = cfg.Get().AccountId
 	request.TeamID = opts.TeamId
 
-	filterService := grpcutil.NewFilterService()
+	filterService := grpcutil.NewFilterService(cfg)
 	defer filterService.CloseConnection()
 
-	for {
-		response, err := filterService.Client.GetFilteredData(context.Background(), request)
-		if err != nil {
-			//log.Fatal(err)
-			continue
-		}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
-		if len(response.Records) > 0 {
-			sort.Sort(grpcutil.ByOffset(response.Records))
-			sourcesOffset = grpcutil.GetOffsets(sourcesOffset, response.Records)
-			pbSources = grpcutil.AddOffset(pbSources, sourcesOffset)
-			showLogs(opts.IO, response.Records)
-		}
-		time.Sleep(500 * time.Millisecond)
-	}
-}
-
-// Convert logs with colors
-func showLogs(io *iostreams.IOStreams, records []*pb.FilteredRecord) {
-	cs := io.ColorScheme()
-	for _, record := range records {
-
-		fmt.Fprintf(io.Out, "%s %s [%s] %s\n",
-			cs.Yellow(record.Dt), cs.Green(record.SourceName), cs.Cyan(strings.ToUpper(record.Level)), record.Message)
+	err = filterService.StreamRecords(ctx, request, func(records []*pb.FilteredRecord) error {
+		return printer.Print(opts.IO.Out, records)
+	})
+	if err != nil {
+		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
 	}
 }
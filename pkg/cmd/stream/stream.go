@@ -1,12 +1,16 @@
 package stream
 
 import (
+	"fmt"
 	"log"
 	"net/http"
 
+	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/gui"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
+	"github.com/logfire-sh/cli/internal/theme"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
 	"github.com/logfire-sh/cli/pkg/cmdutil/pre_defined_prompters"
 	"github.com/logfire-sh/cli/pkg/iostreams"
@@ -34,8 +38,33 @@ func NewCmdStream(f *cmdutil.Factory) *cobra.Command {
 	}
 
 	cmd := &cobra.Command{
-		Use:     "stream",
-		Short:   "Logs streaming",
+		Use:   "stream",
+		Short: "Logs streaming",
+		Long: heredoc.Docf(`
+			Stream the logs of a team in a terminal UI.
+
+			The colours of the UI and of the logs come from the %[1]stheme%[1]s setting, dark or light,
+			changed by the YAML theme file at $LOGFIRE_THEME or ~/.logfire-theme.yml, such as:
+
+			    base: dark
+			    timestamp: "#87afaf"
+			    levels:
+			      warning: orange
+			      error: bold red
+			    ui:
+			      background: black
+
+			The keys and commands of the UI can be rebound in the YAML keymap file at
+			$LOGFIRE_KEYMAP or ~/.logfire-keymap.yml, such as:
+
+			    keys:
+			      up: [Up, k]
+			      down: [Down, j]
+			      pause: [p]
+			    commands:
+			      source: [s]
+			      quit: [q, quit, exit]
+		`, "`"),
 		GroupID: "core",
 		Run: func(cmd *cobra.Command, args []string) {
 			if opts.IO.CanPrompt() {
@@ -56,17 +85,34 @@ func PromptStreamRun(opts *PromptStreamOptions) {
 
 	if opts.Interactive {
 		cs := opts.IO.ColorScheme()
-		cfg, _ := config.NewConfig()
+		cfg, err := opts.Config()
+		if err != nil {
+			fmt.Fprintf(opts.IO.ErrOut, "%s Failed to read config\n", cs.FailureIcon())
+			return
+		}
+
+		// An invalid theme falls back to the built-in palette, as it does for the other commands.
+		palette, err := theme.Load(cfg.Get().Theme, theme.DefaultPath())
+		if err != nil {
+			fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.WarningIcon(), err)
+		}
+		keymap, err := gui.LoadKeymap(gui.KeymapPath())
+		if err != nil {
+			fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err)
+			return
+		}
 
-		teamid, _ := pre_defined_prompters.AskTeamId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter)
+		client := api.NewClientFromConfig(opts.HttpClient(), cfg)
+		teamid, _ := pre_defined_prompters.AskTeamId(client, opts.IO, cs, opts.Prompter)
 
-		err := cfg.UpdateConfig(nil, nil, nil, nil,
-			nil, &teamid, &cfg.Get().AccountId, nil, nil, nil, nil)
+		err = cfg.UpdateConfig(func(c *config.AuthConfig) {
+			c.TeamId = teamid
+		})
 		if err != nil {
 			return
 		}
 
-		ui := gui.NewUI(cfg)
+		ui := gui.NewUI(cfg, palette, keymap)
 		if err := ui.Run(); err != nil {
 			log.Fatal(err)
 		}
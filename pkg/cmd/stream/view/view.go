@@ -5,18 +5,18 @@ import (
 	"fmt"
 	"net/http"
 	"os"
-	"sort"
-	"strings"
+	"os/signal"
 	"time"
 
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/pkg/cmdutil/grpcutil"
 	"github.com/logfire-sh/cli/pkg/cmdutil/helpers"
+	"github.com/logfire-sh/cli/pkg/cmdutil/logformat"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	"github.com/logfire-sh/cli/pkg/iostreams"
 	pb "github.com/logfire-sh/cli/services/flink-service"
 	"github.com/spf13/cobra"
@@ -40,6 +40,9 @@ type ViewStreamOptions struct {
 	FieldBasedFilterName      string
 	FieldBasedFilterValue     string
 	FieldBasedFilterCondition string
+	Format                    string
+	Fields                    []string
+	Pretty                    bool
 }
 
 func NewViewStreamOptionsCmd(f *cmdutil.Factory) *cobra.Command {
@@ -73,6 +76,9 @@ func NewViewStreamOptionsCmd(f *cmdutil.Factory) *cobra.Command {
 
 	cmd.Flags().StringVarP(&opts.TeamId, "team-name", "t", "", "Team name for which the sources will be fetched.")
 	cmd.Flags().StringVarP(&opts.ViewId, "view-id", "v", "", "Team ID for which the sources will be fetched.")
+	cmd.Flags().StringVar(&opts.Format, "format", "", "Line format: {default|short|full|raw|logfmt} or a Go template such as '{{.Clock}} {{.Field \"user_id\"}} {{.Message}}'.")
+	cmd.Flags().StringSliceVar(&opts.Fields, "fields", nil, "Print these fields of JSON or logfmt messages as aligned columns, such as dt,level,user.id,message.")
+	cmd.Flags().BoolVar(&opts.Pretty, "pretty", false, "Print the fields of JSON or logfmt messages on indented lines below each record.")
 	return cmd
 }
 
@@ -93,10 +99,16 @@ func ViewStreamRun(opts *ViewStreamOptions) {
 		return
 	}
 
-	client := http.Client{}
+	printer, err := logformat.NewPrinter(cs, opts.Format, opts.Fields, opts.Pretty)
+	if err != nil {
+		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+		return
+	}
+
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
 
 	if opts.TeamId != "" {
-		teamId := helpers.TeamNameToTeamId(&client, cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+		teamId := helpers.TeamNameToTeamId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 
 		if teamId == "" {
 			fmt.Fprintf(opts.IO.ErrOut, "%s no team with name: %s found.\n", cs.FailureIcon(), opts.TeamId)
@@ -116,46 +128,27 @@ func ViewStreamRun(opts *ViewStreamOptions) {
 		os.Exit(0)
 	}
 
-	view, err := APICalls.GetView(cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId, opts.ViewId)
+	view, err := client.GetView(opts.TeamId, opts.ViewId)
 	if err != nil {
 		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
 		return
 	}
 
-	pbSources := grpcutil.CreateGrpcSource(view.SourcesFilter)
-	var sourcesOffset = make(map[string]uint64)
-
 	request.AccountID = cfg.Get().AccountId
 	request.TeamID = opts.TeamId
-	request.Sources = pbSources
+	request.Sources = grpcutil.CreateGrpcSource(view.SourcesFilter)
 	request.ViewID = view.Id
 
-	filterService := grpcutil.NewFilterService()
+	filterService := grpcutil.NewFilterService(cfg)
 	defer filterService.CloseConnection()
 
-	for {
-		response, err := filterService.Client.GetFilteredData(context.Background(), request)
-		if err != nil {
-			continue
-		}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
-		if len(response.Records) > 0 {
-			sort.Sort(grpcutil.ByOffset(response.Records))
-			sourcesOffset = grpcutil.GetOffsets(sourcesOffset, response.Records)
-			pbSources = grpcutil.AddOffset(pbSources, sourcesOffset)
-			showLogs(opts.IO, response.Records)
-		}
-		time.Sleep(500 * time.Millisecond)
-	}
-}
-
-// Convert logs with colors
-func showLogs(io *iostreams.IOStreams, records []*pb.FilteredRecord) {
-	cs := io.ColorScheme()
-
-	for _, record := range records {
-
-		fmt.Fprintf(io.Out, "%s %s [%s] %s\n",
-			cs.Yellow(record.Dt), cs.Green(record.SourceName), cs.Cyan(strings.ToUpper(record.Level)), record.Message)
+	err = filterService.StreamRecords(ctx, request, func(records []*pb.FilteredRecord) error {
+		return printer.Print(opts.IO.Out, records)
+	})
+	if err != nil {
+		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
 	}
 }
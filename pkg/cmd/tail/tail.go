@@ -2,24 +2,27 @@ package tail
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
-	"sort"
-	"strings"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/pkg/cmdutil/helpers"
+	"github.com/logfire-sh/cli/pkg/cmdutil/logformat"
 	"github.com/logfire-sh/cli/pkg/cmdutil/pre_defined_prompters"
+	"github.com/logfire-sh/cli/pkg/cmdutil/sourceref"
 
 	"github.com/logfire-sh/cli/pkg/cmdutil/grpcutil"
 
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	"github.com/logfire-sh/cli/pkg/cmdutil/filters"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
-	"github.com/logfire-sh/cli/pkg/cmd/sources/models"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
 	"github.com/logfire-sh/cli/pkg/iostreams"
 	pb "github.com/logfire-sh/cli/services/flink-service"
@@ -33,6 +36,7 @@ type TailOptions struct {
 
 	HttpClient func() *http.Client
 	Config     func() (config.Config, error)
+	Exporter   *cmdutil.Exporter
 
 	Interactive bool
 	Interacted  bool
@@ -42,20 +46,46 @@ type TailOptions struct {
 	EndDateTimeFilter         string
 	SourceFilter              []string
 	SearchFilter              []string
+	Filter                    string
 	FieldBasedFilterName      string
 	FieldBasedFilterValue     string
 	FieldBasedFilterCondition string
 	SaveView                  bool
 	ViewName                  string
 	GUI                       bool
+	Checkpoint                string
+	CheckpointStart           string
+	Format                    string
+	Fields                    []string
+	Pretty                    bool
+	Grep                      []string
+	Exclude                   []string
+	Highlight                 []string
+	Context                   int
+	Until                     []string
+	FailOn                    []string
+	MaxRecords                int
+	Timeout                   time.Duration
+	Stats                     bool
 }
 
+// Exit codes of tail when --fail-on, --timeout or --max-records end it.
+const (
+	exitFailOn     = 2
+	exitTimeout    = 3
+	exitMaxRecords = 4
+)
+
+// errStopped ends the stream once the --until, --fail-on or --max-records condition is met.
+var errStopped = errors.New("stopped")
+
 func NewTailCmd(f *cmdutil.Factory) *cobra.Command {
 	opts := &TailOptions{
 		IO:          f.IOStreams,
 		Prompter:    f.Prompter,
 		HttpClient:  f.HttpClient,
 		Config:      f.Config,
+		Exporter:    f.Exporter,
 		Interactive: false,
 	}
 
@@ -64,40 +94,90 @@ func NewTailCmd(f *cmdutil.Factory) *cobra.Command {
 		Short: "Show tail ",
 		Long: heredoc.Docf(`
 			Get live stream of logs coming from multiple sources.
+
+			With --until, --fail-on, --max-records or --timeout, tail ends on its own, for scripts
+			waiting on a log. The exit code tells why:
+
+			  0  --until matched, or --max-records or --timeout ended a tail without --until
+			  1  tail failed
+			  2  --fail-on matched
+			  3  --timeout elapsed before --until matched
+			  4  --max-records logs were printed before --until matched
 		`),
 		Example: heredoc.Doc(`
 			# start stream of logs
 			$ logfire stream tail --team-name <team-name> --source-id <source-id> --search <search>
 			  --field-name <field-name> --field-value <field-value> --field-condition <field-condition>
 			  --start-date <start-date> --end-date <end-date> --save-view <true|default=false> --view-name <view-name>
+
+			# the api sources and every nginx source, by name instead of ID
+			$ logfire tail --team-name <team-name> --source-id 'api-*' --source-id platform=nginx
+
+			# only errors of the orders service, except health checks
+			$ logfire tail --team-name <team-name> --filter 'level=error service=orders -path:/health'
+
+			# the user and status of every JSON record, in columns
+			$ logfire tail --team-name <team-name> --fields dt,level,user.id,status,message
+
+			# server errors with two logs of context, without health checks
+			$ logfire tail --team-name <team-name> --grep 'status=5\d\d' --exclude 'GET /health' -C 2
+
+			# JSON records expanded into coloured fields, with stack traces folded
+			$ logfire tail --team-name <team-name> --pretty
+
+			# wait for the new version to start, failing on a panic or after two minutes
+			$ logfire tail --team-name <team-name> --until 'version=1\.4\.2 ready' --fail-on panic --timeout 2m
+
+			# watch the rate, levels and lag of every source while tailing
+			$ logfire tail --team-name <team-name> --stats
+
+			# export every record once, resuming where the previous run stopped
+			$ timeout 5m logfire tail --team-name <team-name> --checkpoint export.checkpoint --output ndjson >> export.ndjson
 		`),
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			if opts.IO.CanPrompt() {
 				opts.Interactive = true
 			}
 
-			tailRun(opts)
+			return tailRun(opts)
 		},
 	}
 
 	cmd.Flags().StringVarP(&opts.TeamId, "team-name", "t", "", "Team name for which the sources will be fetched.")
-	cmd.Flags().StringSliceVarP(&opts.SourceFilter, "source-id", "s", nil, "Filter logs by sources. (Multiple sources can be specified)")
+	cmd.Flags().StringSliceVarP(&opts.SourceFilter, "source-id", "s", nil, "Filter logs by sources, "+sourceref.Help+". (Multiple sources can be specified)")
+	cmd.Flags().StringVar(&opts.Filter, "filter", "", "Filter logs by an expression, such as 'level=error status>=500 -path:/health'.")
 	cmd.Flags().StringSliceVarP(&opts.SearchFilter, "search", "q", nil, "Filter logs by search.  (Multiple search queries can be specified)")
 	cmd.Flags().StringVarP(&opts.FieldBasedFilterName, "field-name", "n", "", "Filter logs by Fields Name (Name, Value, Condition must be specified)")
 	cmd.Flags().StringVarP(&opts.FieldBasedFilterValue, "field-value", "v", "", "Filter logs by Fields Value (Name, Value, Condition must be specified)")
 	cmd.Flags().StringVarP(&opts.FieldBasedFilterCondition, "field-condition", "c", "", "Filter logs by Fields condition (Name, Value, Condition must be specified)")
-	cmd.Flags().StringVarP(&opts.StartDateTimeFilter, "start-date", "", "", "Filter logs by Start date (Example: --start-date now-2d) should give logs from 2Days ago.")
-	cmd.Flags().StringVarP(&opts.EndDateTimeFilter, "end-date", "e", "", "Filter logs by End date (Start date should be specified).")
+	cmd.Flags().StringVarP(&opts.StartDateTimeFilter, "start-date", "", "", "Filter logs by Start date, such as now-2d, now-1h30m, yesterday or 2024-05-01T22:00:00Z.")
+	cmd.Flags().StringVarP(&opts.EndDateTimeFilter, "end-date", "e", "", "Filter logs by End date, in the same forms as --start-date.")
 	cmd.Flags().BoolVarP(&opts.SaveView, "save-view", "", false, "Do you want to save the filters as a View. (Default: false)")
 	cmd.Flags().StringVarP(&opts.ViewName, "view-name", "", "", "Enter a name for the view.")
 	cmd.Flags().BoolVarP(&opts.GUI, "gui", "", false, "Enable GUI.")
+	cmd.Flags().StringVar(&opts.Format, "format", "", "Line format: {default|short|full|raw|logfmt} or a Go template such as '{{.Clock}} {{.Field \"user_id\"}} {{.Message}}'.")
+	cmd.Flags().StringSliceVar(&opts.Fields, "fields", nil, "Print these fields of JSON or logfmt messages as aligned columns, such as dt,level,user.id,message.")
+	cmd.Flags().BoolVar(&opts.Pretty, "pretty", false, "Print the fields of JSON or logfmt messages on indented lines below each record.")
+	cmd.Flags().StringArrayVar(&opts.Grep, "grep", nil, "Print only the logs whose message matches this regular expression. (Can be given several times)")
+	cmd.Flags().StringArrayVar(&opts.Exclude, "exclude", nil, "Hide the logs whose message matches this regular expression. (Can be given several times)")
+	cmd.Flags().StringArrayVar(&opts.Highlight, "highlight", nil, "Highlight the matches of this regular expression, besides those of --grep. (Can be given several times)")
+	cmd.Flags().IntVarP(&opts.Context, "context", "C", 0, "Print this many logs before and after every --grep match.")
+	cmd.Flags().StringArrayVar(&opts.Until, "until", nil, "Exit once a log message matches this regular expression. (Can be given several times)")
+	cmd.Flags().StringArrayVar(&opts.FailOn, "fail-on", nil, "Exit with code 2 once a log message matches this regular expression. (Can be given several times)")
+	cmd.Flags().IntVar(&opts.MaxRecords, "max-records", 0, "Exit after printing this many logs.")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", 0, "Exit after this long, such as 30s or 2m.")
+	cmd.Flags().BoolVar(&opts.Stats, "stats", false, "Show the logs per second of every source, the levels, the lag and the reconnects, refreshed on a terminal, and a summary at the end.")
+	cmd.Flags().StringVar(&opts.Checkpoint, "checkpoint", "", "File keeping the offset of every source, to resume where the previous run stopped.")
+	cmd.Flags().StringVar(&opts.CheckpointStart, "checkpoint-start", "latest", "Where sources missing from the checkpoint start: {latest|earliest}")
+
+	_ = cmd.RegisterFlagCompletionFunc("source-id", sourceref.CompletionFunc(f, "team-name"))
+
+	cmdutil.EnableExport(cmd)
 
 	return cmd
 }
 
-func tailRun(opts *TailOptions) {
-	conditions := []string{"CONTAINS", "DOES_NOT_CONTAIN", "EQUALS", "NOT_EQUALS", "GREATER_THAN", "GREATER_THAN_EQUALS", "LESS_THAN", "LESS_THAN_EQUALS"}
-
+func tailRun(opts *TailOptions) error {
 	var request = &pb.FilterRequest{
 		DateTimeFilter:    &pb.DateTimeFilter{},
 		FieldBasedFilters: []*pb.FieldBasedFilter{},
@@ -111,119 +191,339 @@ func tailRun(opts *TailOptions) {
 	cfg, err := opts.Config()
 	if err != nil {
 		fmt.Fprintf(opts.IO.ErrOut, "%s Failed to read config\n", cs.FailureIcon())
-		return
+		return cmdutil.SilentError
 	}
 
-	client := http.Client{}
+	query, err := filters.FlagQuery(opts.Filter, opts.SearchFilter, opts.FieldBasedFilterName, opts.FieldBasedFilterValue, opts.FieldBasedFilterCondition)
+	if err != nil {
+		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+		return cmdutil.SilentError
+	}
+
+	startDate, endDate, err := filters.ParseTimeRange(opts.StartDateTimeFilter, opts.EndDateTimeFilter, time.Now())
+	if err != nil {
+		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+		return cmdutil.SilentError
+	}
+
+	printer, err := logformat.NewPrinter(cs, opts.Format, opts.Fields, opts.Pretty)
+	if err == nil && opts.Exporter.Enabled() && (opts.Format != "" || opts.Fields != nil || opts.Pretty) {
+		err = fmt.Errorf("--format, --fields and --pretty cannot be used with --output, --jq or --template")
+	}
+	if err != nil {
+		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+		return cmdutil.SilentError
+	}
+
+	grep, err := tailGrep(opts, printer)
+	if err != nil {
+		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+		return cmdutil.SilentError
+	}
+
+	if opts.MaxRecords < 0 || opts.Timeout < 0 {
+		fmt.Fprintf(opts.IO.ErrOut, "%s --max-records and --timeout must not be negative.\n", cs.FailureIcon())
+		return cmdutil.SilentError
+	}
+
+	until, err := logformat.CompileRegexps("--until", opts.Until)
+	if err != nil {
+		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+		return cmdutil.SilentError
+	}
+	failOn, err := logformat.CompileRegexps("--fail-on", opts.FailOn)
+	if err != nil {
+		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+		return cmdutil.SilentError
+	}
+	watch := &grpcutil.Watch{Until: until, FailOn: failOn, MaxRecords: opts.MaxRecords}
+
+	if opts.CheckpointStart != "latest" && opts.CheckpointStart != "earliest" {
+		fmt.Fprintf(opts.IO.ErrOut, "%s --checkpoint-start must be latest or earliest.\n", cs.FailureIcon())
+		return cmdutil.SilentError
+	}
+
+	var checkpoint *grpcutil.Checkpoint
+	if opts.Checkpoint != "" {
+		checkpoint, err = grpcutil.LoadCheckpoint(opts.Checkpoint)
+		if err != nil {
+			fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+			return cmdutil.SilentError
+		}
+	}
+
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
 
 	if opts.Interactive && opts.TeamId == "" {
-		opts.TeamId, _ = pre_defined_prompters.AskTeamId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter)
+		opts.TeamId, _ = pre_defined_prompters.AskTeamId(client, opts.IO, cs, opts.Prompter)
 
 		opts.Interacted = true
 	} else {
 		if opts.TeamId == "" {
 			fmt.Fprintf(opts.IO.ErrOut, "%s team-name is required.\n", cs.FailureIcon())
-			return
+			return cmdutil.SilentError
 		}
 	}
 
 	if opts.TeamId != "" && !opts.Interacted {
-		teamId := helpers.TeamNameToTeamId(&client, cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+		teamId := helpers.TeamNameToTeamId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 
 		if teamId == "" {
 			fmt.Fprintf(opts.IO.ErrOut, "%s no team with name: %s found.\n", cs.FailureIcon(), opts.TeamId)
-			return
+			return cmdutil.SilentError
 		}
 
 		opts.TeamId = teamId
 	}
 
-	var sources []models.Source
-
-	if opts.SourceFilter == nil {
-		sources, err = APICalls.GetAllSources(opts.HttpClient(), cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId)
+	sources, err := client.ListSources(opts.TeamId)
+	if err != nil {
+		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+		return cmdutil.SilentError
+	}
+	if opts.SourceFilter != nil {
+		sources, err = sourceref.Resolve(sources, opts.SourceFilter)
 		if err != nil {
 			fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
-			return
-		}
-	} else {
-		for _, sourceId := range opts.SourceFilter {
-			source, err := APICalls.GetSource(cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId, sourceId)
-			if err != nil {
-				fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
-				return
-			}
-			sources = append(sources, source)
+			return cmdutil.SilentError
 		}
 	}
 
 	if opts.SaveView {
-		err := APICalls.CreateView(cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId, sources, opts.SearchFilter,
-			opts.FieldBasedFilterName, opts.FieldBasedFilterValue, opts.FieldBasedFilterCondition,
-			opts.StartDateTimeFilter, opts.EndDateTimeFilter, opts.ViewName)
+		err := client.CreateView(opts.TeamId, sources, query, startDate, endDate, opts.ViewName)
 		if err != nil {
-			return
+			fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+			return cmdutil.SilentError
 		}
 	}
 
-	if opts.StartDateTimeFilter == "" {
+	if startDate.IsZero() {
 		request.DateTimeFilter.StartTimeStamp = timestamppb.New(time.Now().Add(-1 * time.Second))
+	} else {
+		request.DateTimeFilter.StartTimeStamp = timestamppb.New(startDate)
+	}
+
+	if !endDate.IsZero() {
+		request.DateTimeFilter.EndTimeStamp = timestamppb.New(endDate)
 	}
 
-	if opts.StartDateTimeFilter != "" {
-		request.DateTimeFilter.StartTimeStamp = timestamppb.New(filters.ShortDateTimeToGoDate(opts.StartDateTimeFilter))
+	query.Apply(request)
+
+	request.Sources = grpcutil.CreateGrpcSource(sources)
+	request.AccountID = cfg.Get().AccountId
+	request.TeamID = opts.TeamId
+
+	filterService := grpcutil.NewFilterService(cfg)
+	defer filterService.CloseConnection()
+
+	// SIGTERM, as sent by timeout(1), stops tail like an interrupt, after the batch being printed
+	// and the checkpoint of its records are written.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-		if opts.EndDateTimeFilter != "" {
-			request.DateTimeFilter.EndTimeStamp = timestamppb.New(filters.ShortDateTimeToGoDate(opts.EndDateTimeFilter))
+	if checkpoint != nil {
+		// Offsets decide where every source starts, so only an explicit start date limits the records.
+		if opts.StartDateTimeFilter == "" {
+			request.DateTimeFilter.StartTimeStamp = nil
+		}
+		request.IsScrollDown = true
+
+		missing := checkpoint.Resume(request.Sources)
+		err = filterService.SeedOffsets(ctx, opts.TeamId, missing, opts.CheckpointStart == "earliest")
+		if err == nil {
+			// Saving the seeded offsets right away keeps records arriving before the next run.
+			err = checkpoint.Save(request.Sources)
+		}
+		if err != nil {
+			fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+			return cmdutil.SilentError
 		}
 	}
 
-	if opts.SearchFilter != nil {
-		request.SearchQueries = append(request.SearchQueries, opts.SearchFilter...)
+	streamCtx := ctx
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		streamCtx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
 	}
 
-	if opts.FieldBasedFilterName != "" && opts.FieldBasedFilterValue != "" && opts.
-		FieldBasedFilterCondition != "" && helpers.StringNotInArray(strings.ToUpper(opts.FieldBasedFilterCondition), conditions) {
-		request.FieldBasedFilters = append(request.FieldBasedFilters, &pb.FieldBasedFilter{
-			FieldName:  opts.FieldBasedFilterName,
-			FieldValue: opts.FieldBasedFilterValue,
-			Operator:   pb.FieldBasedFilter_Operator(pb.FieldBasedFilter_Operator_value[strings.ToUpper(opts.FieldBasedFilterCondition)]),
-		})
+	var stats *logformat.Stats
+	var status *logformat.StatusLine
+	stopStatus := func() {}
+	if opts.Stats {
+		stats = logformat.NewStats(request.Sources, time.Now())
+		filterService.OnReconnect = func(error) { stats.Reconnect() }
+
+		// The status line is only refreshed in place on a terminal; elsewhere only the summary prints.
+		if opts.IO.IsStderrTTY() {
+			status = logformat.NewStatusLine(opts.IO.ErrOut, opts.IO.TerminalWidth())
+			stopStatus = status.Refresh(time.Second, stats.Line)
+		}
 	}
 
-	pbSources := grpcutil.CreateGrpcSource(sources)
-	var sourcesOffset = make(map[string]uint64)
+	batches := &tailBatches{
+		request:    request,
+		watch:      watch,
+		grep:       grep,
+		checkpoint: checkpoint,
+		stats:      stats,
+		status:     status,
+		print: func(records []*pb.FilteredRecord) error {
+			return showLogs(opts.IO, opts.Exporter, printer, records)
+		},
+	}
+	err = filterService.StreamRecords(streamCtx, request, batches.handle)
+	stopStatus()
+	if stats != nil {
+		fmt.Fprint(opts.IO.ErrOut, stats.Summary(time.Now()))
+	}
+	if err != nil && !errors.Is(err, errStopped) {
+		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+		return cmdutil.SilentError
+	}
 
-	request.Sources = pbSources
-	request.AccountID = cfg.Get().AccountId
-	request.TeamID = opts.TeamId
+	timedOut := errors.Is(streamCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil
+	return tailExit(opts, watch, batches.reason, timedOut)
+}
 
-	filterService := grpcutil.NewFilterService()
-	defer filterService.CloseConnection()
+// tailBatches handles the batches streamed to tail: it cuts them at --until and --fail-on,
+// filters them with --grep and --context, limits them to --max-records, and prints them.
+// Records received after the last one handled are given back to the offsets of request, so
+// that the checkpoint saved after every batch resumes from them.
+type tailBatches struct {
+	request    *pb.FilterRequest
+	watch      *grpcutil.Watch
+	grep       *logformat.Grep
+	checkpoint *grpcutil.Checkpoint
+	stats      *logformat.Stats
+	status     *logformat.StatusLine
+	print      func(records []*pb.FilteredRecord) error
+
+	// reason is why the stream ends, once a batch met --until, --fail-on or --max-records.
+	reason grpcutil.StopReason
+}
 
-	for {
-		response, err := filterService.Client.GetFilteredData(context.Background(), request)
-		if err != nil {
-			//log.Fatal(err)
-			continue
+// handle is the grpcutil.RecordHandler of tail, which returns errStopped once the stream ends.
+func (t *tailBatches) handle(records []*pb.FilteredRecord) error {
+	var cut, printed []*pb.FilteredRecord
+	cut, t.reason = t.watch.Cut(records)
+	printed, limited := t.watch.Count(t.grep.Filter(cut))
+
+	// handled is the number of records of the batch up to the last one printed or matched.
+	handled := len(cut)
+	if limited == grpcutil.StopMaxRecords && printed[len(printed)-1] != cut[len(cut)-1] {
+		t.reason = limited
+		for i, record := range records {
+			if record == printed[len(printed)-1] {
+				handled = i + 1
+			}
 		}
+	} else if t.reason == grpcutil.StopNone {
+		t.reason = limited
+	}
 
-		if len(response.Records) > 0 {
-			sort.Sort(grpcutil.ByOffset(response.Records))
-			sourcesOffset = grpcutil.GetOffsets(sourcesOffset, response.Records)
-			pbSources = grpcutil.AddOffset(pbSources, sourcesOffset)
-			showLogs(opts.IO, response.Records)
+	show := func() error {
+		return t.print(printed)
+	}
+	var err error
+	if t.status != nil {
+		err = t.status.Print(show)
+	} else {
+		err = show()
+	}
+	if err != nil {
+		return err
+	}
+	if t.stats != nil {
+		now := time.Now()
+		t.stats.Add(records[:handled], countRecords(printed), now)
+		if t.status != nil {
+			t.status.Set(t.stats.Line(now))
 		}
-		time.Sleep(500 * time.Millisecond)
 	}
+	if t.reason != grpcutil.StopNone {
+		grpcutil.RewindOffsets(t.request.Sources, records[handled:])
+	}
+	if t.checkpoint != nil {
+		if err := t.checkpoint.Save(t.request.Sources); err != nil {
+			return err
+		}
+	}
+	if t.reason != grpcutil.StopNone {
+		return errStopped
+	}
+	return nil
 }
 
-// Convert logs with colors
-func showLogs(io *iostreams.IOStreams, records []*pb.FilteredRecord) {
-	cs := io.ColorScheme()
+// countRecords counts records without the nil records separating --context.
+func countRecords(records []*pb.FilteredRecord) int {
+	count := 0
 	for _, record := range records {
+		if record != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// tailExit reports why the stream ended and returns the error giving the exit code of tail.
+func tailExit(opts *TailOptions, watch *grpcutil.Watch, reason grpcutil.StopReason, timedOut bool) error {
+	cs := opts.IO.ColorScheme()
+	waiting := len(opts.Until) > 0
+
+	switch {
+	case reason == grpcutil.StopFailOn:
+		fmt.Fprintf(opts.IO.ErrOut, "%s --fail-on matched: %s\n", cs.FailureIcon(), watch.Matched.Message)
+		return &cmdutil.ExitError{Code: exitFailOn}
+	case reason == grpcutil.StopMaxRecords && waiting:
+		fmt.Fprintf(opts.IO.ErrOut, "%s --until did not match in %d logs\n", cs.FailureIcon(), opts.MaxRecords)
+		return &cmdutil.ExitError{Code: exitMaxRecords}
+	case timedOut && waiting:
+		fmt.Fprintf(opts.IO.ErrOut, "%s --until did not match within %s\n", cs.FailureIcon(), opts.Timeout)
+		return &cmdutil.ExitError{Code: exitTimeout}
+	}
+	return nil
+}
+
+// tailGrep compiles the --grep, --exclude and --highlight patterns, and highlights the matches of
+// --grep and --highlight with printer.
+func tailGrep(opts *TailOptions, printer *logformat.Printer) (*logformat.Grep, error) {
+	if opts.Context < 0 {
+		return nil, fmt.Errorf("--context must not be negative")
+	}
 
-		fmt.Fprintf(io.Out, "%s %s [%s] %s\n",
-			cs.Yellow(record.Dt), cs.Green(record.SourceName), cs.Cyan(strings.ToUpper(record.Level)), record.Message)
+	include, err := logformat.CompileRegexps("--grep", opts.Grep)
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := logformat.CompileRegexps("--exclude", opts.Exclude)
+	if err != nil {
+		return nil, err
 	}
+	highlight, err := logformat.CompileRegexps("--highlight", opts.Highlight)
+	if err != nil {
+		return nil, err
+	}
+
+	printer.Highlight(include)
+	printer.Highlight(highlight)
+	return logformat.NewGrep(include, exclude, opts.Context), nil
+}
+
+// Print logs with the --format or --fields printer, or write one document per record when structured output is requested
+func showLogs(io *iostreams.IOStreams, exporter *cmdutil.Exporter, printer *logformat.Printer, records []*pb.FilteredRecord) error {
+	if exporter.Enabled() {
+		for _, record := range records {
+			// A nil record separates the --context of two matches, which only text output shows.
+			if record == nil {
+				continue
+			}
+			if err := exporter.WriteRecord(io.Out, record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return printer.Print(io.Out, records)
 }
@@ -0,0 +1,166 @@
+package tail
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/logfire-sh/cli/pkg/cmdutil"
+	"github.com/logfire-sh/cli/pkg/cmdutil/grpcutil"
+	"github.com/logfire-sh/cli/pkg/cmdutil/logformat"
+	"github.com/logfire-sh/cli/pkg/iostreams"
+	pb "github.com/logfire-sh/cli/services/flink-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tailRecords returns a batch of the source api from offset 10, and the request that delivered
+// it, whose offset is already past the batch.
+func tailRecords(messages ...string) ([]*pb.FilteredRecord, *pb.FilterRequest) {
+	var records []*pb.FilteredRecord
+	for i, message := range messages {
+		records = append(records, &pb.FilteredRecord{SourceID: "api", Offset: uint64(10 + i), Message: message})
+	}
+	request := &pb.FilterRequest{Sources: []*pb.Source{{SourceID: "api", StartingOffset: uint64(10 + len(messages))}}}
+	return records, request
+}
+
+func TestTailBatches(t *testing.T) {
+	tests := []struct {
+		name       string
+		messages   []string
+		grep       []string
+		maxRecords int
+		printed    []uint64
+		reason     grpcutil.StopReason
+		offset     uint64
+	}{
+		{
+			name:     "no stop",
+			messages: []string{"starting", "loading"},
+			printed:  []uint64{10, 11},
+			reason:   grpcutil.StopNone,
+			offset:   12,
+		},
+		{
+			name:       "max records in the middle of a batch",
+			messages:   []string{"ERROR a", "info", "ERROR b", "info", "ERROR c"},
+			grep:       []string{`^ERROR`},
+			maxRecords: 2,
+			printed:    []uint64{10, 12},
+			reason:     grpcutil.StopMaxRecords,
+			offset:     13,
+		},
+		{
+			name:       "max records at the end of a batch",
+			messages:   []string{"starting", "loading"},
+			maxRecords: 2,
+			printed:    []uint64{10, 11},
+			reason:     grpcutil.StopMaxRecords,
+			offset:     12,
+		},
+		{
+			name:     "until before fail on",
+			messages: []string{"starting", "ready on :8080", "panic: nil map", "serving"},
+			printed:  []uint64{10, 11},
+			reason:   grpcutil.StopUntil,
+			offset:   12,
+		},
+		{
+			name:     "fail on before until",
+			messages: []string{"starting", "panic: nil map", "ready on :8080"},
+			printed:  []uint64{10, 11},
+			reason:   grpcutil.StopFailOn,
+			offset:   12,
+		},
+		{
+			name:       "until on the last record counted",
+			messages:   []string{"starting", "ready on :8080", "serving"},
+			maxRecords: 2,
+			printed:    []uint64{10, 11},
+			reason:     grpcutil.StopUntil,
+			offset:     12,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, request := tailRecords(tt.messages...)
+			include, err := logformat.CompileRegexps("--grep", tt.grep)
+			require.NoError(t, err)
+			path := filepath.Join(t.TempDir(), "tail.checkpoint")
+			checkpoint, err := grpcutil.LoadCheckpoint(path)
+			require.NoError(t, err)
+
+			var printed []uint64
+			batches := &tailBatches{
+				request: request,
+				watch: &grpcutil.Watch{
+					Until:      []*regexp.Regexp{regexp.MustCompile(`ready`)},
+					FailOn:     []*regexp.Regexp{regexp.MustCompile(`^panic`)},
+					MaxRecords: tt.maxRecords,
+				},
+				grep:       logformat.NewGrep(include, nil, 0),
+				checkpoint: checkpoint,
+				print: func(records []*pb.FilteredRecord) error {
+					for _, record := range records {
+						printed = append(printed, record.Offset)
+					}
+					return nil
+				},
+			}
+
+			err = batches.handle(records)
+			if tt.reason == grpcutil.StopNone {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, errStopped)
+			}
+			assert.Equal(t, tt.printed, printed)
+			assert.Equal(t, tt.reason, batches.reason)
+			assert.Equal(t, tt.offset, request.Sources[0].StartingOffset)
+
+			saved, err := grpcutil.LoadCheckpoint(path)
+			require.NoError(t, err)
+			assert.Equal(t, map[string]uint64{"api": tt.offset}, saved.Offsets)
+		})
+	}
+}
+
+func TestTailExit(t *testing.T) {
+	tests := []struct {
+		name     string
+		until    bool
+		reason   grpcutil.StopReason
+		timedOut bool
+		code     int
+		stderr   string
+	}{
+		{name: "until matched", until: true, reason: grpcutil.StopUntil},
+		{name: "fail on matched", until: true, reason: grpcutil.StopFailOn, code: exitFailOn, stderr: "X --fail-on matched: panic: nil map\n"},
+		{name: "timeout waiting on until", until: true, timedOut: true, code: exitTimeout, stderr: "X --until did not match within 5m0s\n"},
+		{name: "max records waiting on until", until: true, reason: grpcutil.StopMaxRecords, code: exitMaxRecords, stderr: "X --until did not match in 100 logs\n"},
+		{name: "timeout without until", timedOut: true},
+		{name: "max records without until", reason: grpcutil.StopMaxRecords},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			io, _, _, stderr := iostreams.Test()
+			opts := &TailOptions{IO: io, MaxRecords: 100, Timeout: 5 * time.Minute}
+			if tt.until {
+				opts.Until = []string{"ready"}
+			}
+			watch := &grpcutil.Watch{Matched: &pb.FilteredRecord{Message: "panic: nil map"}}
+
+			err := tailExit(opts, watch, tt.reason, tt.timedOut)
+			if tt.code == 0 {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, &cmdutil.ExitError{Code: tt.code}, err)
+			}
+			assert.Equal(t, tt.stderr, stderr.String())
+		})
+	}
+}
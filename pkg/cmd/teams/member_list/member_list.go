@@ -6,10 +6,10 @@ import (
 	"os"
 
 	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	"github.com/logfire-sh/cli/pkg/cmdutil/helpers"
 	"github.com/logfire-sh/cli/pkg/cmdutil/pre_defined_prompters"
 	"github.com/logfire-sh/cli/pkg/iostreams"
@@ -23,6 +23,7 @@ type MemberListOptions struct {
 
 	HttpClient func() *http.Client
 	Config     func() (config.Config, error)
+	Exporter   *cmdutil.Exporter
 
 	Interactive bool
 	TeamId      string
@@ -34,6 +35,7 @@ func NewMemberListCmd(f *cmdutil.Factory) *cobra.Command {
 		Prompter:   f.Prompter,
 		HttpClient: f.HttpClient,
 		Config:     f.Config,
+		Exporter:   f.Exporter,
 	}
 
 	cmd := &cobra.Command{
@@ -64,6 +66,9 @@ func NewMemberListCmd(f *cmdutil.Factory) *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&opts.TeamId, "team-name", "t", "", "Team name for which members are to be fetched.")
+
+	cmdutil.EnableExport(cmd)
+
 	return cmd
 }
 
@@ -74,10 +79,10 @@ func listMembersRun(opts *MemberListOptions) {
 		fmt.Fprintf(opts.IO.ErrOut, "%s Failed to read config\n", cs.FailureIcon())
 	}
 
-	client := http.Client{}
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
 
 	if opts.TeamId != "" {
-		teamId := helpers.TeamNameToTeamId(&client, cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+		teamId := helpers.TeamNameToTeamId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 
 		if teamId == "" {
 			fmt.Fprintf(opts.IO.ErrOut, "%s no team with name: %s found.\n", cs.FailureIcon(), opts.TeamId)
@@ -88,16 +93,20 @@ func listMembersRun(opts *MemberListOptions) {
 	}
 
 	if opts.Interactive && opts.TeamId == "" {
-		opts.TeamId, _ = pre_defined_prompters.AskTeamId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter)
+		opts.TeamId, _ = pre_defined_prompters.AskTeamId(client, opts.IO, cs, opts.Prompter)
 	} else {
 		if opts.TeamId == "" {
 			opts.TeamId = cfg.Get().TeamId
 		}
 	}
 
-	members, err := APICalls.MembersList(opts.HttpClient(), cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId)
+	members, err := client.ListMembers(opts.TeamId)
 	if err != nil {
 		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+	} else if opts.Exporter.Enabled() {
+		if err := opts.Exporter.Write(opts.IO.Out, members); err != nil {
+			fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+		}
 	} else {
 		table := tablewriter.NewWriter(os.Stdout)
 		table.SetHeader([]string{"First name", "Last name", "Profile-Id", "Role"})
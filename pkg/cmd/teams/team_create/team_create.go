@@ -4,9 +4,8 @@ import (
 	"fmt"
 	"net/http"
 
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
-
 	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
@@ -66,6 +65,8 @@ func TeamsCreateRun(opts *TeamCreateOptions) {
 		fmt.Fprintf(opts.IO.ErrOut, "%s Failed to read config\n", cs.FailureIcon())
 	}
 
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
+
 	if opts.Interactive && opts.TeamName == "" {
 		opts.TeamName, err = opts.Prompter.Input("Enter a name for the team:", "")
 		if err != nil {
@@ -78,7 +79,7 @@ func TeamsCreateRun(opts *TeamCreateOptions) {
 		}
 	}
 
-	team, err := APICalls.CreateTeam(cfg.Get().Token, cfg.Get().EndPoint, opts.TeamName)
+	team, err := client.CreateTeam(opts.TeamName)
 	if err != nil {
 		fmt.Fprintf(opts.IO.ErrOut, "%s Failed to create team.\n", cs.FailureIcon())
 	}
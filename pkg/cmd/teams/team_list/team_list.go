@@ -5,10 +5,10 @@ import (
 	"net/http"
 	"os"
 
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	"github.com/olekukonko/tablewriter"
 
 	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
@@ -22,6 +22,7 @@ type TeamOptions struct {
 
 	HttpClient func() *http.Client
 	Config     func() (config.Config, error)
+	Exporter   *cmdutil.Exporter
 
 	Interactive bool
 }
@@ -32,6 +33,7 @@ func NewListCmd(f *cmdutil.Factory) *cobra.Command {
 		Prompter:   f.Prompter,
 		HttpClient: f.HttpClient,
 		Config:     f.Config,
+		Exporter:   f.Exporter,
 	}
 
 	cmd := &cobra.Command{
@@ -53,6 +55,8 @@ func NewListCmd(f *cmdutil.Factory) *cobra.Command {
 		},
 	}
 
+	cmdutil.EnableExport(cmd)
+
 	return cmd
 }
 
@@ -63,9 +67,15 @@ func listRun(opts *TeamOptions) {
 		fmt.Fprintf(opts.IO.ErrOut, "%s Failed to read config\n", cs.FailureIcon())
 	}
 
-	teams, err := APICalls.ListTeams(opts.HttpClient(), cfg.Get().Token, cfg.Get().EndPoint)
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
+
+	teams, err := client.ListTeams()
 	if err != nil {
 		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+	} else if opts.Exporter.Enabled() {
+		if err := opts.Exporter.Write(opts.IO.Out, teams); err != nil {
+			fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+		}
 	} else if len(teams) > 0 {
 		table := tablewriter.NewWriter(os.Stdout)
 		table.SetHeader([]string{"Name", "Team-Id"})
@@ -5,10 +5,10 @@ import (
 	"net/http"
 
 	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	"github.com/logfire-sh/cli/pkg/cmdutil/helpers"
 	"github.com/logfire-sh/cli/pkg/cmdutil/pre_defined_prompters"
 	"github.com/logfire-sh/cli/pkg/iostreams"
@@ -82,10 +82,10 @@ func teamUpdateRun(opts *TeamUpdateOptions) {
 		fmt.Fprintf(opts.IO.ErrOut, "%s Failed to read config\n", cs.FailureIcon())
 	}
 
-	client := http.Client{}
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
 
 	if opts.TeamId != "" {
-		teamId := helpers.TeamNameToTeamId(&client, cfg, opts.IO, cs, opts.Prompter, opts.TeamId)
+		teamId := helpers.TeamNameToTeamId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
 
 		if teamId == "" {
 			fmt.Fprintf(opts.IO.ErrOut, "%s no team with name: %s found.\n", cs.FailureIcon(), opts.TeamId)
@@ -96,7 +96,7 @@ func teamUpdateRun(opts *TeamUpdateOptions) {
 	}
 
 	if opts.Interactive && opts.TeamId == "" && opts.TeamName == "" {
-		opts.TeamId, _ = pre_defined_prompters.AskTeamId(opts.HttpClient(), cfg, opts.IO, cs, opts.Prompter)
+		opts.TeamId, _ = pre_defined_prompters.AskTeamId(client, opts.IO, cs, opts.Prompter)
 
 		opts.TeamName, err = opts.Prompter.Input("Enter a new name for the team:", "")
 		if err != nil {
@@ -113,7 +113,7 @@ func teamUpdateRun(opts *TeamUpdateOptions) {
 		}
 	}
 
-	resp, err := APICalls.UpdateTeam(opts.HttpClient(), cfg.Get().Token, cfg.Get().EndPoint, opts.TeamId, opts.TeamName)
+	resp, err := client.UpdateTeam(opts.TeamId, opts.TeamName)
 	if err != nil {
 		fmt.Fprintf(opts.IO.ErrOut, "%s Failed to update team\n", cs.FailureIcon())
 	} else {
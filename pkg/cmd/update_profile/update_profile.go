@@ -6,10 +6,10 @@ import (
 	"os"
 
 	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	"github.com/logfire-sh/cli/pkg/iostreams"
 	"github.com/spf13/cobra"
 )
@@ -71,6 +71,8 @@ func UpdateProfileRun(opts *UpdateProfileOptions) {
 		fmt.Fprintf(opts.IO.ErrOut, "%s Failed to read config\n", cs.FailureIcon())
 	}
 
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
+
 	if opts.Interactive && opts.FirstName == "" && opts.LastName == "" {
 		updateFirstName, err := opts.Prompter.Confirm("Do you want to update your First name?", false)
 		if err != nil {
@@ -121,7 +123,7 @@ func UpdateProfileRun(opts *UpdateProfileOptions) {
 		}
 	}
 
-	err = APICalls.UpdateProfile(opts.HttpClient(), cfg.Get().Token, cfg.Get().EndPoint, cfg.Get().ProfileID, opts.FirstName, opts.LastName, opts.Role)
+	err = client.UpdateProfile(cfg.Get().ProfileID, opts.FirstName, opts.LastName, opts.Role)
 	if err != nil {
 		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
 	} else {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/internal/prompter"
+	"github.com/logfire-sh/cli/pkg/cmd/views/views_create"
 	"github.com/logfire-sh/cli/pkg/cmd/views/views_delete"
 	"github.com/logfire-sh/cli/pkg/cmd/views/views_list"
 	"github.com/logfire-sh/cli/pkg/cmdutil"
@@ -26,7 +27,7 @@ type PromptViewsOptions struct {
 	Choice      string
 }
 
-var choices = []string{"List", "Delete", "Exit"}
+var choices = []string{"List", "Create", "Delete", "Exit"}
 
 func NewCmdViews(f *cmdutil.Factory) *cobra.Command {
 	opts := &PromptViewsOptions{
@@ -52,6 +53,8 @@ func NewCmdViews(f *cmdutil.Factory) *cobra.Command {
 			case choices[0]:
 				views_list.NewViewListCmd(f).Run(cmd, []string{})
 			case choices[1]:
+				views_create.NewCreateCmd(f).Run(cmd, []string{})
+			case choices[2]:
 				views_delete.NewDeleteCmd(f).Run(cmd, []string{})
 			case "Exit":
 				os.Exit(0)
@@ -59,6 +62,7 @@ func NewCmdViews(f *cmdutil.Factory) *cobra.Command {
 		},
 	}
 
+	cmd.AddCommand(views_create.NewCreateCmd(f))
 	cmd.AddCommand(views_delete.NewDeleteCmd(f))
 	cmd.AddCommand(views_list.NewViewListCmd(f))
 	return cmd
@@ -0,0 +1,152 @@
+package views_create
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/api"
+	"github.com/logfire-sh/cli/internal/config"
+	"github.com/logfire-sh/cli/internal/prompter"
+	"github.com/logfire-sh/cli/pkg/cmd/sources/models"
+	"github.com/logfire-sh/cli/pkg/cmdutil"
+	"github.com/logfire-sh/cli/pkg/cmdutil/filters"
+	"github.com/logfire-sh/cli/pkg/cmdutil/helpers"
+	"github.com/logfire-sh/cli/pkg/cmdutil/pre_defined_prompters"
+	"github.com/logfire-sh/cli/pkg/iostreams"
+	"github.com/spf13/cobra"
+)
+
+type ViewsCreateOptions struct {
+	IO       *iostreams.IOStreams
+	Prompter prompter.Prompter
+
+	HttpClient func() *http.Client
+	Config     func() (config.Config, error)
+
+	Interactive bool
+	TeamId      string
+	Name        string
+	SourceIds   []string
+	Filter      string
+	StartDate   string
+	EndDate     string
+}
+
+func NewCreateCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &ViewsCreateOptions{
+		IO:       f.IOStreams,
+		Prompter: f.Prompter,
+
+		HttpClient: f.HttpClient,
+		Config:     f.Config,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a view",
+		Long: heredoc.Doc(`
+			Create a view from sources, a date range and a filter expression.
+
+			The filter uses the same expressions as tail --filter, such as
+			'level=error status>=500 msg:"timeout" -path:/health'.
+		`),
+		Args: cobra.ExactArgs(0),
+		Example: heredoc.Doc(`
+			# start interactive setup
+			$ logfire views create
+
+			# start argument setup
+			$ logfire views create --team-name <team-name> --name <name> --source-id <source-id>
+			  --filter 'level=error -path:/health' --start-date now-2d
+		`),
+		Run: func(cmd *cobra.Command, args []string) {
+			if opts.IO.CanPrompt() {
+				opts.Interactive = true
+			}
+
+			viewCreateRun(opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.TeamId, "team-name", "", "Team name in which the view will be created.")
+	cmd.Flags().StringVar(&opts.Name, "name", "", "Name of the view.")
+	cmd.Flags().StringSliceVarP(&opts.SourceIds, "source-id", "s", nil, "Sources of the view. (Default: all sources of the team)")
+	cmd.Flags().StringVar(&opts.Filter, "filter", "", "Filter expression of the view.")
+	cmd.Flags().StringVar(&opts.StartDate, "start-date", "", "Start date of the view, such as now-2d, yesterday or 2024-05-01T22:00:00Z.")
+	cmd.Flags().StringVar(&opts.EndDate, "end-date", "", "End date of the view.")
+	return cmd
+}
+
+func viewCreateRun(opts *ViewsCreateOptions) {
+	cs := opts.IO.ColorScheme()
+	cfg, err := opts.Config()
+	if err != nil {
+		fmt.Fprintf(opts.IO.ErrOut, "%s Failed to read config\n", cs.FailureIcon())
+		return
+	}
+
+	query, err := filters.ParseQuery(opts.Filter)
+	if err != nil {
+		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+		return
+	}
+
+	startDate, endDate, err := filters.ParseTimeRange(opts.StartDate, opts.EndDate, time.Now())
+	if err != nil {
+		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+		return
+	}
+
+	client := api.NewClientFromConfig(opts.HttpClient(), cfg)
+
+	if opts.TeamId != "" {
+		teamId := helpers.TeamNameToTeamId(client, opts.IO, cs, opts.Prompter, opts.TeamId)
+
+		if teamId == "" {
+			fmt.Fprintf(opts.IO.ErrOut, "%s no team with name: %s found.\n", cs.FailureIcon(), opts.TeamId)
+			return
+		}
+
+		opts.TeamId = teamId
+	} else if opts.Interactive {
+		opts.TeamId, _ = pre_defined_prompters.AskTeamId(client, opts.IO, cs, opts.Prompter)
+	} else {
+		opts.TeamId = cfg.Get().TeamId
+	}
+
+	if opts.Name == "" && opts.Interactive {
+		opts.Name, _ = opts.Prompter.Input("Enter a name for the view:", "")
+	}
+	if opts.Name == "" {
+		fmt.Fprintf(opts.IO.ErrOut, "%s name is required.\n", cs.FailureIcon())
+		return
+	}
+
+	var sources []models.Source
+
+	if opts.SourceIds == nil {
+		sources, err = client.ListSources(opts.TeamId)
+		if err != nil {
+			fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+			return
+		}
+	} else {
+		for _, sourceId := range opts.SourceIds {
+			source, err := client.GetSource(opts.TeamId, sourceId)
+			if err != nil {
+				fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+				return
+			}
+			sources = append(sources, source)
+		}
+	}
+
+	err = client.CreateView(opts.TeamId, sources, query, startDate, endDate, opts.Name)
+	if err != nil {
+		fmt.Fprintf(opts.IO.ErrOut, "%s %s\n", cs.FailureIcon(), err.Error())
+		return
+	}
+
+	fmt.Fprintf(opts.IO.Out, "%s View %s created successfully\n", cs.SuccessIcon(), opts.Name)
+}
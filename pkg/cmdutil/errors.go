@@ -1,9 +1,15 @@
 package cmdutil
 
+import "fmt"
+
 type FlagError struct {
 	err error
 }
 
+func FlagErrorf(format string, args ...interface{}) error {
+	return FlagErrorWrap(fmt.Errorf(format, args...))
+}
+
 func FlagErrorWrap(err error) error { return &FlagError{err} }
 
 func (fe *FlagError) Error() string {
@@ -13,3 +19,23 @@ func (fe *FlagError) Error() string {
 func (fe *FlagError) Unwrap() error {
 	return fe.err
 }
+
+// ExitError ends the program with Code, printing Err first unless it is nil.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// SilentError exits with 1 for commands that already printed why they failed.
+var SilentError = &ExitError{Code: 1}
+
+func (ee *ExitError) Error() string {
+	if ee.Err == nil {
+		return fmt.Sprintf("exit status %d", ee.Code)
+	}
+	return ee.Err.Error()
+}
+
+func (ee *ExitError) Unwrap() error {
+	return ee.Err
+}
@@ -0,0 +1,415 @@
+package cmdutil
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/itchyny/gojq"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	OutputTable  = "table"
+	OutputJSON   = "json"
+	OutputNDJSON = "ndjson"
+	OutputCSV    = "csv"
+	OutputYAML   = "yaml"
+)
+
+var OutputFormats = []string{OutputTable, OutputJSON, OutputNDJSON, OutputCSV, OutputYAML}
+
+// EnableExport marks a command that writes its results through the Exporter, so it accepts
+// the global --output, --jq and --template flags.
+func EnableExport(cmd *cobra.Command) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations["export"] = "true"
+}
+
+// IsExportEnabled reports whether a command was marked with EnableExport.
+func IsExportEnabled(cmd *cobra.Command) bool {
+	return cmd.Annotations != nil && cmd.Annotations["export"] == "true"
+}
+
+// Exporter writes command results in the machine readable format selected with the global
+// --output, --jq and --template flags. The zero value prints nothing and leaves the command's
+// own table output in charge.
+type Exporter struct {
+	Format   string
+	JQ       string
+	Template string
+
+	// columns are the csv columns of the records written by WriteRecord, set by the first one.
+	columns []string
+}
+
+// Enabled reports whether structured output was requested instead of the default table.
+func (e *Exporter) Enabled() bool {
+	if e == nil {
+		return false
+	}
+	return (e.Format != "" && e.Format != OutputTable) || e.JQ != "" || e.Template != ""
+}
+
+// Validate checks the flag values before any request is made.
+func (e *Exporter) Validate() error {
+	if e == nil {
+		return nil
+	}
+
+	if e.Format != "" {
+		valid := false
+		for _, format := range OutputFormats {
+			if e.Format == format {
+				valid = true
+			}
+		}
+		if !valid {
+			return FlagErrorWrap(fmt.Errorf("invalid output format %q, expected one of: %s", e.Format, strings.Join(OutputFormats, ", ")))
+		}
+	}
+
+	if e.JQ != "" && e.Template != "" {
+		return FlagErrorWrap(fmt.Errorf("only one of --jq or --template can be used"))
+	}
+
+	if e.JQ != "" {
+		if _, err := gojq.Parse(e.JQ); err != nil {
+			return FlagErrorWrap(fmt.Errorf("invalid jq expression: %w", err))
+		}
+	}
+
+	if e.Template != "" {
+		if _, err := parseTemplate(e.Template); err != nil {
+			return FlagErrorWrap(fmt.Errorf("invalid template: %w", err))
+		}
+	}
+
+	return nil
+}
+
+// Write encodes data, a struct or a slice of structs, in the selected format.
+// Field names follow the json tags of the data, so they match what the API returns.
+func (e *Exporter) Write(w io.Writer, data interface{}) error {
+	value, err := toJSONValue(data)
+	if err != nil {
+		return err
+	}
+
+	if e.Template != "" {
+		return executeTemplate(w, e.Template, value)
+	}
+
+	values := []interface{}{value}
+	if e.JQ != "" {
+		values, err = evalJQ(e.JQ, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	format := e.Format
+	if format == "" || format == OutputTable {
+		format = OutputJSON
+	}
+
+	for _, v := range values {
+		if err := encode(w, format, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteRecord writes a single item of an unbounded stream, like the records printed by tail.
+// Every format other than csv and yaml produces one JSON document per line. The csv columns
+// are fixed by the first record, all the json fields of a struct, and printed as a header once;
+// later records leave the cells of their missing fields empty.
+func (e *Exporter) WriteRecord(w io.Writer, record interface{}) error {
+	if e.Template != "" || e.Format == OutputYAML {
+		return e.Write(w, record)
+	}
+
+	value, err := toJSONValue(record)
+	if err != nil {
+		return err
+	}
+
+	values := []interface{}{value}
+	if e.JQ != "" {
+		values, err = evalJQ(e.JQ, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, v := range values {
+		if e.Format == OutputCSV {
+			err = e.writeCSVRow(w, record, v)
+		} else {
+			err = encode(w, OutputNDJSON, v)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encode(w io.Writer, format string, value interface{}) error {
+	switch format {
+	case OutputNDJSON:
+		if items, ok := value.([]interface{}); ok {
+			for _, item := range items {
+				if err := writeJSONLine(w, item); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return writeJSONLine(w, value)
+	case OutputCSV:
+		return writeCSV(w, value)
+	case OutputYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(value); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(value)
+	}
+}
+
+func writeJSONLine(w io.Writer, value interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(value)
+}
+
+// writeCSV writes a header and one row per object. Nested values are written as JSON.
+func writeCSV(w io.Writer, value interface{}) error {
+	items, ok := value.([]interface{})
+	if !ok {
+		items = []interface{}{value}
+	}
+
+	columns := csvColumns(items)
+	cw := csv.NewWriter(w)
+	if len(columns) > 0 {
+		if err := cw.Write(columns); err != nil {
+			return err
+		}
+	}
+
+	for _, item := range items {
+		if err := cw.Write(csvRow(item, columns)); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeCSVRow writes value, the JSON value of record or a result of --jq on it, as a single row,
+// preceded by the header for the first row of the stream.
+func (e *Exporter) writeCSVRow(w io.Writer, record, value interface{}) error {
+	cw := csv.NewWriter(w)
+	if e.columns == nil {
+		if e.JQ == "" {
+			e.columns = fieldColumns(record)
+		}
+		if len(e.columns) == 0 {
+			e.columns = csvColumns([]interface{}{value})
+		}
+		if len(e.columns) > 0 {
+			if err := cw.Write(e.columns); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := cw.Write(csvRow(value, e.columns)); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// fieldColumns returns the sorted json names of the fields of a struct, including those left
+// out by omitempty, or nil for any other value.
+func fieldColumns(data interface{}) []string {
+	t := reflect.TypeOf(data)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var columns []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		columns = append(columns, name)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+func csvColumns(items []interface{}) []string {
+	seen := map[string]bool{}
+	var columns []string
+	for _, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key := range obj {
+			if !seen[key] {
+				seen[key] = true
+				columns = append(columns, key)
+			}
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+func csvRow(item interface{}, columns []string) []string {
+	obj, ok := item.(map[string]interface{})
+	if !ok {
+		return []string{scalarString(item)}
+	}
+
+	row := make([]string, len(columns))
+	for i, column := range columns {
+		row[i] = scalarString(obj[column])
+	}
+	return row
+}
+
+func scalarString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64, bool:
+		return fmt.Sprint(v)
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
+
+func executeTemplate(w io.Writer, text string, value interface{}) error {
+	t, err := parseTemplate(text)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, value)
+}
+
+func parseTemplate(text string) (*template.Template, error) {
+	return template.New("output").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+		"join": func(sep string, v []interface{}) string {
+			parts := make([]string, len(v))
+			for i := range v {
+				parts[i] = scalarString(v[i])
+			}
+			return strings.Join(parts, sep)
+		},
+	}).Parse(text)
+}
+
+func evalJQ(expr string, value interface{}) ([]interface{}, error) {
+	query, err := gojq.Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []interface{}
+	iter := query.Run(value)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := v.(error); ok {
+			return nil, err
+		}
+		results = append(results, v)
+	}
+	return results, nil
+}
+
+// toJSONValue round-trips data through encoding/json so every format sees the same field names.
+func toJSONValue(data interface{}) (interface{}, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	// An empty list should still print as a list rather than null.
+	if value == nil && reflect.ValueOf(data).Kind() == reflect.Slice {
+		return []interface{}{}, nil
+	}
+	return normalizeNumbers(value), nil
+}
+
+// normalizeNumbers turns json.Number into int or float64 values that gojq and yaml understand.
+func normalizeNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return int(i)
+		}
+		f, _ := v.Float64()
+		return f
+	case []interface{}:
+		for i := range v {
+			v[i] = normalizeNumbers(v[i])
+		}
+	case map[string]interface{}:
+		for key := range v {
+			v[key] = normalizeNumbers(v[key])
+		}
+	}
+	return value
+}
@@ -0,0 +1,123 @@
+package cmdutil
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+type exportItem struct {
+	Name string `json:"name"`
+	ID   string `json:"id"`
+	Size int    `json:"size"`
+}
+
+func TestExporterWrite(t *testing.T) {
+	items := []exportItem{{Name: "api", ID: "1", Size: 10}, {Name: "web", ID: "2", Size: 20}}
+
+	tests := []struct {
+		name     string
+		exporter Exporter
+		data     interface{}
+		wants    string
+	}{
+		{
+			name:     "json",
+			exporter: Exporter{Format: OutputJSON},
+			data:     items[:1],
+			wants:    "[\n  {\n    \"id\": \"1\",\n    \"name\": \"api\",\n    \"size\": 10\n  }\n]\n",
+		},
+		{
+			name:     "ndjson",
+			exporter: Exporter{Format: OutputNDJSON},
+			data:     items,
+			wants:    "{\"id\":\"1\",\"name\":\"api\",\"size\":10}\n{\"id\":\"2\",\"name\":\"web\",\"size\":20}\n",
+		},
+		{
+			name:     "csv",
+			exporter: Exporter{Format: OutputCSV},
+			data:     items,
+			wants:    "id,name,size\n1,api,10\n2,web,20\n",
+		},
+		{
+			name:     "yaml",
+			exporter: Exporter{Format: OutputYAML},
+			data:     items[:1],
+			wants:    "- id: \"1\"\n  name: api\n  size: 10\n",
+		},
+		{
+			name:     "jq",
+			exporter: Exporter{JQ: ".[].name"},
+			data:     items,
+			wants:    "\"api\"\n\"web\"\n",
+		},
+		{
+			name:     "template",
+			exporter: Exporter{Template: "{{range .}}{{.name}}={{.size}} {{end}}"},
+			data:     items,
+			wants:    "api=10 web=20 ",
+		},
+		{
+			name:     "empty list",
+			exporter: Exporter{Format: OutputJSON},
+			data:     []exportItem(nil),
+			wants:    "[]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := tt.exporter.Write(out, tt.data)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wants, out.String())
+		})
+	}
+}
+
+func TestExporterValidate(t *testing.T) {
+	assert.NoError(t, (&Exporter{Format: OutputCSV}).Validate())
+	assert.NoError(t, (&Exporter{Template: "{{json .}}"}).Validate())
+	assert.Error(t, (&Exporter{Format: "xml"}).Validate())
+	assert.Error(t, (&Exporter{JQ: ".[", Format: OutputJSON}).Validate())
+	assert.Error(t, (&Exporter{JQ: ".", Template: "{{.}}"}).Validate())
+}
+
+func TestExporterWriteRecord(t *testing.T) {
+	out := &bytes.Buffer{}
+	exporter := &Exporter{Format: OutputJSON}
+
+	assert.NoError(t, exporter.WriteRecord(out, exportItem{Name: "api", ID: "1"}))
+	assert.NoError(t, exporter.WriteRecord(out, exportItem{Name: "web", ID: "2"}))
+	assert.Equal(t, "{\"id\":\"1\",\"name\":\"api\",\"size\":0}\n{\"id\":\"2\",\"name\":\"web\",\"size\":0}\n", out.String())
+}
+
+func TestExporterWriteRecordCSV(t *testing.T) {
+	type record struct {
+		Message string `json:"message,omitempty"`
+		Level   string `json:"level,omitempty"`
+		state   int
+	}
+
+	out := &bytes.Buffer{}
+	exporter := &Exporter{Format: OutputCSV}
+	assert.NoError(t, exporter.WriteRecord(out, &record{Message: "started"}))
+	assert.NoError(t, exporter.WriteRecord(out, &record{Message: "failed", Level: "error"}))
+	assert.Equal(t, "level,message\n,started\nerror,failed\n", out.String())
+
+	out.Reset()
+	exporter = &Exporter{Format: OutputCSV, JQ: "{name, size}"}
+	assert.NoError(t, exporter.WriteRecord(out, exportItem{Name: "api", Size: 1}))
+	assert.NoError(t, exporter.WriteRecord(out, map[string]interface{}{"id": "2"}))
+	assert.Equal(t, "name,size\napi,1\n,\n", out.String())
+}
+
+func TestEnableExport(t *testing.T) {
+	cmd := &cobra.Command{Use: "list"}
+	assert.False(t, IsExportEnabled(cmd))
+
+	EnableExport(cmd)
+	assert.True(t, IsExportEnabled(cmd))
+}
@@ -14,4 +14,18 @@ type Factory struct {
 
 	HttpClient func() *http.Client
 	Config     func() (config.Config, error)
+
+	// Profile is the config context selected with the global --profile flag.
+	Profile string
+	// CreateProfile lets Profile name a context that is not saved yet, for the commands that
+	// create it.
+	CreateProfile bool
+	// ConfigOverrides are bound to the global --endpoint and --grpc-endpoint flags
+	// and take precedence over the LOGFIRE_* environment variables.
+	ConfigOverrides config.Overrides
+	// Debug is bound to the global --debug flag and traces HTTP and gRPC traffic to stderr.
+	Debug bool
+
+	// Exporter is bound to the global --output, --jq and --template flags.
+	Exporter *Exporter
 }
@@ -0,0 +1,348 @@
+package filters
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	pb "github.com/logfire-sh/cli/services/flink-service"
+)
+
+// Query is a compiled filter expression, ready to be added to a FilterRequest.
+//
+// An expression is a list of terms separated by spaces, all of which must match:
+//
+//	level=error             field equals value
+//	status>=500             field compared with value (>, >=, <, <=, !=)
+//	msg:"timeout"           field contains value (!: for does not contain)
+//	-path:/health           a leading - negates a field term
+//	timeout "disk full"     words and quoted phrases are searched in the whole record
+//	-healthcheck            a negated word excludes records whose message contains it
+//
+// Values with spaces or operator characters are quoted; \" and \\ escape inside quotes.
+type Query struct {
+	FieldFilters  []*pb.FieldBasedFilter
+	SearchQueries []string
+}
+
+// SyntaxError reports where an expression could not be parsed.
+type SyntaxError struct {
+	Input string
+	// Pos is the offset of the error in Input, in characters.
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid filter at column %d: %s\n  %s\n  %s^", e.Pos+1, e.Msg, e.Input, strings.Repeat(" ", e.Pos))
+}
+
+// operators maps the operator symbols of the language, longest first, to the filter operators.
+var operators = []struct {
+	symbol   string
+	operator pb.FieldBasedFilter_Operator
+}{
+	{">=", pb.FieldBasedFilter_GREATER_THAN_EQUALS},
+	{"<=", pb.FieldBasedFilter_LESS_THAN_EQUALS},
+	{"!=", pb.FieldBasedFilter_NOT_EQUALS},
+	{"!:", pb.FieldBasedFilter_DOES_NOT_CONTAIN},
+	{"=", pb.FieldBasedFilter_EQUALS},
+	{":", pb.FieldBasedFilter_CONTAINS},
+	{">", pb.FieldBasedFilter_GREATER_THAN},
+	{"<", pb.FieldBasedFilter_LESS_THAN},
+}
+
+// negations maps every operator to the one matching exactly the other records.
+var negations = map[pb.FieldBasedFilter_Operator]pb.FieldBasedFilter_Operator{
+	pb.FieldBasedFilter_CONTAINS:            pb.FieldBasedFilter_DOES_NOT_CONTAIN,
+	pb.FieldBasedFilter_DOES_NOT_CONTAIN:    pb.FieldBasedFilter_CONTAINS,
+	pb.FieldBasedFilter_EQUALS:              pb.FieldBasedFilter_NOT_EQUALS,
+	pb.FieldBasedFilter_NOT_EQUALS:          pb.FieldBasedFilter_EQUALS,
+	pb.FieldBasedFilter_GREATER_THAN:        pb.FieldBasedFilter_LESS_THAN_EQUALS,
+	pb.FieldBasedFilter_GREATER_THAN_EQUALS: pb.FieldBasedFilter_LESS_THAN,
+	pb.FieldBasedFilter_LESS_THAN:           pb.FieldBasedFilter_GREATER_THAN_EQUALS,
+	pb.FieldBasedFilter_LESS_THAN_EQUALS:    pb.FieldBasedFilter_GREATER_THAN,
+}
+
+// ParseQuery compiles a filter expression. An empty expression gives an empty query.
+func ParseQuery(input string) (*Query, error) {
+	p := &parser{input: input}
+	query := &Query{}
+
+	for {
+		p.skipSpaces()
+		if p.done() {
+			return query, nil
+		}
+		if err := p.term(query); err != nil {
+			return nil, err
+		}
+	}
+}
+
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *parser) skipSpaces() {
+	for !p.done() && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *parser) errorf(pos int, format string, args ...interface{}) error {
+	return &SyntaxError{Input: p.input, Pos: utf8.RuneCountInString(p.input[:pos]), Msg: fmt.Sprintf(format, args...)}
+}
+
+// term parses one field term, word or phrase.
+func (p *parser) term(query *Query) error {
+	start := p.pos
+	negated := false
+	if p.input[p.pos] == '-' {
+		negated = true
+		p.pos++
+		if p.done() || p.input[p.pos] == ' ' || p.input[p.pos] == '\t' {
+			return p.errorf(start, "expected a field or word after -")
+		}
+	}
+
+	if p.input[p.pos] == '"' {
+		phrase, err := p.quoted()
+		if err != nil {
+			return err
+		}
+		if !p.done() && p.input[p.pos] != ' ' && p.input[p.pos] != '\t' {
+			return p.errorf(p.pos, "expected a space after the closing quote")
+		}
+		return addText(query, phrase, negated)
+	}
+
+	nameStart := p.pos
+	for !p.done() {
+		r, size := utf8.DecodeRuneInString(p.input[p.pos:])
+		if !isFieldChar(r) {
+			break
+		}
+		p.pos += size
+	}
+	name := p.input[nameStart:p.pos]
+
+	operator, symbol, ok := p.operator()
+	if !ok {
+		// Not a field term: the rest of the word is searched as text.
+		for !p.done() && p.input[p.pos] != ' ' && p.input[p.pos] != '\t' {
+			if p.input[p.pos] == '"' {
+				return p.errorf(p.pos, "unexpected quote inside a word; quote the whole phrase")
+			}
+			p.pos++
+		}
+		return addText(query, p.input[nameStart:p.pos], negated)
+	}
+
+	if name == "" {
+		return p.errorf(nameStart, "expected a field name before %s", symbol)
+	}
+
+	valueStart := p.pos
+	var value string
+	if !p.done() && p.input[p.pos] == '"' {
+		var err error
+		if value, err = p.quoted(); err != nil {
+			return err
+		}
+		if !p.done() && p.input[p.pos] != ' ' && p.input[p.pos] != '\t' {
+			return p.errorf(p.pos, "expected a space after the closing quote")
+		}
+	} else {
+		for !p.done() && p.input[p.pos] != ' ' && p.input[p.pos] != '\t' {
+			if p.input[p.pos] == '"' {
+				return p.errorf(p.pos, "unexpected quote inside a value; quote the whole value")
+			}
+			p.pos++
+		}
+		value = p.input[valueStart:p.pos]
+		if value == "" {
+			return p.errorf(valueStart, "expected a value after %s%s", name, symbol)
+		}
+	}
+
+	if negated {
+		operator = negations[operator]
+	}
+	query.FieldFilters = append(query.FieldFilters, &pb.FieldBasedFilter{
+		FieldName:  name,
+		FieldValue: value,
+		Operator:   operator,
+	})
+	return nil
+}
+
+func (p *parser) operator() (pb.FieldBasedFilter_Operator, string, bool) {
+	for _, op := range operators {
+		if strings.HasPrefix(p.input[p.pos:], op.symbol) {
+			p.pos += len(op.symbol)
+			return op.operator, op.symbol, true
+		}
+	}
+	return 0, "", false
+}
+
+// quoted reads a double quoted string starting at the current position.
+func (p *parser) quoted() (string, error) {
+	start := p.pos
+	p.pos++
+
+	var b strings.Builder
+	for !p.done() {
+		c := p.input[p.pos]
+		switch {
+		case c == '\\' && p.pos+1 < len(p.input):
+			b.WriteByte(p.input[p.pos+1])
+			p.pos += 2
+		case c == '"':
+			p.pos++
+			if b.Len() == 0 {
+				return "", p.errorf(start, "empty quotes")
+			}
+			return b.String(), nil
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", p.errorf(start, "missing closing quote")
+}
+
+func isFieldChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '@' || r == '-'
+}
+
+func addText(query *Query, text string, negated bool) error {
+	if negated {
+		// SearchQueries cannot exclude, so a negated word becomes a condition on the message.
+		query.FieldFilters = append(query.FieldFilters, &pb.FieldBasedFilter{
+			FieldName:  "message",
+			FieldValue: text,
+			Operator:   pb.FieldBasedFilter_DOES_NOT_CONTAIN,
+		})
+		return nil
+	}
+	query.SearchQueries = append(query.SearchQueries, text)
+	return nil
+}
+
+// Merge adds the terms of other to q.
+func (q *Query) Merge(other *Query) {
+	if other == nil {
+		return
+	}
+	q.FieldFilters = append(q.FieldFilters, other.FieldFilters...)
+	q.SearchQueries = append(q.SearchQueries, other.SearchQueries...)
+}
+
+// Apply adds the filters of q to request.
+func (q *Query) Apply(request *pb.FilterRequest) {
+	if q == nil {
+		return
+	}
+	request.FieldBasedFilters = append(request.FieldBasedFilters, q.FieldFilters...)
+	request.SearchQueries = append(request.SearchQueries, q.SearchQueries...)
+}
+
+// IsEmpty reports whether q matches every record.
+func (q *Query) IsEmpty() bool {
+	return q == nil || len(q.FieldFilters) == 0 && len(q.SearchQueries) == 0
+}
+
+// String formats q as an expression that ParseQuery turns back into q.
+func (q *Query) String() string {
+	if q == nil {
+		return ""
+	}
+
+	var terms []string
+	for _, filter := range q.FieldFilters {
+		terms = append(terms, filter.FieldName+OperatorSymbol(filter.Operator)+quote(filter.FieldValue))
+	}
+	for _, search := range q.SearchQueries {
+		terms = append(terms, quote(search))
+	}
+	return strings.Join(terms, " ")
+}
+
+// FieldTerm formats the term matching the records whose field name equals value.
+func FieldTerm(name, value string) string {
+	return name + "=" + quote(value)
+}
+
+func quote(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\"\\=:<>!") && !strings.HasPrefix(value, "-") {
+		return value
+	}
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+}
+
+// OperatorSymbol returns the symbol of operator in the filter language.
+func OperatorSymbol(operator pb.FieldBasedFilter_Operator) string {
+	for _, op := range operators {
+		if op.operator == operator {
+			return op.symbol
+		}
+	}
+	return "="
+}
+
+// ParseOperator accepts an operator symbol such as ">=", or an operator name such as
+// "greater_than_equals" in any case, as used by the --field-condition flags and by views.
+func ParseOperator(condition string) (pb.FieldBasedFilter_Operator, error) {
+	for _, op := range operators {
+		if op.symbol == condition {
+			return op.operator, nil
+		}
+	}
+	if value, ok := pb.FieldBasedFilter_Operator_value[strings.ToUpper(condition)]; ok {
+		return pb.FieldBasedFilter_Operator(value), nil
+	}
+	return 0, fmt.Errorf("unknown condition %q", condition)
+}
+
+// FieldFilter builds a query from a single field, value and condition, as given by the
+// --field-name, --field-value and --field-condition flags. All three empty give an empty query.
+func FieldFilter(name, value, condition string) (*Query, error) {
+	if name == "" && value == "" && condition == "" {
+		return &Query{}, nil
+	}
+	if name == "" || value == "" || condition == "" {
+		return nil, fmt.Errorf("field name, value and condition must be given together")
+	}
+
+	operator, err := ParseOperator(condition)
+	if err != nil {
+		return nil, err
+	}
+	return &Query{FieldFilters: []*pb.FieldBasedFilter{{FieldName: name, FieldValue: value, Operator: operator}}}, nil
+}
+
+// FlagQuery combines the --filter expression of the tail commands with their older --search,
+// --field-name, --field-value and --field-condition flags.
+func FlagQuery(expression string, search []string, name, value, condition string) (*Query, error) {
+	query, err := ParseQuery(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	field, err := FieldFilter(name, value, condition)
+	if err != nil {
+		return nil, err
+	}
+	query.Merge(field)
+	query.SearchQueries = append(query.SearchQueries, search...)
+	return query, nil
+}
@@ -0,0 +1,144 @@
+package filters
+
+import (
+	"testing"
+
+	pb "github.com/logfire-sh/cli/services/flink-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		filters []*pb.FieldBasedFilter
+		search  []string
+	}{
+		{
+			name:  "empty",
+			input: "  ",
+		},
+		{
+			name:  "operators",
+			input: `level=error status>=500 msg:timeout code!=0 size<10 n>1 m<=2 path!:/api`,
+			filters: []*pb.FieldBasedFilter{
+				{FieldName: "level", FieldValue: "error", Operator: pb.FieldBasedFilter_EQUALS},
+				{FieldName: "status", FieldValue: "500", Operator: pb.FieldBasedFilter_GREATER_THAN_EQUALS},
+				{FieldName: "msg", FieldValue: "timeout", Operator: pb.FieldBasedFilter_CONTAINS},
+				{FieldName: "code", FieldValue: "0", Operator: pb.FieldBasedFilter_NOT_EQUALS},
+				{FieldName: "size", FieldValue: "10", Operator: pb.FieldBasedFilter_LESS_THAN},
+				{FieldName: "n", FieldValue: "1", Operator: pb.FieldBasedFilter_GREATER_THAN},
+				{FieldName: "m", FieldValue: "2", Operator: pb.FieldBasedFilter_LESS_THAN_EQUALS},
+				{FieldName: "path", FieldValue: "/api", Operator: pb.FieldBasedFilter_DOES_NOT_CONTAIN},
+			},
+		},
+		{
+			name:  "quoted values and phrases",
+			input: `msg:"connection timeout" "disk full" user.name="say \"hi\""`,
+			filters: []*pb.FieldBasedFilter{
+				{FieldName: "msg", FieldValue: "connection timeout", Operator: pb.FieldBasedFilter_CONTAINS},
+				{FieldName: "user.name", FieldValue: `say "hi"`, Operator: pb.FieldBasedFilter_EQUALS},
+			},
+			search: []string{"disk full"},
+		},
+		{
+			name:  "negations",
+			input: `-path:/health -level=debug -status>=500 -healthcheck`,
+			filters: []*pb.FieldBasedFilter{
+				{FieldName: "path", FieldValue: "/health", Operator: pb.FieldBasedFilter_DOES_NOT_CONTAIN},
+				{FieldName: "level", FieldValue: "debug", Operator: pb.FieldBasedFilter_NOT_EQUALS},
+				{FieldName: "status", FieldValue: "500", Operator: pb.FieldBasedFilter_LESS_THAN},
+				{FieldName: "message", FieldValue: "healthcheck", Operator: pb.FieldBasedFilter_DOES_NOT_CONTAIN},
+			},
+		},
+		{
+			name:  "non-ASCII field names",
+			input: `größe>=5 ユーザー=太郎`,
+			filters: []*pb.FieldBasedFilter{
+				{FieldName: "größe", FieldValue: "5", Operator: pb.FieldBasedFilter_GREATER_THAN_EQUALS},
+				{FieldName: "ユーザー", FieldValue: "太郎", Operator: pb.FieldBasedFilter_EQUALS},
+			},
+		},
+		{
+			name:   "words",
+			input:  "timeout\tretry/42",
+			search: []string{"timeout", "retry/42"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := ParseQuery(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.filters, query.FieldFilters)
+			assert.Equal(t, tt.search, query.SearchQueries)
+
+			again, err := ParseQuery(query.String())
+			require.NoError(t, err)
+			assert.Equal(t, query, again)
+		})
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		pos   int
+		msg   string
+	}{
+		{input: "level=", pos: 6, msg: "expected a value after level="},
+		{input: "a=1 =error", pos: 4, msg: "expected a field name before ="},
+		{input: `msg:"timeout`, pos: 4, msg: "missing closing quote"},
+		{input: `msg:""`, pos: 4, msg: "empty quotes"},
+		{input: `"a"b`, pos: 3, msg: "expected a space after the closing quote"},
+		{input: `msg:time"out"`, pos: 8, msg: "unexpected quote inside a value; quote the whole value"},
+		{input: "a - b", pos: 2, msg: "expected a field or word after -"},
+		{input: "größe=", pos: 6, msg: "expected a value after größe="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := ParseQuery(tt.input)
+			var syntaxErr *SyntaxError
+			require.ErrorAs(t, err, &syntaxErr)
+			assert.Equal(t, tt.pos, syntaxErr.Pos)
+			assert.Equal(t, tt.msg, syntaxErr.Msg)
+		})
+	}
+}
+
+func TestSyntaxErrorColumn(t *testing.T) {
+	_, err := ParseQuery(`ä=1 ö:"x`)
+	assert.EqualError(t, err, "invalid filter at column 7: missing closing quote\n  ä=1 ö:\"x\n        ^")
+}
+
+func TestFieldFilter(t *testing.T) {
+	query, err := FieldFilter("status", "500", "greater_than_equals")
+	require.NoError(t, err)
+	assert.Equal(t, "status>=500", query.String())
+
+	query, err = FieldFilter("status", "500", "!=")
+	require.NoError(t, err)
+	assert.Equal(t, "status!=500", query.String())
+
+	query, err = FieldFilter("", "", "")
+	require.NoError(t, err)
+	assert.True(t, query.IsEmpty())
+
+	_, err = FieldFilter("status", "", "=")
+	assert.Error(t, err)
+	_, err = FieldFilter("status", "500", "about")
+	assert.Error(t, err)
+}
+
+func TestFieldTerm(t *testing.T) {
+	assert.Equal(t, "level=error", FieldTerm("level", "error"))
+	assert.Equal(t, `user.name="say \"hi\""`, FieldTerm("user.name", `say "hi"`))
+
+	query, err := ParseQuery(FieldTerm("path", "-/health a=b"))
+	require.NoError(t, err)
+	assert.Equal(t, []*pb.FieldBasedFilter{
+		{FieldName: "path", FieldValue: "-/health a=b", Operator: pb.FieldBasedFilter_EQUALS},
+	}, query.FieldFilters)
+}
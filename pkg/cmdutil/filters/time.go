@@ -0,0 +1,149 @@
+package filters
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// absoluteLayouts are the date formats accepted by ParseTime, tried in order. Those without a
+// zone are read in the zone of the expression.
+var absoluteLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+var (
+	offsetPattern   = regexp.MustCompile(`^[+-](\d+[wdhms])+$`)
+	durationPattern = regexp.MustCompile(`(\d+)([wdhms])`)
+	utcOffset       = regexp.MustCompile(`^[+-]\d{2}:\d{2}$`)
+)
+
+// ParseTime reads the time expressions of the date flags, relative to now:
+//
+//	now, now-2h, now-1h30m, now-1w      now, shifted by weeks, days, hours, minutes and seconds
+//	-15m, +1d                           the same, with now left out
+//	today, yesterday, today+9h          midnight, shifted the same way
+//	2024-05-01, 2024-05-01 22:00        a date or a date and time
+//	2024-05-01T22:00:00+02:00           an RFC 3339 timestamp
+//
+// Expressions without an explicit offset use the zone of now, unless they end with a zone such
+// as "UTC", "Europe/Berlin" or "+02:00": "yesterday Asia/Tokyo", "2024-05-01 09:00 UTC".
+func ParseTime(expression string, now time.Time) (time.Time, error) {
+	value := strings.TrimSpace(expression)
+	loc := now.Location()
+
+	if i := strings.LastIndexAny(value, " \t"); i > 0 {
+		if zone, err := parseZone(value[i+1:]); err == nil {
+			value, loc = strings.TrimSpace(value[:i]), zone
+		}
+	}
+	now = now.In(loc)
+
+	lower := strings.ToLower(value)
+	for _, base := range []string{"now", "today", "yesterday", ""} {
+		if !strings.HasPrefix(lower, base) {
+			continue
+		}
+		offset := lower[len(base):]
+		if offset != "" && !offsetPattern.MatchString(offset) {
+			continue
+		}
+
+		t := now
+		switch base {
+		case "today":
+			t = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+		case "yesterday":
+			t = time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, loc)
+		case "":
+			if offset == "" {
+				continue
+			}
+		}
+		return shift(t, offset), nil
+	}
+
+	for _, layout := range absoluteLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q: expected now, today or yesterday with an optional offset such as now-1h30m, a date such as 2024-05-01 or an RFC 3339 timestamp", expression)
+}
+
+// shift moves t by an offset matching offsetPattern, such as "-1d12h". Days and weeks follow
+// the calendar, so that today-1d is the previous midnight across daylight saving changes.
+func shift(t time.Time, offset string) time.Time {
+	if offset == "" {
+		return t
+	}
+	sign := 1
+	if offset[0] == '-' {
+		sign = -1
+	}
+
+	for _, match := range durationPattern.FindAllStringSubmatch(offset, -1) {
+		n, _ := strconv.Atoi(match[1])
+		n *= sign
+		switch match[2] {
+		case "w":
+			t = t.AddDate(0, 0, 7*n)
+		case "d":
+			t = t.AddDate(0, 0, n)
+		case "h":
+			t = t.Add(time.Duration(n) * time.Hour)
+		case "m":
+			t = t.Add(time.Duration(n) * time.Minute)
+		case "s":
+			t = t.Add(time.Duration(n) * time.Second)
+		}
+	}
+	return t
+}
+
+func parseZone(zone string) (*time.Location, error) {
+	switch {
+	case zone == "Z" || strings.EqualFold(zone, "utc"):
+		return time.UTC, nil
+	case utcOffset.MatchString(zone):
+		hours, _ := strconv.Atoi(zone[1:3])
+		minutes, _ := strconv.Atoi(zone[4:])
+		seconds := hours*3600 + minutes*60
+		if zone[0] == '-' {
+			seconds = -seconds
+		}
+		return time.FixedZone(zone, seconds), nil
+	case strings.Contains(zone, "/"):
+		return time.LoadLocation(zone)
+	}
+	return nil, fmt.Errorf("unknown zone %q", zone)
+}
+
+// ParseTimeRange parses the start and end date flags of a command relative to the same now.
+// An empty expression gives the zero time.
+func ParseTimeRange(start, end string, now time.Time) (time.Time, time.Time, error) {
+	var startTime, endTime time.Time
+	var err error
+	if start != "" {
+		if startTime, err = ParseTime(start, now); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("start date: %w", err)
+		}
+	}
+	if end != "" {
+		if endTime, err = ParseTime(end, now); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("end date: %w", err)
+		}
+	}
+	if !startTime.IsZero() && !endTime.IsZero() && !startTime.Before(endTime) {
+		return time.Time{}, time.Time{}, fmt.Errorf("start date %s is not before end date %s", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339))
+	}
+	return startTime, endTime, nil
+}
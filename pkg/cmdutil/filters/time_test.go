@@ -0,0 +1,72 @@
+package filters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	now := time.Date(2024, 5, 10, 15, 30, 0, 0, berlin)
+
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{input: "now", want: now},
+		{input: " NOW ", want: now},
+		{input: "now-2h", want: now.Add(-2 * time.Hour)},
+		{input: "now-1h30m", want: now.Add(-90 * time.Minute)},
+		{input: "now-1w", want: time.Date(2024, 5, 3, 15, 30, 0, 0, berlin)},
+		{input: "now+1d12h", want: time.Date(2024, 5, 12, 3, 30, 0, 0, berlin)},
+		{input: "-15m", want: now.Add(-15 * time.Minute)},
+		{input: "today", want: time.Date(2024, 5, 10, 0, 0, 0, 0, berlin)},
+		{input: "today+9h", want: time.Date(2024, 5, 10, 9, 0, 0, 0, berlin)},
+		{input: "yesterday", want: time.Date(2024, 5, 9, 0, 0, 0, 0, berlin)},
+		{input: "yesterday UTC", want: time.Date(2024, 5, 9, 0, 0, 0, 0, time.UTC)},
+		{input: "today Asia/Tokyo", want: time.Date(2024, 5, 10, 0, 0, 0, 0, time.FixedZone("JST", 9*3600))},
+		{input: "2024-05-01", want: time.Date(2024, 5, 1, 0, 0, 0, 0, berlin)},
+		{input: "2024-05-01 22:00", want: time.Date(2024, 5, 1, 22, 0, 0, 0, berlin)},
+		{input: "2024-05-01T22:00:05", want: time.Date(2024, 5, 1, 22, 0, 5, 0, berlin)},
+		{input: "2024-05-01 22:00 UTC", want: time.Date(2024, 5, 1, 22, 0, 0, 0, time.UTC)},
+		{input: "2024-05-01 22:00 -05:00", want: time.Date(2024, 5, 2, 3, 0, 0, 0, time.UTC)},
+		{input: "2024-05-01T22:00:00Z", want: time.Date(2024, 5, 1, 22, 0, 0, 0, time.UTC)},
+		{input: "2024-05-01T22:00:00.5+02:00", want: time.Date(2024, 5, 1, 20, 0, 0, 500000000, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseTime(tt.input, now)
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "got %s, want %s", got, tt.want)
+		})
+	}
+}
+
+func TestParseTimeErrors(t *testing.T) {
+	for _, input := range []string{"", "now-", "now-2", "now-2y", "nowish", "tomorrow", "2024-13-01", "2024-05-01 Mars/Olympus", "last week"} {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseTime(input, time.Now())
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseTimeRange(t *testing.T) {
+	now := time.Date(2024, 5, 10, 15, 30, 0, 0, time.UTC)
+
+	start, end, err := ParseTimeRange("now-1h", "", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-time.Hour), start)
+	assert.True(t, end.IsZero())
+
+	_, _, err = ParseTimeRange("now", "now-1h", now)
+	assert.EqualError(t, err, "start date 2024-05-10T15:30:00Z is not before end date 2024-05-10T14:30:00Z")
+
+	_, _, err = ParseTimeRange("2024-05-01", "soon", now)
+	assert.ErrorContains(t, err, `end date: invalid time "soon"`)
+}
@@ -0,0 +1,110 @@
+package grpcutil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/logfire-sh/cli/internal/fsutil"
+	pb "github.com/logfire-sh/cli/services/flink-service"
+)
+
+// Checkpoint is a file recording, for every source of a stream, the offset of the next record to
+// read, so that a restarted stream resumes exactly where the previous one stopped.
+type Checkpoint struct {
+	path string
+
+	Updated time.Time `json:"updated"`
+	// Offsets maps the SourceID of a pb.Source to its next StartingOffset.
+	Offsets map[string]uint64 `json:"offsets"`
+}
+
+// LoadCheckpoint reads the checkpoint at path. A missing file gives an empty checkpoint.
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	checkpoint := &Checkpoint{path: path, Offsets: map[string]uint64{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return checkpoint, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint %s: %w", path, err)
+	}
+	if checkpoint.Offsets == nil {
+		checkpoint.Offsets = map[string]uint64{}
+	}
+	return checkpoint, nil
+}
+
+// Resume sets the StartingOffset of the sources found in the checkpoint and returns the others.
+func (c *Checkpoint) Resume(sources []*pb.Source) (missing []*pb.Source) {
+	for _, source := range sources {
+		offset, ok := c.Offsets[source.SourceID]
+		if !ok {
+			missing = append(missing, source)
+			continue
+		}
+		source.StartingOffset = offset
+	}
+	return missing
+}
+
+// Save records the StartingOffset of sources and replaces the file atomically, so that an
+// interrupted write leaves the previous checkpoint in place.
+func (c *Checkpoint) Save(sources []*pb.Source) error {
+	for _, source := range sources {
+		c.Offsets[source.SourceID] = source.StartingOffset
+	}
+	c.Updated = time.Now().UTC()
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+	return fsutil.WriteFile(c.path, data, 0600)
+}
+
+// SeedOffsets sets the StartingOffset of sources from GetOffsetData: the oldest stored record when
+// earliest is set, otherwise the first record stored after now.
+func (fs *FilterService) SeedOffsets(ctx context.Context, teamId string, sources []*pb.Source, earliest bool) error {
+	if len(sources) == 0 {
+		return nil
+	}
+
+	request := &pb.OffsetRequest{TeamID: teamId}
+	for _, source := range sources {
+		request.IDs = append(request.IDs, source.SourceID)
+	}
+
+	response, err := fs.Client.GetOffsetData(ctx, request)
+	if err != nil {
+		return fmt.Errorf("failed to get the offsets of the sources: %w", err)
+	}
+
+	for _, source := range sources {
+		for _, offsets := range response.OffsetRecords {
+			if strings.TrimPrefix(offsets.ID, sourceTopicPrefix) != strings.TrimPrefix(source.SourceID, sourceTopicPrefix) {
+				continue
+			}
+			// An empty source has a MaxOffset below its MinOffset.
+			if earliest || offsets.MaxOffset < offsets.MinOffset {
+				source.StartingOffset = uint64(offsets.MinOffset)
+			} else {
+				source.StartingOffset = uint64(offsets.MaxOffset + 1)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package grpcutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "github.com/logfire-sh/cli/services/flink-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "tail.checkpoint")
+
+	checkpoint, err := LoadCheckpoint(path)
+	require.NoError(t, err)
+	sources := []*pb.Source{{SourceID: "source_topic_a", StartingOffset: 7}, {SourceID: "source_topic_b"}}
+	require.NoError(t, checkpoint.Save(sources))
+
+	reloaded, err := LoadCheckpoint(path)
+	require.NoError(t, err)
+	resumed := []*pb.Source{{SourceID: "source_topic_a"}, {SourceID: "source_topic_b"}, {SourceID: "source_topic_c"}}
+	missing := reloaded.Resume(resumed)
+	assert.Equal(t, uint64(7), resumed[0].StartingOffset)
+	assert.Equal(t, uint64(0), resumed[1].StartingOffset)
+	assert.Equal(t, []*pb.Source{resumed[2]}, missing)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary files are removed")
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o644))
+	_, err = LoadCheckpoint(path)
+	assert.Error(t, err)
+}
+
+type offsetFilterClient struct {
+	pb.FilterServiceClient
+
+	request *pb.OffsetRequest
+	records []*pb.OffsetRecord
+}
+
+func (c *offsetFilterClient) GetOffsetData(ctx context.Context, in *pb.OffsetRequest, opts ...grpc.CallOption) (*pb.OffsetResponse, error) {
+	c.request = in
+	return &pb.OffsetResponse{OffsetRecords: c.records}, nil
+}
+
+func TestSeedOffsets(t *testing.T) {
+	records := []*pb.OffsetRecord{
+		{ID: "source_topic_a", MinOffset: 3, MaxOffset: 9},
+		{ID: "b", MinOffset: 0, MaxOffset: -1},
+	}
+
+	tests := []struct {
+		name     string
+		earliest bool
+		want     []uint64
+	}{
+		{name: "latest", want: []uint64{10, 0}},
+		{name: "earliest", earliest: true, want: []uint64{3, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &offsetFilterClient{records: records}
+			fs := &FilterService{Client: client}
+			sources := []*pb.Source{{SourceID: "source_topic_a"}, {SourceID: "source_topic_b"}}
+
+			require.NoError(t, fs.SeedOffsets(context.Background(), "team", sources, tt.earliest))
+			assert.Equal(t, []string{"source_topic_a", "source_topic_b"}, client.request.IDs)
+			assert.Equal(t, tt.want, []uint64{sources[0].StartingOffset, sources[1].StartingOffset})
+		})
+	}
+}
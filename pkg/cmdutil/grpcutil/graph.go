@@ -0,0 +1,96 @@
+package grpcutil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/logfire-sh/cli/internal/config"
+	pb "github.com/logfire-sh/cli/services/flink-service"
+	"google.golang.org/grpc"
+)
+
+// MetaService reads aggregates of the logs, such as their volume over time.
+type MetaService struct {
+	conn   *grpc.ClientConn
+	Client pb.MetaServiceClient
+}
+
+// NewMetaService dials the gRPC endpoint of cfg like NewFilterService.
+func NewMetaService(cfg config.Config, kv ...string) *MetaService {
+	conn := dial(cfg, kv...)
+	return &MetaService{
+		conn:   conn,
+		Client: pb.NewMetaServiceClient(conn),
+	}
+}
+
+func (ms *MetaService) CloseConnection() {
+	err := ms.conn.Close()
+	if err != nil {
+		log.Printf("Failed to close connection: %v", err)
+	}
+}
+
+// ErrGraphFormat is returned for a GraphResponse whose meta is not in the layout read by
+// parseGraphBucket. That layout is the one served by `logfire dev server`: the bar graph is
+// experimental until the layout of the Logfire API is confirmed to match it.
+var ErrGraphFormat = errors.New("the bar graph of the server is in a format this version of the CLI does not read")
+
+// GraphBucket is one bar of GetBarGraph: the records of the time range starting at Dt, counted
+// in total, by level and by source name.
+type GraphBucket struct {
+	Dt      time.Time      `json:"dt"`
+	Count   int            `json:"count"`
+	Levels  map[string]int `json:"levels"`
+	Sources map[string]int `json:"sources"`
+}
+
+// BarGraph returns the buckets streamed by GetBarGraph for request, oldest first.
+func (ms *MetaService) BarGraph(ctx context.Context, request *pb.GraphRequest) ([]GraphBucket, error) {
+	stream, err := ms.Client.GetBarGraph(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	var buckets []GraphBucket
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			return buckets, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		bucket, err := parseGraphBucket(response.Meta)
+		if err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, bucket)
+	}
+}
+
+// parseGraphBucket decodes the JSON meta of a GraphResponse, such as
+// {"dt":"2024-05-01T22:00:00Z","count":3,"levels":{"error":1,"info":2},"sources":{"api":3}}.
+func parseGraphBucket(meta string) (GraphBucket, error) {
+	var bucket struct {
+		Dt      string         `json:"dt"`
+		Count   int            `json:"count"`
+		Levels  map[string]int `json:"levels"`
+		Sources map[string]int `json:"sources"`
+	}
+	if err := json.Unmarshal([]byte(meta), &bucket); err != nil {
+		return GraphBucket{}, fmt.Errorf("%w: invalid bar graph bucket %q: %s", ErrGraphFormat, meta, err)
+	}
+
+	dt, err := time.Parse(time.RFC3339Nano, bucket.Dt)
+	if err != nil {
+		return GraphBucket{}, fmt.Errorf("%w: invalid time of bar graph bucket %q", ErrGraphFormat, meta)
+	}
+	return GraphBucket{Dt: dt, Count: bucket.Count, Levels: bucket.Levels, Sources: bucket.Sources}, nil
+}
@@ -0,0 +1,68 @@
+package grpcutil
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	pb "github.com/logfire-sh/cli/services/flink-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type fakeMetaClient struct {
+	pb.MetaServiceClient
+	metas []string
+}
+
+func (f *fakeMetaClient) GetBarGraph(ctx context.Context, in *pb.GraphRequest, opts ...grpc.CallOption) (pb.MetaService_GetBarGraphClient, error) {
+	return &fakeGraphStream{metas: f.metas}, nil
+}
+
+type fakeGraphStream struct {
+	pb.MetaService_GetBarGraphClient
+	metas []string
+}
+
+func (s *fakeGraphStream) Recv() (*pb.GraphResponse, error) {
+	if len(s.metas) == 0 {
+		return nil, io.EOF
+	}
+	meta := s.metas[0]
+	s.metas = s.metas[1:]
+	return &pb.GraphResponse{Meta: meta}, nil
+}
+
+func TestBarGraph(t *testing.T) {
+	ms := &MetaService{Client: &fakeMetaClient{metas: []string{
+		`{"dt":"2024-05-01T22:00:00Z","count":3,"levels":{"error":1,"info":2},"sources":{"api":3}}`,
+		`{"dt":"2024-05-01T22:01:00Z","count":0,"levels":{},"sources":{}}`,
+	}}}
+
+	buckets, err := ms.BarGraph(context.Background(), &pb.GraphRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, []GraphBucket{
+		{
+			Dt:      time.Date(2024, 5, 1, 22, 0, 0, 0, time.UTC),
+			Count:   3,
+			Levels:  map[string]int{"error": 1, "info": 2},
+			Sources: map[string]int{"api": 3},
+		},
+		{
+			Dt:      time.Date(2024, 5, 1, 22, 1, 0, 0, time.UTC),
+			Levels:  map[string]int{},
+			Sources: map[string]int{},
+		},
+	}, buckets)
+}
+
+func TestBarGraphInvalidMeta(t *testing.T) {
+	for _, meta := range []string{`not json`, `{"dt":"yesterday","count":1}`} {
+		ms := &MetaService{Client: &fakeMetaClient{metas: []string{meta}}}
+		_, err := ms.BarGraph(context.Background(), &pb.GraphRequest{})
+		assert.ErrorIs(t, err, ErrGraphFormat)
+		assert.ErrorContains(t, err, "bar graph bucket")
+	}
+}
@@ -5,30 +5,114 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"log"
+	"net"
+	"strings"
 	"time"
 
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
+	"github.com/logfire-sh/cli/internal/debug"
 	"github.com/logfire-sh/cli/pkg/cmd/sources/models"
 	pb "github.com/logfire-sh/cli/services/flink-service"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 type FilterService struct {
 	conn   *grpc.ClientConn
 	Client pb.FilterServiceClient
+
+	// OnConnect, when set, is called once StreamRecords has opened a stream, or polled
+	// successfully after starting or failing.
+	OnConnect func()
+	// OnReconnect, when set, is called with the error of every stream that StreamRecords reopens.
+	OnReconnect func(err error)
+}
+
+// authContext attaches the current access token of cfg and the extra metadata pairs kv to ctx.
+func authContext(ctx context.Context, cfg config.Config, kv ...string) (context.Context, string) {
+	token := cfg.Get().Token
+	md := metadata.Pairs(append([]string{"Authorization", "Bearer " + token}, kv...)...)
+	return metadata.NewOutgoingContext(ctx, md), token
 }
 
-func authUnaryInterceptor(kv ...string) func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+// authUnaryInterceptor retries a call once with a refreshed access token when the server answers UNAUTHENTICATED.
+func authUnaryInterceptor(cfg config.Config, kv ...string) func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
 	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
-		md := metadata.Pairs(kv...)
-		ctx = metadata.NewOutgoingContext(ctx, md)
-		return invoker(ctx, method, req, reply, cc, opts...)
+		authCtx, token := authContext(ctx, cfg, kv...)
+		err := invoker(authCtx, method, req, reply, cc, opts...)
+		if status.Code(err) != codes.Unauthenticated {
+			return err
+		}
+
+		if _, refreshErr := api.RefreshToken(cfg, token); refreshErr != nil {
+			return refreshError(refreshErr)
+		}
+
+		authCtx, _ = authContext(ctx, cfg, kv...)
+		return invoker(authCtx, method, req, reply, cc, opts...)
 	}
 }
 
+// authStreamInterceptor refreshes the access token when a stream is rejected as UNAUTHENTICATED.
+// A stream rejected when it is opened is opened again with the new token, while a stream ended
+// by the server while receiving returns a tokenRefreshedError, on which StreamRecords reconnects.
+func authStreamInterceptor(cfg config.Config, kv ...string) func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		authCtx, token := authContext(ctx, cfg, kv...)
+		stream, err := streamer(authCtx, desc, cc, method, opts...)
+		if status.Code(err) == codes.Unauthenticated {
+			if _, refreshErr := api.RefreshToken(cfg, token); refreshErr != nil {
+				return nil, refreshError(refreshErr)
+			}
+			authCtx, token = authContext(ctx, cfg, kv...)
+			stream, err = streamer(authCtx, desc, cc, method, opts...)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &authClientStream{ClientStream: stream, cfg: cfg, token: token}, nil
+	}
+}
+
+// refreshError explains an UNAUTHENTICATED call by the failed refresh of its token, such as an
+// expired session or an expired LOGFIRE_TOKEN.
+func refreshError(refreshErr error) error {
+	return status.Error(codes.Unauthenticated, refreshErr.Error())
+}
+
+// tokenRefreshedError is the UNAUTHENTICATED error of a stream after which the access token was
+// refreshed, so that a new stream is authorized again.
+type tokenRefreshedError struct {
+	err error
+}
+
+func (e *tokenRefreshedError) Error() string { return e.err.Error() }
+
+func (e *tokenRefreshedError) Unwrap() error { return e.err }
+
+type authClientStream struct {
+	grpc.ClientStream
+	cfg   config.Config
+	token string
+}
+
+func (s *authClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if status.Code(err) != codes.Unauthenticated {
+		return err
+	}
+	if _, refreshErr := api.RefreshToken(s.cfg, s.token); refreshErr != nil {
+		return refreshError(refreshErr)
+	}
+	return &tokenRefreshedError{err}
+}
+
 func (fs *FilterService) CloseConnection() {
 	err := fs.conn.Close()
 	if err != nil {
@@ -36,10 +120,20 @@ func (fs *FilterService) CloseConnection() {
 	}
 }
 
-func NewFilterService(kv ...string) *FilterService {
-	cfg, _ := config.NewConfig()
+// NewFilterService dials the gRPC endpoint of cfg and authenticates every call with its token.
+// kv are extra metadata pairs sent along with the Authorization header.
+func NewFilterService(cfg config.Config, kv ...string) *FilterService {
+	conn := dial(cfg, kv...)
+	return &FilterService{
+		conn:   conn,
+		Client: pb.NewFilterServiceClient(conn),
+	}
+}
+
+// dial connects to the gRPC endpoint of cfg, the connection being shared by the services of
+// flink-service.
+func dial(cfg config.Config, kv ...string) *grpc.ClientConn {
 	grpcURL := cfg.Get().GrpcEndpoint
-	allParams := append([]string{"Authorization", "Bearer " + cfg.Get().Token}, kv...)
 
 	// Retry policy
 	backoffConfig := backoff.Config{
@@ -49,37 +143,55 @@ func NewFilterService(kv ...string) *FilterService {
 		MaxDelay:   5 * time.Second,
 	}
 
-	// Load system CA certs
-	systemRoots, err := x509.SystemCertPool()
-	if err != nil {
-		log.Fatalf("Failed to load system cert pool: %v", err)
-	}
-	creds := credentials.NewTLS(&tls.Config{
-		RootCAs: systemRoots,
-	})
-
 	// conn, err := grpc.Dial(grpc_url, grpc.WithTransportCredentials(credentials.NewClientTLSFromCert(nil, "")), grpc.WithUnaryInterceptor(authUnaryInterceptor(allParams...)), grpc.WithUserAgent("Logfire-cli"))
 	// conn, err := grpc.Dial(grpc_url, grpc.WithInsecure(), grpc.WithUnaryInterceptor(authUnaryInterceptor(allParams...)), grpc.WithUserAgent("Logfire-cli"))
-	conn, err := grpc.Dial(grpcURL,
-		grpc.WithTransportCredentials(creds),
-		grpc.WithUnaryInterceptor(authUnaryInterceptor(allParams...)),
+	dialOptions := []grpc.DialOption{
+		grpc.WithTransportCredentials(transportCredentials(grpcURL)),
+		grpc.WithUnaryInterceptor(authUnaryInterceptor(cfg, kv...)),
+		grpc.WithStreamInterceptor(authStreamInterceptor(cfg, kv...)),
 		grpc.WithUserAgent("Logfire-cli"),
 		grpc.WithConnectParams(grpc.ConnectParams{
 			Backoff:           backoffConfig,
 			MinConnectTimeout: 10 * time.Second,
 		}),
-	)
+	}
+	dialOptions = append(dialOptions, debug.DialOptions()...)
+
+	conn, err := grpc.Dial(grpcURL, dialOptions...)
 
 	if err != nil {
 		log.Fatalf("Failed to dial server: %v", err)
 	}
+	return conn
+}
 
-	client := pb.NewFilterServiceClient(conn)
+// transportCredentials uses TLS with the system CAs, except for endpoints on the local machine
+// such as `logfire dev server`, which serve plain gRPC.
+func transportCredentials(endpoint string) credentials.TransportCredentials {
+	host, _, err := net.SplitHostPort(endpoint)
+	if err == nil {
+		if ip := net.ParseIP(host); host == "localhost" || (ip != nil && ip.IsLoopback()) {
+			return insecure.NewCredentials()
+		}
+	}
 
-	return &FilterService{
-		conn:   conn,
-		Client: client,
+	// Load system CA certs
+	systemRoots, err := x509.SystemCertPool()
+	if err != nil {
+		log.Fatalf("Failed to load system cert pool: %v", err)
 	}
+	return credentials.NewTLS(&tls.Config{
+		RootCAs: systemRoots,
+	})
+}
+
+// sourceTopicPrefix turns the ID of a source into the SourceID of the gRPC services.
+const sourceTopicPrefix = "source_topic_"
+
+// SourceKey identifies a source by its ID, which unlike its name is unique, whether or not the ID
+// has the topic prefix.
+func SourceKey(sourceID string) string {
+	return strings.TrimPrefix(sourceID, sourceTopicPrefix)
 }
 
 // CreateGrpcSource creates a proper sources to be used in grpc request
@@ -87,7 +199,7 @@ func CreateGrpcSource(sources []models.Source) []*pb.Source {
 	var grpcSources []*pb.Source
 	for _, source := range sources {
 		pbSource := pb.Source{
-			SourceID:   "source_topic_" + source.ID,
+			SourceID:   sourceTopicPrefix + source.ID,
 			SourceName: source.Name,
 			TeamID:     source.TeamID,
 		}
@@ -103,19 +215,22 @@ func (a ByOffset) Len() int           { return len(a) }
 func (a ByOffset) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 func (a ByOffset) Less(i, j int) bool { return a[i].Offset < a[j].Offset }
 
-// AddOffset adds offset to sources based on the last response received.
+// AddOffset adds offset to sources based on the last response received. offset is keyed by
+// source ID, as returned by GetOffsets.
 func AddOffset(sources []*pb.Source, offset map[string]uint64) []*pb.Source {
 	for _, source := range sources {
-		source.StartingOffset = offset[source.SourceName]
+		source.StartingOffset = offset[SourceKey(source.SourceID)]
 	}
 
 	return sources
 }
 
+// GetOffsets advances offsets, keyed by source ID, past records.
 func GetOffsets(offsets map[string]uint64, records []*pb.FilteredRecord) map[string]uint64 {
 	for _, record := range records {
-		if offsets[record.SourceName] == 0 || record.Offset >= offsets[record.SourceName] {
-			offsets[record.SourceName] = record.Offset + 1
+		key := SourceKey(record.SourceID)
+		if offsets[key] == 0 || record.Offset >= offsets[key] {
+			offsets[key] = record.Offset + 1
 		}
 	}
 	return offsets
@@ -5,9 +5,9 @@ import (
 	"log"
 	"time"
 
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/pkg/cmd/sources/models"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	pb "github.com/logfire-sh/cli/services/flink-service"
 )
 
@@ -24,7 +24,7 @@ func GetLog(config config.Config, token, endpoint, teamId, accountId, sourceId,
 		BatchSize:      1,
 	}
 
-	source, err := APICalls.GetSource(token, endpoint, teamId, sourceId)
+	source, err := api.NewClient(nil, endpoint, token).GetSource(teamId, sourceId)
 	if err != nil {
 		log.Println("Error getting source:", err)
 		stop <- err
@@ -35,7 +35,9 @@ func GetLog(config config.Config, token, endpoint, teamId, accountId, sourceId,
 	pbSources := CreateGrpcSource(sources)
 	request.Sources = pbSources
 
-	filterService := NewFilterService()
+	ingestClient := api.NewClient(nil, config.Get().GrpcIngestion, sourceToken)
+
+	filterService := NewFilterService(config)
 	defer filterService.CloseConnection()
 
 	for {
@@ -44,7 +46,7 @@ func GetLog(config config.Config, token, endpoint, teamId, accountId, sourceId,
 			stop <- nil
 			return nil
 		default:
-			_, err := APICalls.LogIngestFlow(config.Get().GrpcIngestion, sourceToken)
+			err := ingestClient.SendTestLog()
 			if err != nil {
 				log.Printf("Error: %s", err.Error())
 				continue
@@ -6,9 +6,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/config"
 	"github.com/logfire-sh/cli/pkg/cmd/sources/models"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	pb "github.com/logfire-sh/cli/services/flink-service"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -25,7 +25,7 @@ func WaitForLog(cfg config.Config, id uuid.UUID, teamId string, accountId string
 
 	var sources []models.Source
 
-	source, err := APICalls.GetSource(cfg.Get().Token, cfg.Get().EndPoint, teamId, sourceId)
+	source, err := api.NewClientFromConfig(nil, cfg).GetSource(teamId, sourceId)
 	if err != nil {
 		return
 	}
@@ -39,7 +39,7 @@ func WaitForLog(cfg config.Config, id uuid.UUID, teamId string, accountId string
 
 	request.SearchQueries = []string{id.String()}
 
-	filterService := NewFilterService("Diagnostic", "True")
+	filterService := NewFilterService(cfg, "Diagnostic", "True")
 	defer filterService.CloseConnection()
 
 	for {
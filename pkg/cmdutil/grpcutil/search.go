@@ -0,0 +1,176 @@
+package grpcutil
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	pb "github.com/logfire-sh/cli/services/flink-service"
+)
+
+// searchSource is the paging state of one source of SearchRecords.
+type searchSource struct {
+	source    *pb.Source
+	buffer    []*pb.FilteredRecord
+	exhausted bool
+}
+
+// SearchRecords pages through the records matching request and returns once every source is
+// exhausted, limit records were handled (0 means no limit), ctx is done or handle returns an error.
+//
+// Records are handed to handle oldest first, or newest first when reverse is set. Every source is
+// paged on its own with GetFilteredData: forwards by advancing its StartingOffset, backwards by
+// lowering its EndingOffset. The pages of all sources are merged by time, so a batch only holds
+// records that no later page can precede.
+func (fs *FilterService) SearchRecords(ctx context.Context, request *pb.FilterRequest, limit int, reverse bool, handle RecordHandler) error {
+	request.IsScrollDown = !reverse
+
+	var sources []*searchSource
+	for _, source := range request.Sources {
+		sources = append(sources, &searchSource{source: source})
+	}
+	defer func() {
+		request.Sources = nil
+		for _, s := range sources {
+			request.Sources = append(request.Sources, s.source)
+		}
+	}()
+
+	handled := 0
+	for {
+		if err := fs.fetchPages(ctx, request, sources, reverse); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		var batch []*pb.FilteredRecord
+		for limit == 0 || handled+len(batch) < limit {
+			next := nextRecord(sources, reverse)
+			if next == nil {
+				break
+			}
+			batch = append(batch, next.buffer[0])
+			next.buffer = next.buffer[1:]
+		}
+
+		if len(batch) > 0 {
+			handled += len(batch)
+			if err := handle(batch); err != nil {
+				return err
+			}
+		}
+
+		if limit > 0 && handled >= limit {
+			return nil
+		}
+		if searchDone(sources) {
+			return nil
+		}
+	}
+}
+
+// fetchPages reads the next page of every source whose buffer ran empty, with a single call.
+// Sources that return no new record are exhausted.
+func (fs *FilterService) fetchPages(ctx context.Context, request *pb.FilterRequest, sources []*searchSource, reverse bool) error {
+	request.Sources = nil
+	for _, s := range sources {
+		if !s.exhausted && len(s.buffer) == 0 {
+			request.Sources = append(request.Sources, s.source)
+		}
+	}
+	if len(request.Sources) == 0 {
+		return nil
+	}
+
+	response, err := fs.Client.GetFilteredData(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range sources {
+		if s.exhausted || len(s.buffer) > 0 {
+			continue
+		}
+
+		for _, record := range response.Records {
+			if SourceKey(record.SourceID) != SourceKey(s.source.SourceID) {
+				continue
+			}
+			// Servers that ignore the offsets would otherwise repeat the same page forever.
+			if !reverse && s.source.StartingOffset > 0 && record.Offset < s.source.StartingOffset ||
+				reverse && s.source.EndingOffset > 0 && record.Offset >= s.source.EndingOffset {
+				continue
+			}
+			s.buffer = append(s.buffer, record)
+		}
+
+		if len(s.buffer) == 0 {
+			s.exhausted = true
+			continue
+		}
+
+		sort.Sort(ByOffset(s.buffer))
+		if reverse {
+			for i, j := 0, len(s.buffer)-1; i < j; i, j = i+1, j-1 {
+				s.buffer[i], s.buffer[j] = s.buffer[j], s.buffer[i]
+			}
+			// Offsets are exclusive at the end, so the source is exhausted once offset 0 was read.
+			s.source.EndingOffset = s.buffer[len(s.buffer)-1].Offset
+			s.exhausted = s.source.EndingOffset == 0
+		} else {
+			s.source.StartingOffset = s.buffer[len(s.buffer)-1].Offset + 1
+		}
+	}
+	return nil
+}
+
+// nextRecord returns the source holding the next record in time order, or nil when a source
+// that is not exhausted needs another page first.
+func nextRecord(sources []*searchSource, reverse bool) *searchSource {
+	var next *searchSource
+	for _, s := range sources {
+		if len(s.buffer) == 0 {
+			if !s.exhausted {
+				return nil
+			}
+			continue
+		}
+		if next == nil || !reverse && recordBefore(s.buffer[0], next.buffer[0]) || reverse && recordBefore(next.buffer[0], s.buffer[0]) {
+			next = s
+		}
+	}
+	return next
+}
+
+func searchDone(sources []*searchSource) bool {
+	for _, s := range sources {
+		if !s.exhausted || len(s.buffer) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// recordTimeLayouts are the formats of FilteredRecord.Dt.
+var recordTimeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02 15:04:05"}
+
+// RecordTime parses the Dt of record, which servers send in one of recordTimeLayouts.
+func RecordTime(record *pb.FilteredRecord) (time.Time, bool) {
+	for _, layout := range recordTimeLayouts {
+		if t, err := time.Parse(layout, record.Dt); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func recordBefore(a, b *pb.FilteredRecord) bool {
+	ta, okA := RecordTime(a)
+	tb, okB := RecordTime(b)
+	if okA && okB {
+		return ta.Before(tb)
+	}
+	return a.Dt < b.Dt
+}
@@ -0,0 +1,164 @@
+package grpcutil
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	pb "github.com/logfire-sh/cli/services/flink-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// pagingFilterClient pages the records of every source like the Logfire API: the first or the
+// last batch after StartingOffset and before EndingOffset.
+type pagingFilterClient struct {
+	pb.FilterServiceClient
+
+	records map[string][]*pb.FilteredRecord
+	calls   int
+}
+
+func (c *pagingFilterClient) GetFilteredData(ctx context.Context, in *pb.FilterRequest, opts ...grpc.CallOption) (*pb.FilteredRecords, error) {
+	c.calls++
+
+	var result []*pb.FilteredRecord
+	for _, source := range in.Sources {
+		var matched []*pb.FilteredRecord
+		for _, record := range c.records[source.SourceID] {
+			if record.Offset >= source.StartingOffset && (source.EndingOffset == 0 || record.Offset < source.EndingOffset) {
+				matched = append(matched, record)
+			}
+		}
+		if len(matched) > int(in.BatchSize) {
+			if in.IsScrollDown {
+				matched = matched[:in.BatchSize]
+			} else {
+				matched = matched[len(matched)-int(in.BatchSize):]
+			}
+		}
+		result = append(result, matched...)
+	}
+	return &pb.FilteredRecords{Records: result}, nil
+}
+
+// records returns count records of source, one every step starting at start.
+func records(source string, count int, start time.Time, step time.Duration) []*pb.FilteredRecord {
+	var result []*pb.FilteredRecord
+	for i := 0; i < count; i++ {
+		result = append(result, &pb.FilteredRecord{
+			SourceID: source,
+			Offset:   uint64(i),
+			Dt:       start.Add(time.Duration(i) * step).Format(time.RFC3339Nano),
+			Message:  fmt.Sprintf("%s-%d", source, i),
+		})
+	}
+	return result
+}
+
+func TestSearchRecords(t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	// api logs every second and worker every 3 seconds, so their pages cover different time spans.
+	all := map[string][]*pb.FilteredRecord{
+		"api":    records("api", 7, start, time.Second),
+		"worker": records("worker", 3, start.Add(500*time.Millisecond), 3*time.Second),
+	}
+
+	tests := []struct {
+		name    string
+		limit   int
+		reverse bool
+		want    []string
+	}{
+		{
+			name: "oldest first",
+			want: []string{"api-0", "worker-0", "api-1", "api-2", "api-3", "worker-1", "api-4", "api-5", "api-6", "worker-2"},
+		},
+		{
+			name:    "newest first",
+			reverse: true,
+			want:    []string{"worker-2", "api-6", "api-5", "api-4", "worker-1", "api-3", "api-2", "api-1", "worker-0", "api-0"},
+		},
+		{
+			name:  "limit",
+			limit: 4,
+			want:  []string{"api-0", "worker-0", "api-1", "api-2"},
+		},
+		{
+			name:    "reverse limit",
+			limit:   3,
+			reverse: true,
+			want:    []string{"worker-2", "api-6", "api-5"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := &FilterService{Client: &pagingFilterClient{records: all}}
+			request := &pb.FilterRequest{
+				BatchSize: 2,
+				Sources:   []*pb.Source{{SourceID: "api"}, {SourceID: "worker"}},
+			}
+
+			var got []string
+			err := fs.SearchRecords(context.Background(), request, tt.limit, tt.reverse, func(records []*pb.FilteredRecord) error {
+				for _, record := range records {
+					got = append(got, record.Message)
+				}
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Len(t, request.Sources, 2)
+		})
+	}
+}
+
+func TestSearchRecordsIgnoredOffsets(t *testing.T) {
+	// A server that ignores the offsets returns the same page again, which must end the search.
+	client := &fakeFilterClient{batches: [][]*pb.FilteredRecord{
+		{{SourceID: "api", Offset: 0}, {SourceID: "api", Offset: 1}},
+		{{SourceID: "api", Offset: 0}, {SourceID: "api", Offset: 1}},
+	}}
+	fs := &FilterService{Client: client}
+
+	count := 0
+	err := fs.SearchRecords(context.Background(), &pb.FilterRequest{Sources: []*pb.Source{{SourceID: "api"}}}, 0, false, func(records []*pb.FilteredRecord) error {
+		count += len(records)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Len(t, client.requests, 2)
+}
+
+func TestSearchRecordsSameNamedSources(t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	all := map[string][]*pb.FilteredRecord{
+		"1": records("1", 2, start, time.Second),
+		"2": records("2", 2, start.Add(500*time.Millisecond), time.Second),
+	}
+	for _, batch := range all {
+		for _, record := range batch {
+			record.SourceName = "api"
+		}
+	}
+
+	fs := &FilterService{Client: &pagingFilterClient{records: all}}
+	request := &pb.FilterRequest{
+		BatchSize: 1,
+		Sources:   []*pb.Source{{SourceID: "1", SourceName: "api"}, {SourceID: "2", SourceName: "api"}},
+	}
+
+	var got []string
+	err := fs.SearchRecords(context.Background(), request, 0, false, func(records []*pb.FilteredRecord) error {
+		for _, record := range records {
+			got = append(got, record.Message)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1-0", "2-0", "1-1", "2-1"}, got)
+}
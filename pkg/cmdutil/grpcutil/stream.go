@@ -0,0 +1,191 @@
+package grpcutil
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"time"
+
+	pb "github.com/logfire-sh/cli/services/flink-service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	pollInterval     = 500 * time.Millisecond
+	reconnectDelay   = 1 * time.Second
+	maxReconnectWait = 30 * time.Second
+)
+
+// RecordHandler receives every batch of records delivered by StreamRecords, sorted by offset.
+// Returning an error stops the stream and is passed back to the caller.
+type RecordHandler func(records []*pb.FilteredRecord) error
+
+// StreamRecords follows the logs matching request until ctx is done or handle returns an error.
+// It uses the server push stream (GetStreamData) and falls back to polling GetFilteredData
+// when the server rejects streaming. Temporary failures reconnect with backoff, while errors
+// such as an unknown source or a denied permission are returned. The source offsets of request
+// are advanced after every batch so that a reconnect resumes where the previous stream stopped.
+// Sources with a StartingOffset, such as those resumed from a Checkpoint, start from it.
+func (fs *FilterService) StreamRecords(ctx context.Context, request *pb.FilterRequest, handle RecordHandler) error {
+	offsets := make(map[string]uint64)
+	for _, source := range request.Sources {
+		if source.StartingOffset > 0 {
+			offsets[SourceKey(source.SourceID)] = source.StartingOffset
+		}
+	}
+	wait := reconnectDelay
+	// reauthorized is set once a stream is reopened after a token refresh, until records arrive.
+	reauthorized := false
+
+	for {
+		received, err := fs.stream(ctx, request, offsets, handle)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		var handlerErr *handlerError
+		if errors.As(err, &handlerErr) {
+			return handlerErr.err
+		}
+
+		if status.Code(err) == codes.Unimplemented {
+			return fs.poll(ctx, request, offsets, handle)
+		}
+
+		// The token expired while streaming and was refreshed: reconnect at once, unless the
+		// stream opened with the new token was rejected before delivering anything.
+		var refreshed *tokenRefreshedError
+		if errors.As(err, &refreshed) {
+			if !received && reauthorized {
+				return refreshed.err
+			}
+			reauthorized = true
+			if fs.OnReconnect != nil {
+				fs.OnReconnect(err)
+			}
+			continue
+		}
+		if received {
+			reauthorized = false
+		}
+
+		if err != nil && !temporary(err) {
+			return err
+		}
+
+		if received {
+			wait = reconnectDelay
+		}
+		if fs.OnReconnect != nil {
+			fs.OnReconnect(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+
+		wait *= 2
+		if wait > maxReconnectWait {
+			wait = maxReconnectWait
+		}
+	}
+}
+
+// temporary reports whether err is a failure of the connection or of the server that a later
+// call may not meet, rather than a rejection of the request.
+func temporary(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted, codes.Internal:
+		return true
+	}
+	return false
+}
+
+// handlerError marks errors returned by a RecordHandler so they are not mistaken for transport errors.
+type handlerError struct {
+	err error
+}
+
+func (e *handlerError) Error() string { return e.err.Error() }
+
+// stream reads from a single GetStreamData call until it fails or ends.
+// received reports whether at least one message arrived before the stream stopped.
+func (fs *FilterService) stream(ctx context.Context, request *pb.FilterRequest, offsets map[string]uint64, handle RecordHandler) (received bool, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := fs.Client.GetStreamData(ctx, request)
+	if err != nil {
+		return false, err
+	}
+	// The stream opens on a ready connection, while its headers only arrive with the first record.
+	if fs.OnConnect != nil {
+		fs.OnConnect()
+	}
+
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			return received, nil
+		}
+		if err != nil {
+			return received, err
+		}
+		received = true
+
+		if err := deliver(request, offsets, response.Records, handle); err != nil {
+			return received, &handlerError{err}
+		}
+	}
+}
+
+// poll repeatedly calls GetFilteredData for servers that do not support GetStreamData.
+func (fs *FilterService) poll(ctx context.Context, request *pb.FilterRequest, offsets map[string]uint64, handle RecordHandler) error {
+	connected := false
+	for {
+		response, err := fs.Client.GetFilteredData(ctx, request)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil && !temporary(err) {
+			return err
+		}
+
+		switch {
+		case err != nil && connected && fs.OnReconnect != nil:
+			fs.OnReconnect(err)
+		case err == nil && !connected && fs.OnConnect != nil:
+			fs.OnConnect()
+		}
+		connected = err == nil
+
+		if err == nil {
+			if err := deliver(request, offsets, response.Records, handle); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// deliver sorts records, advances the source offsets of request and hands the batch to handle.
+func deliver(request *pb.FilterRequest, offsets map[string]uint64, records []*pb.FilteredRecord, handle RecordHandler) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	sort.Sort(ByOffset(records))
+	offsets = GetOffsets(offsets, records)
+	request.Sources = AddOffset(request.Sources, offsets)
+
+	return handle(records)
+}
@@ -0,0 +1,354 @@
+package grpcutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/logfire-sh/cli/internal/config"
+	pb "github.com/logfire-sh/cli/services/flink-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type fakeFilterClient struct {
+	pb.FilterServiceClient
+
+	streamErr error
+	// pollErrs are returned by the first calls to GetFilteredData, nil ones returning a batch.
+	pollErrs []error
+	batches  [][]*pb.FilteredRecord
+	requests []*pb.FilterRequest
+}
+
+func (f *fakeFilterClient) GetStreamData(ctx context.Context, in *pb.FilterRequest, opts ...grpc.CallOption) (pb.FilterService_GetStreamDataClient, error) {
+	return nil, f.streamErr
+}
+
+func (f *fakeFilterClient) GetFilteredData(ctx context.Context, in *pb.FilterRequest, opts ...grpc.CallOption) (*pb.FilteredRecords, error) {
+	f.requests = append(f.requests, &pb.FilterRequest{Sources: []*pb.Source{{
+		SourceID:       in.Sources[0].SourceID,
+		StartingOffset: in.Sources[0].StartingOffset,
+	}}})
+
+	if len(f.pollErrs) > 0 {
+		err := f.pollErrs[0]
+		f.pollErrs = f.pollErrs[1:]
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(f.batches) == 0 {
+		return &pb.FilteredRecords{}, nil
+	}
+	batch := f.batches[0]
+	f.batches = f.batches[1:]
+	return &pb.FilteredRecords{Records: batch}, nil
+}
+
+func TestStreamRecordsFallsBackToPolling(t *testing.T) {
+	client := &fakeFilterClient{
+		streamErr: status.Error(codes.Unimplemented, "streaming disabled"),
+		batches: [][]*pb.FilteredRecord{
+			{{SourceID: "api", Offset: 4}, {SourceID: "api", Offset: 3}},
+			{{SourceID: "api", Offset: 5}},
+		},
+	}
+	fs := &FilterService{Client: client}
+	request := &pb.FilterRequest{Sources: []*pb.Source{{SourceID: "api"}}}
+
+	stop := errors.New("stop")
+	var got []uint64
+	err := fs.StreamRecords(context.Background(), request, func(records []*pb.FilteredRecord) error {
+		for _, record := range records {
+			got = append(got, record.Offset)
+		}
+		if len(got) == 3 {
+			return stop
+		}
+		return nil
+	})
+
+	assert.Equal(t, stop, err)
+	assert.Equal(t, []uint64{3, 4, 5}, got)
+	assert.Equal(t, uint64(0), client.requests[0].Sources[0].StartingOffset)
+	assert.Equal(t, uint64(5), client.requests[1].Sources[0].StartingOffset)
+	assert.Equal(t, uint64(6), request.Sources[0].StartingOffset)
+}
+
+func TestStreamRecordsResumesFromStartingOffset(t *testing.T) {
+	client := &fakeFilterClient{
+		streamErr: status.Error(codes.Unimplemented, "streaming disabled"),
+		batches:   [][]*pb.FilteredRecord{{{SourceID: "web", Offset: 2}}},
+	}
+	fs := &FilterService{Client: client}
+	request := &pb.FilterRequest{Sources: []*pb.Source{{SourceID: "api", StartingOffset: 40}, {SourceID: "web"}}}
+
+	stop := errors.New("stop")
+	err := fs.StreamRecords(context.Background(), request, func(records []*pb.FilteredRecord) error {
+		return stop
+	})
+
+	assert.Equal(t, stop, err)
+	assert.Equal(t, uint64(40), request.Sources[0].StartingOffset, "sources without records keep their offset")
+	assert.Equal(t, uint64(3), request.Sources[1].StartingOffset)
+}
+
+func TestStreamRecordsStopsOnCancel(t *testing.T) {
+	client := &fakeFilterClient{streamErr: status.Error(codes.Unavailable, "connection refused")}
+	fs := &FilterService{Client: client}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := fs.StreamRecords(ctx, &pb.FilterRequest{}, func(records []*pb.FilteredRecord) error {
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestStreamRecordsReportsReconnects(t *testing.T) {
+	client := &fakeFilterClient{streamErr: status.Error(codes.Unavailable, "connection refused")}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var reconnects []error
+	fs := &FilterService{Client: client, OnReconnect: func(err error) {
+		reconnects = append(reconnects, err)
+		cancel()
+	}}
+
+	err := fs.StreamRecords(ctx, &pb.FilterRequest{}, func(records []*pb.FilteredRecord) error {
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []error{client.streamErr}, reconnects)
+}
+
+func TestStreamRecordsReportsPollingState(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+	client := &fakeFilterClient{
+		streamErr: status.Error(codes.Unimplemented, "streaming disabled"),
+		pollErrs:  []error{nil, unavailable, unavailable, nil},
+		batches:   [][]*pb.FilteredRecord{{}, {{SourceID: "api", Offset: 1}}},
+	}
+
+	var events []string
+	fs := &FilterService{
+		Client:      client,
+		OnConnect:   func() { events = append(events, "connect") },
+		OnReconnect: func(err error) { events = append(events, "reconnect: "+status.Convert(err).Message()) },
+	}
+
+	stop := errors.New("stop")
+	err := fs.StreamRecords(context.Background(), &pb.FilterRequest{Sources: []*pb.Source{{SourceID: "api"}}}, func(records []*pb.FilteredRecord) error {
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, []string{"connect", "reconnect: connection refused", "connect"}, events)
+}
+
+func TestStreamRecordsReturnsPermanentErrors(t *testing.T) {
+	denied := status.Error(codes.PermissionDenied, "not a member of the team")
+	tests := []struct {
+		name   string
+		client *fakeFilterClient
+	}{
+		{
+			name:   "stream",
+			client: &fakeFilterClient{streamErr: denied},
+		},
+		{
+			name: "poll",
+			client: &fakeFilterClient{
+				streamErr: status.Error(codes.Unimplemented, "streaming disabled"),
+				pollErrs:  []error{nil, status.Error(codes.Unavailable, "connection refused"), denied},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reconnects []error
+			fs := &FilterService{Client: tt.client, OnReconnect: func(err error) {
+				reconnects = append(reconnects, err)
+			}}
+
+			err := fs.StreamRecords(context.Background(), &pb.FilterRequest{Sources: []*pb.Source{{SourceID: "api"}}}, func(records []*pb.FilteredRecord) error {
+				return nil
+			})
+			assert.Equal(t, denied, err)
+			assert.NotContains(t, reconnects, denied)
+		})
+	}
+}
+
+func TestStreamRecordsKeepsOffsetsOfSameNamedSources(t *testing.T) {
+	client := &fakeFilterClient{
+		streamErr: status.Error(codes.Unimplemented, "streaming disabled"),
+		batches: [][]*pb.FilteredRecord{{
+			{SourceID: sourceTopicPrefix + "1", SourceName: "api", Offset: 40},
+			{SourceID: sourceTopicPrefix + "2", SourceName: "api", Offset: 7},
+		}},
+	}
+	fs := &FilterService{Client: client}
+	request := &pb.FilterRequest{Sources: []*pb.Source{
+		{SourceID: sourceTopicPrefix + "1", SourceName: "api"},
+		{SourceID: sourceTopicPrefix + "2", SourceName: "api"},
+	}}
+
+	stop := errors.New("stop")
+	err := fs.StreamRecords(context.Background(), request, func(records []*pb.FilteredRecord) error {
+		return stop
+	})
+
+	assert.Equal(t, stop, err)
+	assert.Equal(t, uint64(41), request.Sources[0].StartingOffset)
+	assert.Equal(t, uint64(8), request.Sources[1].StartingOffset)
+}
+
+// scriptedStream delivers its batches, then fails with err.
+type scriptedStream struct {
+	grpc.ClientStream
+	batches [][]*pb.FilteredRecord
+	err     error
+}
+
+func (s *scriptedStream) RecvMsg(m interface{}) error {
+	if len(s.batches) == 0 {
+		return s.err
+	}
+	m.(*pb.FilteredRecords).Records = s.batches[0]
+	s.batches = s.batches[1:]
+	return nil
+}
+
+// recvStream is the GetStreamData client of a stream returned by authStreamInterceptor.
+type recvStream struct {
+	grpc.ClientStream
+}
+
+func (s recvStream) Recv() (*pb.FilteredRecords, error) {
+	response := &pb.FilteredRecords{}
+	return response, s.RecvMsg(response)
+}
+
+// interceptedFilterClient opens its streams in turn through authStreamInterceptor, recording the
+// token each one is opened with.
+type interceptedFilterClient struct {
+	pb.FilterServiceClient
+	cfg     config.Config
+	streams []*scriptedStream
+	tokens  []string
+}
+
+func (c *interceptedFilterClient) GetStreamData(ctx context.Context, in *pb.FilterRequest, opts ...grpc.CallOption) (pb.FilterService_GetStreamDataClient, error) {
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		c.tokens = append(c.tokens, md.Get("Authorization")[0])
+		stream := c.streams[0]
+		c.streams = c.streams[1:]
+		return stream, nil
+	}
+	stream, err := authStreamInterceptor(c.cfg)(ctx, &grpc.StreamDesc{ServerStreams: true}, nil, "GetStreamData", streamer)
+	if err != nil {
+		return nil, err
+	}
+	return recvStream{stream}, nil
+}
+
+func TestStreamRecordsRefreshesExpiredToken(t *testing.T) {
+	expired := status.Error(codes.Unauthenticated, "token expired")
+	stop := errors.New("stop")
+	tests := []struct {
+		name         string
+		refreshToken string
+		streams      []*scriptedStream
+		wantOffsets  []uint64
+		wantTokens   []string
+		wantErr      string
+	}{
+		{
+			name:         "reconnects with the new token",
+			refreshToken: "old-refresh",
+			streams: []*scriptedStream{
+				{batches: [][]*pb.FilteredRecord{{{SourceID: "api", Offset: 1}}}, err: expired},
+				{batches: [][]*pb.FilteredRecord{{{SourceID: "api", Offset: 2}}}, err: expired},
+				{batches: [][]*pb.FilteredRecord{{{SourceID: "api", Offset: 3}}}},
+			},
+			wantOffsets: []uint64{1, 2, 3},
+			wantTokens:  []string{"Bearer old-token", "Bearer new-token-1", "Bearer new-token-2"},
+			wantErr:     "stop",
+		},
+		{
+			name:         "stops when the new token is rejected at once",
+			refreshToken: "old-refresh",
+			streams: []*scriptedStream{
+				{batches: [][]*pb.FilteredRecord{{{SourceID: "api", Offset: 1}}}, err: expired},
+				{err: expired},
+			},
+			wantOffsets: []uint64{1},
+			wantTokens:  []string{"Bearer old-token", "Bearer new-token-1"},
+			wantErr:     "token expired",
+		},
+		{
+			name: "returns the refresh error",
+			streams: []*scriptedStream{
+				{batches: [][]*pb.FilteredRecord{{{SourceID: "api", Offset: 1}}}, err: expired},
+			},
+			wantOffsets: []uint64{1},
+			wantTokens:  []string{"Bearer old-token"},
+			wantErr:     "session expired, please run `logfire login` again",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refreshes := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				refreshes++
+				fmt.Fprintf(w, `{"isSuccessful":true,"bearerToken":{"accessToken":"new-token-%d"}}`, refreshes)
+			}))
+			defer server.Close()
+
+			cfg, err := config.Load(config.Options{Path: filepath.Join(t.TempDir(), ".logfire")})
+			require.NoError(t, err)
+			require.NoError(t, cfg.UpdateConfig(func(c *config.AuthConfig) {
+				c.EndPoint = server.URL + "/"
+				c.Token = "old-token"
+				c.RefreshToken = tt.refreshToken
+			}))
+
+			client := &interceptedFilterClient{cfg: cfg, streams: tt.streams}
+			fs := &FilterService{Client: client}
+			request := &pb.FilterRequest{Sources: []*pb.Source{{SourceID: "api"}}}
+
+			var offsets []uint64
+			err = fs.StreamRecords(context.Background(), request, func(records []*pb.FilteredRecord) error {
+				for _, record := range records {
+					offsets = append(offsets, record.Offset)
+				}
+				if len(offsets) == 3 {
+					return stop
+				}
+				return nil
+			})
+
+			if tt.wantErr == stop.Error() {
+				assert.Equal(t, stop, err)
+			} else {
+				assert.Equal(t, codes.Unauthenticated, status.Code(err))
+				assert.Equal(t, tt.wantErr, status.Convert(err).Message())
+			}
+			assert.Equal(t, tt.wantOffsets, offsets)
+			assert.Equal(t, tt.wantTokens, client.tokens)
+			assert.Equal(t, uint64(tt.wantOffsets[len(tt.wantOffsets)-1]+1), request.Sources[0].StartingOffset)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package grpcutil
+
+import (
+	"regexp"
+
+	pb "github.com/logfire-sh/cli/services/flink-service"
+)
+
+// StopReason tells why a Watch ended a stream.
+type StopReason int
+
+const (
+	StopNone StopReason = iota
+	StopUntil
+	StopFailOn
+	StopMaxRecords
+)
+
+// Watch ends a stream on the first record whose message matches Until or FailOn, or once
+// MaxRecords records were counted, the way WaitForLog waits for its diagnostic record.
+type Watch struct {
+	Until  []*regexp.Regexp
+	FailOn []*regexp.Regexp
+	// MaxRecords is the number of records counted before the stream ends, 0 for no limit.
+	MaxRecords int
+
+	// Matched is the record that matched Until or FailOn.
+	Matched *pb.FilteredRecord
+	counted int
+}
+
+// Cut returns the records of a batch up to the first one matching Until or FailOn, included,
+// and the reason to stop. FailOn wins when a record matches both.
+func (w *Watch) Cut(records []*pb.FilteredRecord) ([]*pb.FilteredRecord, StopReason) {
+	for i, record := range records {
+		reason := StopNone
+		switch {
+		case matchAny(w.FailOn, record.Message):
+			reason = StopFailOn
+		case matchAny(w.Until, record.Message):
+			reason = StopUntil
+		}
+		if reason != StopNone {
+			w.Matched = record
+			return records[:i+1], reason
+		}
+	}
+	return records, StopNone
+}
+
+// Count counts the records of a batch, skipping nil ones, and returns those up to MaxRecords
+// with StopMaxRecords once the limit is reached.
+func (w *Watch) Count(records []*pb.FilteredRecord) ([]*pb.FilteredRecord, StopReason) {
+	if w.MaxRecords == 0 {
+		return records, StopNone
+	}
+
+	for i, record := range records {
+		if record == nil {
+			continue
+		}
+		w.counted++
+		if w.counted == w.MaxRecords {
+			return records[:i+1], StopMaxRecords
+		}
+	}
+	return records, StopNone
+}
+
+func matchAny(regexps []*regexp.Regexp, text string) bool {
+	for _, re := range regexps {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// RewindOffsets lowers the StartingOffset of sources to the first of records, records that were
+// received but not handled, so that a Checkpoint saved afterwards still reads them.
+func RewindOffsets(sources []*pb.Source, records []*pb.FilteredRecord) {
+	for _, source := range sources {
+		for _, record := range records {
+			if record != nil && SourceKey(record.SourceID) == SourceKey(source.SourceID) && record.Offset < source.StartingOffset {
+				source.StartingOffset = record.Offset
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package grpcutil
+
+import (
+	"regexp"
+	"testing"
+
+	pb "github.com/logfire-sh/cli/services/flink-service"
+	"github.com/stretchr/testify/assert"
+)
+
+func watchRecords(messages ...string) []*pb.FilteredRecord {
+	var records []*pb.FilteredRecord
+	for i, message := range messages {
+		records = append(records, &pb.FilteredRecord{SourceID: "api", Offset: uint64(10 + i), Message: message})
+	}
+	return records
+}
+
+func TestWatchCut(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []*pb.FilteredRecord
+		wants   int
+		reason  StopReason
+	}{
+		{name: "no match", records: watchRecords("starting", "loading"), wants: 2, reason: StopNone},
+		{name: "until", records: watchRecords("starting", "ready on :8080", "serving"), wants: 2, reason: StopUntil},
+		{name: "fail on", records: watchRecords("starting", "panic: nil map", "ready"), wants: 2, reason: StopFailOn},
+		{name: "fail on wins", records: watchRecords("panic before ready"), wants: 1, reason: StopFailOn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			watch := &Watch{Until: []*regexp.Regexp{regexp.MustCompile(`ready`)}, FailOn: []*regexp.Regexp{regexp.MustCompile(`^panic`)}}
+			cut, reason := watch.Cut(tt.records)
+			assert.Equal(t, tt.records[:tt.wants], cut)
+			assert.Equal(t, tt.reason, reason)
+			if reason != StopNone {
+				assert.Equal(t, cut[len(cut)-1], watch.Matched)
+			}
+		})
+	}
+}
+
+func TestWatchCount(t *testing.T) {
+	watch := &Watch{MaxRecords: 3}
+
+	records, reason := watch.Count(watchRecords("a", "b"))
+	assert.Len(t, records, 2)
+	assert.Equal(t, StopNone, reason)
+
+	batch := append(watchRecords("c"), nil)
+	batch = append(batch, watchRecords("d")...)
+	records, reason = watch.Count(batch)
+	assert.Equal(t, batch[:1], records)
+	assert.Equal(t, StopMaxRecords, reason)
+
+	unlimited := &Watch{}
+	records, reason = unlimited.Count(watchRecords("a", "b"))
+	assert.Len(t, records, 2)
+	assert.Equal(t, StopNone, reason)
+}
+
+func TestRewindOffsets(t *testing.T) {
+	sources := []*pb.Source{{SourceID: sourceTopicPrefix + "api", StartingOffset: 13}, {SourceID: sourceTopicPrefix + "web", StartingOffset: 5}}
+	RewindOffsets(sources, append(watchRecords("x", "y"), nil))
+
+	assert.Equal(t, uint64(10), sources[0].StartingOffset)
+	assert.Equal(t, uint64(5), sources[1].StartingOffset)
+}
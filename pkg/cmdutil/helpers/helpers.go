@@ -2,13 +2,11 @@ package helpers
 
 import (
 	"fmt"
-	"github.com/logfire-sh/cli/internal/config"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/prompter"
 	"github.com/logfire-sh/cli/pkg/cmd/teams/models"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	"github.com/logfire-sh/cli/pkg/cmdutil/pre_defined_prompters"
 	"github.com/logfire-sh/cli/pkg/iostreams"
-	"net/http"
 )
 
 func StringNotInArray(str string, array []string) bool {
@@ -20,8 +18,8 @@ func StringNotInArray(str string, array []string) bool {
 	return true
 }
 
-func TeamNameToTeamId(client *http.Client, cfg config.Config, io *iostreams.IOStreams, cs *iostreams.ColorScheme, prompter prompter.Prompter, name string) string {
-	teamsList, err := APICalls.ListTeams(client, cfg.Get().Token, cfg.Get().EndPoint)
+func TeamNameToTeamId(client *api.Client, io *iostreams.IOStreams, cs *iostreams.ColorScheme, prompter prompter.Prompter, name string) string {
+	teamsList, err := client.ListTeams()
 	if err != nil {
 		return ""
 	}
@@ -43,7 +41,7 @@ func TeamNameToTeamId(client *http.Client, cfg config.Config, io *iostreams.IOSt
 		return matchingTeams[0].ID
 	} else {
 		fmt.Fprint(io.Out, "You seem to have multiple teams with the same name, please select from the below options")
-		teamId, err := pre_defined_prompters.AskTeamId(client, cfg, io, cs, prompter)
+		teamId, err := pre_defined_prompters.AskTeamId(client, io, cs, prompter)
 		if err != nil {
 			return ""
 		}
@@ -0,0 +1,162 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/logfire-sh/cli/api"
+)
+
+// Sender delivers a batch of records; *api.Client is the one used outside of tests.
+type Sender interface {
+	SendLogs(records interface{}) error
+}
+
+// Batcher groups records into batches and sends each batch with retries. Records are sent
+// in the order they are received, one batch at a time, so a slow endpoint holds back the
+// producers instead of piling records up in memory.
+type Batcher struct {
+	Sender Sender
+
+	// BatchSize is the most records sent in one request.
+	BatchSize int
+	// MaxBatchBytes is the most JSON bytes sent in one request.
+	MaxBatchBytes int
+	// FlushInterval is the longest a record waits for its batch to fill up.
+	FlushInterval time.Duration
+	// MaxAttempts is how often a batch is tried before giving up.
+	MaxAttempts int
+	// RetryDelay is the wait before the first retry; it doubles with every further attempt.
+	RetryDelay time.Duration
+
+	// OnRetry, when set, is told about every failed attempt that will be retried.
+	OnRetry func(err error, attempt int)
+}
+
+// Stats counts what a Batcher sent.
+type Stats struct {
+	Records int
+	Batches int
+}
+
+// NewBatcher returns a Batcher with the defaults of `logfire send`.
+func NewBatcher(sender Sender) *Batcher {
+	return &Batcher{
+		Sender:        sender,
+		BatchSize:     500,
+		MaxBatchBytes: 4 * 1024 * 1024,
+		FlushInterval: time.Second,
+		MaxAttempts:   5,
+		RetryDelay:    time.Second,
+	}
+}
+
+// Run sends the records read from in until in is closed. It stops at the first batch that
+// cannot be delivered and returns what was sent before it.
+func (b *Batcher) Run(in <-chan Record) (Stats, error) {
+	var stats Stats
+	batch := make([]Record, 0, b.BatchSize)
+	size := 0
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := b.send(batch); err != nil {
+			return err
+		}
+		stats.Records += len(batch)
+		stats.Batches++
+		batch = make([]Record, 0, b.BatchSize)
+		size = 0
+		return nil
+	}
+
+	ticker := time.NewTicker(b.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case record, ok := <-in:
+			if !ok {
+				return stats, flush()
+			}
+
+			recordSize := encodedSize(record)
+			if len(batch) > 0 && size+recordSize > b.MaxBatchBytes {
+				if err := flush(); err != nil {
+					return stats, err
+				}
+			}
+			batch = append(batch, record)
+			size += recordSize
+
+			if len(batch) >= b.BatchSize {
+				if err := flush(); err != nil {
+					return stats, err
+				}
+			}
+		case <-ticker.C:
+			if err := flush(); err != nil {
+				return stats, err
+			}
+		}
+	}
+}
+
+func (b *Batcher) send(batch []Record) error {
+	return retry(context.Background(), b.MaxAttempts, b.RetryDelay, b.OnRetry, func() error {
+		return b.Sender.SendLogs(batch)
+	})
+}
+
+// maxRetryDelay caps the exponential backoff between attempts.
+const maxRetryDelay = 30 * time.Second
+
+// retry calls send until it succeeds, fails for good, maxAttempts are used up or ctx is done.
+// A maxAttempts of 0 retries temporary failures without limit.
+func retry(ctx context.Context, maxAttempts int, delay time.Duration, onRetry func(err error, attempt int), send func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := send()
+		if err == nil || (maxAttempts > 0 && attempt >= maxAttempts) || !temporary(err) {
+			return err
+		}
+
+		if onRetry != nil {
+			onRetry(err, attempt)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+}
+
+// temporary reports whether a failed batch may succeed when sent again.
+func temporary(err error) bool {
+	if api.IsConnectionError(err) {
+		return true
+	}
+
+	var apiErr *api.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+func encodedSize(record Record) int {
+	b, err := json.Marshal(record)
+	if err != nil {
+		return 0
+	}
+	return len(b) + 1
+}
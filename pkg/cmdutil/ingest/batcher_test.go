@@ -0,0 +1,116 @@
+package ingest
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/logfire-sh/cli/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSender struct {
+	batches [][]Record
+	errs    []error
+}
+
+func (s *fakeSender) SendLogs(records interface{}) error {
+	if len(s.errs) > 0 {
+		err := s.errs[0]
+		s.errs = s.errs[1:]
+		if err != nil {
+			return err
+		}
+	}
+	s.batches = append(s.batches, records.([]Record))
+	return nil
+}
+
+func records(n int) <-chan Record {
+	in := make(chan Record, n)
+	for i := 0; i < n; i++ {
+		in <- Record{"dt": "2024-05-01T12:00:00Z", "message": "line"}
+	}
+	close(in)
+	return in
+}
+
+func TestBatcherRun(t *testing.T) {
+	tests := []struct {
+		name        string
+		records     int
+		errs        []error
+		wantBatches []int
+		wantErr     bool
+	}{
+		{
+			name:        "splits into batches",
+			records:     7,
+			wantBatches: []int{3, 3, 1},
+		},
+		{
+			name:        "retries temporary failures",
+			records:     2,
+			errs:        []error{&api.ConnectionError{}, &api.APIError{StatusCode: http.StatusServiceUnavailable}},
+			wantBatches: []int{2},
+		},
+		{
+			name:    "gives up on permanent failures",
+			records: 2,
+			errs:    []error{&api.APIError{StatusCode: http.StatusUnauthorized}},
+			wantErr: true,
+		},
+		{
+			name:    "gives up after the last attempt",
+			records: 1,
+			errs:    []error{&api.ConnectionError{}, &api.ConnectionError{}, &api.ConnectionError{}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &fakeSender{errs: tt.errs}
+			batcher := NewBatcher(sender)
+			batcher.BatchSize = 3
+			batcher.MaxAttempts = 3
+			batcher.RetryDelay = time.Millisecond
+
+			stats, err := batcher.Run(records(tt.records))
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, 0, stats.Records)
+				return
+			}
+
+			require.NoError(t, err)
+			var sizes []int
+			for _, batch := range sender.batches {
+				sizes = append(sizes, len(batch))
+			}
+			assert.Equal(t, tt.wantBatches, sizes)
+			assert.Equal(t, Stats{Records: tt.records, Batches: len(tt.wantBatches)}, stats)
+		})
+	}
+}
+
+func TestBatcherFlushesOnInterval(t *testing.T) {
+	sender := &fakeSender{}
+	batcher := NewBatcher(sender)
+	batcher.FlushInterval = 10 * time.Millisecond
+
+	in := make(chan Record)
+	done := make(chan Stats)
+	go func() {
+		stats, _ := batcher.Run(in)
+		done <- stats
+	}()
+
+	in <- Record{"message": "first"}
+	time.Sleep(50 * time.Millisecond)
+	in <- Record{"message": "second"}
+	close(in)
+
+	assert.Equal(t, Stats{Records: 2, Batches: 2}, <-done)
+}
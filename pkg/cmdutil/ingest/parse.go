@@ -0,0 +1,214 @@
+// Package ingest turns local log lines into records for the Logfire ingestion endpoint and sends them in batches.
+package ingest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatAuto   = "auto"
+	FormatText   = "text"
+	FormatJSON   = "json"
+	FormatLogfmt = "logfmt"
+)
+
+var Formats = []string{FormatAuto, FormatText, FormatJSON, FormatLogfmt}
+
+// Record is one log record: a "dt" timestamp, a "message" and any structured fields of the line.
+type Record map[string]interface{}
+
+// DtLayout is the layout of the "dt" field of every record.
+const DtLayout = time.RFC3339Nano
+
+var (
+	timestampKeys = []string{"dt", "timestamp", "time", "ts", "@timestamp"}
+	messageKeys   = []string{"message", "msg", "log"}
+
+	timestampLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999Z07:00", "2006-01-02 15:04:05.999999999", "2006-01-02T15:04:05.999999999"}
+)
+
+// ValidateFormat checks a --format value.
+func ValidateFormat(format string) error {
+	for _, f := range Formats {
+		if format == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid format %q, expected one of: %s", format, strings.Join(Formats, ", "))
+}
+
+// Parse turns a line into a record. Lines that do not match format, and plain text lines,
+// become a record with the whole line as message. now is the timestamp of lines that carry none.
+func Parse(line, format string, now time.Time) Record {
+	var fields map[string]interface{}
+
+	switch format {
+	case FormatJSON:
+		fields = parseJSON(line)
+	case FormatLogfmt:
+		fields = parseLogfmt(line)
+	case FormatAuto:
+		if fields = parseJSON(line); fields == nil {
+			fields = parseLogfmt(line)
+		}
+	}
+
+	if fields == nil {
+		return Record{"dt": now.UTC().Format(DtLayout), "message": line}
+	}
+	return normalize(fields, line, now)
+}
+
+// ParseFields returns the fields of a JSON object or logfmt line, or nil for any other line.
+// Unlike Parse, it keeps every field under its own name.
+func ParseFields(line string) map[string]interface{} {
+	if fields := parseJSON(line); fields != nil {
+		return fields
+	}
+	return parseLogfmt(line)
+}
+
+func parseJSON(line string) map[string]interface{} {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "{") {
+		return nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(trimmed), &fields); err != nil {
+		return nil
+	}
+	return fields
+}
+
+// parseLogfmt reads key=value pairs, where values may be double quoted. A line with anything
+// other than pairs is not logfmt.
+func parseLogfmt(line string) map[string]interface{} {
+	fields := map[string]interface{}{}
+	s := strings.TrimSpace(line)
+
+	for s != "" {
+		eq := strings.IndexByte(s, '=')
+		if eq <= 0 || strings.ContainsAny(s[:eq], " \t\"") {
+			return nil
+		}
+		key := s[:eq]
+		s = s[eq+1:]
+
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			end := closingQuote(s)
+			if end < 0 {
+				return nil
+			}
+			unquoted, err := strconv.Unquote(s[:end+1])
+			if err != nil {
+				return nil
+			}
+			value = unquoted
+			s = s[end+1:]
+			if s != "" && s[0] != ' ' && s[0] != '\t' {
+				return nil
+			}
+		} else {
+			end := strings.IndexAny(s, " \t")
+			if end < 0 {
+				end = len(s)
+			}
+			value = s[:end]
+			s = s[end:]
+		}
+
+		fields[key] = value
+		s = strings.TrimLeft(s, " \t")
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// normalize moves the timestamp and message of fields to "dt" and "message".
+func normalize(fields map[string]interface{}, line string, now time.Time) Record {
+	record := Record(fields)
+
+	dt := now
+	for _, key := range timestampKeys {
+		if t, ok := parseTimestamp(record[key]); ok {
+			dt = t
+			delete(record, key)
+			break
+		}
+	}
+	record["dt"] = dt.UTC().Format(DtLayout)
+
+	message, found := "", false
+	for _, key := range messageKeys {
+		if value, ok := record[key].(string); ok {
+			message, found = value, true
+			delete(record, key)
+			break
+		}
+	}
+	if !found {
+		message = line
+	}
+	record["message"] = message
+
+	return record
+}
+
+// parseTimestamp accepts RFC 3339 and similar strings and Unix times in seconds, milliseconds,
+// microseconds or nanoseconds.
+func parseTimestamp(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case string:
+		for _, layout := range timestampLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, true
+			}
+		}
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return unixTime(f)
+		}
+	case float64:
+		return unixTime(v)
+	}
+	return time.Time{}, false
+}
+
+func unixTime(f float64) (time.Time, bool) {
+	if f <= 0 {
+		return time.Time{}, false
+	}
+
+	switch {
+	case f < 1e11:
+		sec, frac := math.Modf(f)
+		return time.Unix(int64(sec), int64(frac*1e9)), true
+	case f < 1e14:
+		return time.UnixMilli(int64(f)), true
+	case f < 1e17:
+		return time.UnixMicro(int64(f)), true
+	default:
+		return time.Unix(0, int64(f)), true
+	}
+}
@@ -0,0 +1,92 @@
+package ingest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	nowDt := "2024-05-01T12:00:00Z"
+
+	tests := []struct {
+		name   string
+		line   string
+		format string
+		want   Record
+	}{
+		{
+			name:   "plain text",
+			line:   "GET /health 200",
+			format: FormatAuto,
+			want:   Record{"dt": nowDt, "message": "GET /health 200"},
+		},
+		{
+			name:   "json with timestamp and msg",
+			line:   `{"time":"2024-04-30T08:15:00.5+02:00","msg":"started","level":"info"}`,
+			format: FormatAuto,
+			want:   Record{"dt": "2024-04-30T06:15:00.5Z", "message": "started", "level": "info"},
+		},
+		{
+			name:   "json with epoch milliseconds",
+			line:   `{"ts":1714550400000,"message":"tick"}`,
+			format: FormatJSON,
+			want:   Record{"dt": "2024-05-01T08:00:00Z", "message": "tick"},
+		},
+		{
+			name:   "json without message keeps the line",
+			line:   `{"status":200}`,
+			format: FormatJSON,
+			want:   Record{"dt": nowDt, "message": `{"status":200}`, "status": float64(200)},
+		},
+		{
+			name:   "invalid json is text",
+			line:   `{"status":`,
+			format: FormatJSON,
+			want:   Record{"dt": nowDt, "message": `{"status":`},
+		},
+		{
+			name:   "logfmt",
+			line:   `level=warn msg="disk \"data\" almost full" ts=2024-05-01T10:00:00Z used=91%`,
+			format: FormatAuto,
+			want:   Record{"dt": "2024-05-01T10:00:00Z", "message": `disk "data" almost full`, "level": "warn", "used": "91%"},
+		},
+		{
+			name:   "text with an equals sign is not logfmt",
+			line:   "retrying request id=42",
+			format: FormatAuto,
+			want:   Record{"dt": nowDt, "message": "retrying request id=42"},
+		},
+		{
+			name:   "text format ignores structure",
+			line:   `{"msg":"raw"}`,
+			format: FormatText,
+			want:   Record{"dt": nowDt, "message": `{"msg":"raw"}`},
+		},
+		{
+			name:   "unparseable timestamp is kept as a field",
+			line:   `{"time":"yesterday","msg":"late"}`,
+			format: FormatJSON,
+			want:   Record{"dt": nowDt, "message": "late", "time": "yesterday"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Parse(tt.line, tt.format, now))
+		})
+	}
+}
+
+func TestValidateFormat(t *testing.T) {
+	assert.NoError(t, ValidateFormat(FormatLogfmt))
+	assert.Error(t, ValidateFormat("xml"))
+}
+
+func TestParseFields(t *testing.T) {
+	assert.Equal(t, map[string]interface{}{"status": float64(200), "msg": "ok"}, ParseFields(`{"status":200,"msg":"ok"}`))
+	assert.Equal(t, map[string]interface{}{"status": "200", "msg": "not found"}, ParseFields(`status=200 msg="not found"`))
+	assert.Nil(t, ParseFields("GET /health 200"))
+}
@@ -0,0 +1,164 @@
+package ingest
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+// MaxLineSize caps the length of a line; anything beyond it is dropped so a runaway line cannot exhaust memory.
+const MaxLineSize = 256 * 1024
+
+// pollInterval is how often a followed file is checked for new data.
+var pollInterval = 250 * time.Millisecond
+
+// lineReader splits a stream into lines and keeps an unterminated last line until more data arrives.
+type lineReader struct {
+	r       *bufio.Reader
+	partial []byte
+	// read counts the bytes consumed from r since the last reset, line endings and the dropped
+	// rest of overlong lines included.
+	read int64
+}
+
+func newLineReader(r io.Reader) *lineReader {
+	return &lineReader{r: bufio.NewReaderSize(r, 64*1024)}
+}
+
+// next returns the next complete line without its line ending. At the end of the stream it returns
+// io.EOF and keeps the unterminated rest for the next call.
+func (l *lineReader) next() (string, error) {
+	for {
+		chunk, err := l.r.ReadSlice('\n')
+		l.read += int64(len(chunk))
+		if room := MaxLineSize - len(l.partial); room > 0 {
+			if len(chunk) > room {
+				l.partial = append(l.partial, chunk[:room]...)
+			} else {
+				l.partial = append(l.partial, chunk...)
+			}
+		}
+
+		switch {
+		case err == nil || (len(chunk) > 0 && chunk[len(chunk)-1] == '\n'):
+			line := trimEOL(l.partial)
+			l.partial = l.partial[:0]
+			return line, nil
+		case errors.Is(err, bufio.ErrBufferFull):
+			continue
+		default:
+			return "", err
+		}
+	}
+}
+
+// rest returns and clears the unterminated last line.
+func (l *lineReader) rest() string {
+	line := trimEOL(l.partial)
+	l.partial = l.partial[:0]
+	return line
+}
+
+func (l *lineReader) reset(r io.Reader) {
+	l.r.Reset(r)
+	l.partial = l.partial[:0]
+	l.read = 0
+}
+
+func trimEOL(b []byte) string {
+	for len(b) > 0 && (b[len(b)-1] == '\n' || b[len(b)-1] == '\r') {
+		b = b[:len(b)-1]
+	}
+	return string(b)
+}
+
+// ReadLines sends every line of r to out until r ends or ctx is done.
+func ReadLines(ctx context.Context, r io.Reader, out chan<- string) error {
+	lines := newLineReader(r)
+	for {
+		line, err := lines.next()
+		if errors.Is(err, io.EOF) {
+			if rest := lines.rest(); rest != "" {
+				return send(ctx, out, rest)
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := send(ctx, out, line); err != nil {
+			return nil
+		}
+	}
+}
+
+// FollowFile sends the lines of the file at path to out and keeps waiting for new ones, like tail -F.
+// It starts over when the file is truncated and reopens it when it is replaced, as log rotation does.
+// Only lines written after the call are sent when fromEnd is set.
+func FollowFile(ctx context.Context, path string, fromEnd bool, out chan<- string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { file.Close() }()
+
+	// start is where the reader started in the file, which it has read up to start+lines.read.
+	var start int64
+	if fromEnd {
+		if start, err = file.Seek(0, io.SeekEnd); err != nil {
+			return err
+		}
+	}
+
+	lines := newLineReader(file)
+	for {
+		line, err := lines.next()
+		if err == nil {
+			if send(ctx, out, line) != nil {
+				return nil
+			}
+			continue
+		}
+		if !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(pollInterval):
+		}
+
+		info, statErr := os.Stat(path)
+		current, currentErr := file.Stat()
+		switch {
+		case statErr != nil || currentErr != nil:
+			// The file is being rotated; keep reading the old one until the new one shows up.
+		case !os.SameFile(info, current):
+			if next, openErr := os.Open(path); openErr == nil {
+				file.Close()
+				file = next
+				start = 0
+				lines.reset(file)
+			}
+		case info.Size() < start+lines.read:
+			if _, err := file.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			start = 0
+			lines.reset(file)
+		}
+	}
+}
+
+func send(ctx context.Context, out chan<- string, line string) error {
+	select {
+	case out <- line:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
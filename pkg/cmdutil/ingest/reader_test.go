@@ -0,0 +1,86 @@
+package ingest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadLines(t *testing.T) {
+	out := make(chan string, 10)
+	long := strings.Repeat("x", MaxLineSize+10)
+	err := ReadLines(context.Background(), strings.NewReader("one\r\ntwo\n"+long+"\nlast"), out)
+	require.NoError(t, err)
+	close(out)
+
+	var lines []string
+	for line := range out {
+		lines = append(lines, line)
+	}
+	assert.Equal(t, []string{"one", "two", long[:MaxLineSize], "last"}, lines)
+}
+
+func TestFollowFile(t *testing.T) {
+	pollInterval = 5 * time.Millisecond
+	path := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, os.WriteFile(path, []byte("old\n"), 0600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	out := make(chan string, 10)
+	errc := make(chan error, 1)
+	go func() { errc <- FollowFile(ctx, path, false, out) }()
+
+	assert.Equal(t, "old", <-out)
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	file.WriteString("appended\n")
+	file.Close()
+	assert.Equal(t, "appended", <-out)
+
+	// Rotation: the file is replaced by a new, shorter one.
+	require.NoError(t, os.Rename(path, path+".1"))
+	require.NoError(t, os.WriteFile(path, []byte("new\n"), 0600))
+	assert.Equal(t, "new", <-out)
+
+	cancel()
+	assert.NoError(t, <-errc)
+}
+
+func TestFollowFileTruncatedAfterCRLF(t *testing.T) {
+	pollInterval = 5 * time.Millisecond
+	path := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("line\r\n", 10)), 0600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	out := make(chan string, 20)
+	errc := make(chan error, 1)
+	go func() { errc <- FollowFile(ctx, path, false, out) }()
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, "line", <-out)
+	}
+
+	// Truncated in place to 56 bytes, fewer than the 60 read so far, counting the carriage returns.
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("fresh\r\n", 8)), 0600))
+	select {
+	case line := <-out:
+		assert.Equal(t, "fresh", line)
+	case <-time.After(time.Second):
+		t.Fatal("the truncation was not detected")
+	}
+
+	cancel()
+	assert.NoError(t, <-errc)
+}
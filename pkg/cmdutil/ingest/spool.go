@@ -0,0 +1,528 @@
+package ingest
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/logfire-sh/cli/api"
+	"github.com/logfire-sh/cli/internal/fsutil"
+)
+
+const (
+	segmentExt     = ".seg"
+	checkpointFile = "checkpoint"
+	lockFile       = "lock"
+	// RejectedFile keeps the records of the batches the endpoint rejected, one per line.
+	RejectedFile = "rejected.ndjson"
+)
+
+// ErrSpoolLocked is returned when another process is using the spool directory.
+var ErrSpoolLocked = errors.New("spool directory is in use by another process")
+
+// SpoolOptions limit the disk space a Spool may use.
+type SpoolOptions struct {
+	// MaxBytes caps the total size of the segments; the oldest ones are dropped beyond it.
+	MaxBytes int64
+	// MaxAge drops segments whose last write is older than this.
+	MaxAge time.Duration
+	// SegmentBytes is the size at which a new segment is started.
+	SegmentBytes int64
+
+	// OnDrop, when set, is told about every segment dropped to respect the caps that held
+	// batches that were not shipped yet, and their number.
+	OnDrop func(segment string, batches int)
+	// OnReject, when set, is told about every batch the endpoint rejected and that was moved
+	// to RejectedFile.
+	OnReject func(err error, records int)
+}
+
+// DefaultSpoolOptions are the limits of `logfire send`.
+var DefaultSpoolOptions = SpoolOptions{
+	MaxBytes:     256 * 1024 * 1024,
+	MaxAge:       72 * time.Hour,
+	SegmentBytes: 8 * 1024 * 1024,
+}
+
+// Spool is a write-ahead log of batches on disk. Batches are appended to numbered segment files
+// and shipped from there in order; the position after the last acknowledged batch is saved in a
+// checkpoint file, so batches that were not acknowledged are sent again after a restart.
+//
+// A Spool is a Sender, so a Batcher can write into it while Ship delivers its content.
+type Spool struct {
+	dir  string
+	opts SpoolOptions
+	lock *os.File
+
+	mu      sync.Mutex
+	segment int64 // segment being written
+	file    *os.File
+	size    int64
+	closed  bool
+	notify  chan struct{}
+}
+
+type checkpoint struct {
+	Segment int64 `json:"segment"`
+	Offset  int64 `json:"offset"`
+}
+
+// OpenSpool opens or creates the spool in dir. Batches left over by an earlier run are shipped first.
+func OpenSpool(dir string, opts SpoolOptions) (*Spool, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	lock, err := lockDir(filepath.Join(dir, lockFile))
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Spool{dir: dir, opts: opts, lock: lock, notify: make(chan struct{}, 1)}
+
+	segments, err := s.segments()
+	if err != nil {
+		s.unlock()
+		return nil, err
+	}
+
+	// Never append to a segment of an earlier run, its last batch may be torn.
+	s.segment = 1
+	if len(segments) > 0 {
+		s.segment = segments[len(segments)-1] + 1
+	}
+	if err := s.openSegment(); err != nil {
+		s.unlock()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Dir returns the directory of the spool.
+func (s *Spool) Dir() string {
+	return s.dir
+}
+
+// SendLogs appends a batch to the spool and syncs it to disk before returning.
+func (s *Spool) SendLogs(records interface{}) error {
+	line, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return errors.New("spool is closed")
+	}
+
+	if s.size > 0 && s.size+int64(len(line)) > s.opts.SegmentBytes {
+		if err := s.file.Close(); err != nil {
+			return err
+		}
+		s.segment++
+		if err := s.openSegment(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := s.file.Write(line); err != nil {
+		return err
+	}
+	if err := s.file.Sync(); err != nil {
+		return err
+	}
+	s.size += int64(len(line))
+
+	s.enforceCaps()
+	s.signal()
+	return nil
+}
+
+// CloseWrite marks the end of the input. Ship returns once everything written before is acknowledged.
+func (s *Spool) CloseWrite() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	s.signal()
+}
+
+// Close releases the spool. Batches that were not shipped stay on disk for the next run.
+func (s *Spool) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	err := s.file.Close()
+
+	// Don't leave an empty segment behind for every run.
+	if s.size == 0 {
+		os.Remove(s.segmentPath(s.segment))
+	}
+	s.unlock()
+	return err
+}
+
+// Pending returns the number of batches that were not acknowledged yet.
+func (s *Spool) Pending() (int, error) {
+	cp, err := s.readCheckpoint()
+	if err != nil {
+		return 0, err
+	}
+	segments, err := s.segments()
+	if err != nil {
+		return 0, err
+	}
+
+	pending := 0
+	for _, segment := range segments {
+		if segment < cp.Segment {
+			continue
+		}
+		offset := int64(0)
+		if segment == cp.Segment {
+			offset = cp.Offset
+		}
+		n, err := countBatches(s.segmentPath(segment), offset)
+		if err != nil {
+			return 0, err
+		}
+		pending += n
+	}
+	return pending, nil
+}
+
+// Ship sends the spooled batches to sender in order and saves a checkpoint after each acknowledged one.
+// Temporary failures are retried until ctx is done. A batch the endpoint rejects is moved to RejectedFile,
+// so it does not hold back the batches behind it, except when the credentials are rejected, which no
+// batch would get past. It returns nil once the spool was closed for writing and is empty, ctx.Err()
+// when ctx is done, and the error of a batch whose credentials were rejected.
+func (s *Spool) Ship(ctx context.Context, sender Sender, retryDelay time.Duration, onRetry func(err error, attempt int)) error {
+	cp, err := s.readCheckpoint()
+	if err != nil {
+		return err
+	}
+
+	for {
+		segment, offset, err := s.nextBatch(ctx, &cp)
+		if err != nil || segment == nil {
+			return err
+		}
+
+		var batch []Record
+		if err := json.Unmarshal(segment, &batch); err != nil {
+			// A batch torn by a crash was never acknowledged to the producer; skip it.
+			cp.Offset = offset
+			continue
+		}
+
+		err = retry(ctx, 0, retryDelay, onRetry, func() error {
+			return sender.SendLogs(batch)
+		})
+		if rejected(err) {
+			if err := s.reject(batch); err != nil {
+				return err
+			}
+			if s.opts.OnReject != nil {
+				s.opts.OnReject(err, len(batch))
+			}
+		} else if err != nil {
+			return err
+		}
+
+		cp.Offset = offset
+		if err := s.writeCheckpoint(cp); err != nil {
+			return err
+		}
+	}
+}
+
+// rejected reports whether the endpoint refused a batch for good for its content, rather than for
+// the credentials or a temporary failure.
+func rejected(err error) bool {
+	var apiErr *api.APIError
+	if !errors.As(err, &apiErr) || temporary(err) {
+		return false
+	}
+	return apiErr.StatusCode != http.StatusUnauthorized && apiErr.StatusCode != http.StatusForbidden
+}
+
+// reject appends the records of a batch to RejectedFile, where they can be fixed and sent again.
+func (s *Spool) reject(batch []Record) error {
+	file, err := os.OpenFile(filepath.Join(s.dir, RejectedFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	for _, record := range batch {
+		line, err := json.Marshal(record)
+		if err != nil {
+			file.Close()
+			return err
+		}
+		data = append(append(data, line...), '\n')
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// nextBatch returns the next complete batch after cp and the offset behind it. It moves cp on to
+// the next segment at the end of a finished one and waits for new batches at the end of the
+// current one. A nil batch means the spool was closed and everything was read.
+func (s *Spool) nextBatch(ctx context.Context, cp *checkpoint) ([]byte, int64, error) {
+	for {
+		s.mu.Lock()
+		writing, closed := s.segment, s.closed
+		s.mu.Unlock()
+
+		line, err := readLine(s.segmentPath(cp.Segment), cp.Offset)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, 0, err
+		}
+		if line != nil {
+			return line, cp.Offset + int64(len(line)) + 1, nil
+		}
+
+		if cp.Segment < writing {
+			// Everything in this segment was acknowledged, or it was dropped by the caps.
+			if err := os.Remove(s.segmentPath(cp.Segment)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return nil, 0, err
+			}
+			next, err := s.nextSegment(cp.Segment)
+			if err != nil {
+				return nil, 0, err
+			}
+			cp.Segment, cp.Offset = next, 0
+			if err := s.writeCheckpoint(*cp); err != nil {
+				return nil, 0, err
+			}
+			continue
+		}
+
+		if closed {
+			return nil, 0, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, 0, ctx.Err()
+		case <-s.notify:
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// nextSegment returns the first segment after current, or the segment being written.
+func (s *Spool) nextSegment(current int64) (int64, error) {
+	segments, err := s.segments()
+	if err != nil {
+		return 0, err
+	}
+	for _, segment := range segments {
+		if segment > current {
+			return segment, nil
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.segment, nil
+}
+
+// enforceCaps drops the oldest segments that exceed the size or age limits, shipped or not.
+// It never touches the segment being written. Called with s.mu held.
+func (s *Spool) enforceCaps() {
+	segments, err := s.segments()
+	if err != nil {
+		return
+	}
+	cp, err := s.readCheckpoint()
+	if err != nil {
+		return
+	}
+
+	var total int64
+	infos := make(map[int64]os.FileInfo, len(segments))
+	for _, segment := range segments {
+		info, err := os.Stat(s.segmentPath(segment))
+		if err != nil {
+			continue
+		}
+		infos[segment] = info
+		total += info.Size()
+	}
+
+	for _, segment := range segments {
+		info, ok := infos[segment]
+		if !ok || segment >= s.segment {
+			break
+		}
+
+		tooBig := s.opts.MaxBytes > 0 && total > s.opts.MaxBytes
+		tooOld := s.opts.MaxAge > 0 && time.Since(info.ModTime()) > s.opts.MaxAge
+		if !tooBig && !tooOld {
+			break
+		}
+
+		// Only the batches after the checkpoint are lost, the others were shipped.
+		batches := 0
+		switch {
+		case segment == cp.Segment:
+			batches, _ = countBatches(s.segmentPath(segment), cp.Offset)
+		case segment > cp.Segment:
+			batches, _ = countBatches(s.segmentPath(segment), 0)
+		}
+		if os.Remove(s.segmentPath(segment)) != nil {
+			break
+		}
+		total -= info.Size()
+		if s.opts.OnDrop != nil && batches > 0 {
+			s.opts.OnDrop(filepath.Base(s.segmentPath(segment)), batches)
+		}
+	}
+}
+
+func (s *Spool) openSegment() error {
+	file, err := os.OpenFile(s.segmentPath(s.segment), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	s.file = file
+	s.size = 0
+	return nil
+}
+
+func (s *Spool) signal() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Spool) unlock() {
+	if s.lock != nil {
+		unlockDir(s.lock)
+		s.lock = nil
+	}
+}
+
+func (s *Spool) segmentPath(segment int64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%020d%s", segment, segmentExt))
+}
+
+// segments returns the numbers of the segment files in ascending order.
+func (s *Spool) segments() ([]int64, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var segments []int64
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasSuffix(name, segmentExt) {
+			continue
+		}
+		n, err := strconv.ParseInt(strings.TrimSuffix(name, segmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, n)
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i] < segments[j] })
+	return segments, nil
+}
+
+func (s *Spool) readCheckpoint() (checkpoint, error) {
+	var cp checkpoint
+	data, err := os.ReadFile(filepath.Join(s.dir, checkpointFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return cp, nil
+	}
+	if err != nil {
+		return cp, err
+	}
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return checkpoint{}, fmt.Errorf("corrupt spool checkpoint %s: %w", filepath.Join(s.dir, checkpointFile), err)
+	}
+	return cp, nil
+}
+
+// writeCheckpoint replaces the checkpoint atomically, so a crash leaves either the old or the new one.
+func (s *Spool) writeCheckpoint(cp checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	return fsutil.WriteFile(filepath.Join(s.dir, checkpointFile), data, 0600)
+}
+
+// readLine returns the complete line starting at offset in the file at path, or nil at its end.
+func readLine(path string, offset int64) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	line, err := bufio.NewReader(file).ReadBytes('\n')
+	if errors.Is(err, io.EOF) {
+		// Nothing, or a batch that is still being written.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return line[:len(line)-1], nil
+}
+
+func countBatches(path string, offset int64) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	n := 0
+	reader := bufio.NewReader(file)
+	for {
+		_, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+}
@@ -0,0 +1,193 @@
+package ingest
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/logfire-sh/cli/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func batch(messages ...string) []Record {
+	batch := make([]Record, 0, len(messages))
+	for _, message := range messages {
+		batch = append(batch, Record{"message": message})
+	}
+	return batch
+}
+
+func messages(batches [][]Record) []string {
+	var messages []string
+	for _, batch := range batches {
+		for _, record := range batch {
+			messages = append(messages, record["message"].(string))
+		}
+	}
+	return messages
+}
+
+func TestSpoolShip(t *testing.T) {
+	spool, err := OpenSpool(t.TempDir(), DefaultSpoolOptions)
+	require.NoError(t, err)
+	defer spool.Close()
+
+	require.NoError(t, spool.SendLogs(batch("a", "b")))
+	require.NoError(t, spool.SendLogs(batch("c")))
+	spool.CloseWrite()
+
+	sender := &fakeSender{errs: []error{&api.ConnectionError{}}}
+	require.NoError(t, spool.Ship(context.Background(), sender, time.Millisecond, nil))
+
+	assert.Equal(t, []string{"a", "b", "c"}, messages(sender.batches))
+	pending, err := spool.Pending()
+	require.NoError(t, err)
+	assert.Equal(t, 0, pending)
+}
+
+func TestSpoolReplaysAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+
+	spool, err := OpenSpool(dir, DefaultSpoolOptions)
+	require.NoError(t, err)
+	require.NoError(t, spool.SendLogs(batch("a")))
+	require.NoError(t, spool.SendLogs(batch("b")))
+	spool.CloseWrite()
+
+	// The endpoint rejects the credentials of the second batch, so only the first one is checkpointed.
+	sender := &fakeSender{errs: []error{nil, &api.APIError{StatusCode: http.StatusUnauthorized}}}
+	assert.Error(t, spool.Ship(context.Background(), sender, time.Millisecond, nil))
+	require.NoError(t, spool.Close())
+
+	spool, err = OpenSpool(dir, DefaultSpoolOptions)
+	require.NoError(t, err)
+	defer spool.Close()
+
+	pending, err := spool.Pending()
+	require.NoError(t, err)
+	assert.Equal(t, 1, pending)
+
+	require.NoError(t, spool.SendLogs(batch("c")))
+	spool.CloseWrite()
+	require.NoError(t, spool.Ship(context.Background(), sender, time.Millisecond, nil))
+
+	assert.Equal(t, []string{"a", "b", "c"}, messages(sender.batches))
+}
+
+func TestSpoolRejectsBatches(t *testing.T) {
+	dir := t.TempDir()
+	var rejects []int
+	opts := DefaultSpoolOptions
+	opts.OnReject = func(err error, records int) {
+		rejects = append(rejects, records)
+	}
+
+	spool, err := OpenSpool(dir, opts)
+	require.NoError(t, err)
+	require.NoError(t, spool.SendLogs(batch("a")))
+	require.NoError(t, spool.SendLogs(batch("b", "c")))
+	require.NoError(t, spool.SendLogs(batch("d")))
+	spool.CloseWrite()
+
+	sender := &fakeSender{errs: []error{nil, &api.APIError{StatusCode: http.StatusBadRequest}}}
+	require.NoError(t, spool.Ship(context.Background(), sender, time.Millisecond, nil))
+	require.NoError(t, spool.Close())
+
+	assert.Equal(t, []string{"a", "d"}, messages(sender.batches))
+	assert.Equal(t, []int{2}, rejects)
+	rejected, err := os.ReadFile(filepath.Join(dir, RejectedFile))
+	require.NoError(t, err)
+	assert.Equal(t, "{\"message\":\"b\"}\n{\"message\":\"c\"}\n", string(rejected))
+
+	// The next run ships its own batches, not the rejected one again.
+	spool, err = OpenSpool(dir, opts)
+	require.NoError(t, err)
+	defer spool.Close()
+
+	require.NoError(t, spool.SendLogs(batch("e")))
+	spool.CloseWrite()
+	require.NoError(t, spool.Ship(context.Background(), sender, time.Millisecond, nil))
+	assert.Equal(t, []string{"a", "d", "e"}, messages(sender.batches))
+}
+
+func TestSpoolShipStopsWithContext(t *testing.T) {
+	spool, err := OpenSpool(t.TempDir(), DefaultSpoolOptions)
+	require.NoError(t, err)
+	defer spool.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// Nothing was written and the spool is still open, so Ship waits for batches.
+	assert.ErrorIs(t, spool.Ship(ctx, &fakeSender{}, time.Millisecond, nil), context.DeadlineExceeded)
+}
+
+func TestSpoolDropsOldestSegments(t *testing.T) {
+	var dropped int
+	opts := SpoolOptions{
+		MaxBytes:     64,
+		SegmentBytes: 1,
+		OnDrop: func(segment string, batches int) {
+			dropped += batches
+		},
+	}
+
+	spool, err := OpenSpool(t.TempDir(), opts)
+	require.NoError(t, err)
+	defer spool.Close()
+
+	for _, message := range []string{"one", "two", "three", "four", "five"} {
+		require.NoError(t, spool.SendLogs(batch(message)))
+	}
+	spool.CloseWrite()
+
+	sender := &fakeSender{}
+	require.NoError(t, spool.Ship(context.Background(), sender, time.Millisecond, nil))
+
+	assert.Greater(t, dropped, 0)
+	assert.Equal(t, 5, dropped+len(sender.batches))
+	assert.Equal(t, "five", messages(sender.batches)[len(sender.batches)-1])
+}
+
+func TestSpoolDropCountsUnshippedBatches(t *testing.T) {
+	var dropped []int
+	opts := SpoolOptions{
+		// Two batches of 20 bytes fit in a segment, and the third one exceeds MaxBytes.
+		MaxBytes:     50,
+		SegmentBytes: 40,
+		OnDrop: func(segment string, batches int) {
+			dropped = append(dropped, batches)
+		},
+	}
+
+	spool, err := OpenSpool(t.TempDir(), opts)
+	require.NoError(t, err)
+	defer spool.Close()
+
+	require.NoError(t, spool.SendLogs(batch("one")))
+	require.NoError(t, spool.SendLogs(batch("two")))
+	sender := &fakeSender{errs: []error{nil, &api.APIError{StatusCode: http.StatusUnauthorized}}}
+	assert.Error(t, spool.Ship(context.Background(), sender, time.Millisecond, nil))
+
+	require.NoError(t, spool.SendLogs(batch("six")))
+	assert.Equal(t, []int{1}, dropped)
+}
+
+func TestSpoolLocked(t *testing.T) {
+	dir := t.TempDir()
+
+	spool, err := OpenSpool(dir, DefaultSpoolOptions)
+	require.NoError(t, err)
+
+	_, err = OpenSpool(dir, DefaultSpoolOptions)
+	assert.ErrorIs(t, err, ErrSpoolLocked)
+
+	require.NoError(t, spool.Close())
+	spool, err = OpenSpool(dir, DefaultSpoolOptions)
+	require.NoError(t, err)
+	require.NoError(t, spool.Close())
+}
@@ -0,0 +1,134 @@
+package logformat
+
+import (
+	"fmt"
+	"regexp"
+
+	pb "github.com/logfire-sh/cli/services/flink-service"
+)
+
+// CompileRegexps compiles the patterns of a flag such as --grep, naming the flag in errors.
+func CompileRegexps(flag string, patterns []string) ([]*regexp.Regexp, error) {
+	var regexps []*regexp.Regexp
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s pattern %q: %w", flag, pattern, err)
+		}
+		regexps = append(regexps, re)
+	}
+	return regexps, nil
+}
+
+// Grep selects the records of a stream by regular expressions on their message, on the client,
+// as SearchQueries only match substrings on the server.
+//
+// Records matching an exclude pattern are dropped. Of the others, the records matching an include
+// pattern are kept, or all of them when there is none, together with up to context records
+// before and after each of them. Context spans batches, so the records kept before a match may
+// come from an earlier batch.
+type Grep struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+	context int
+
+	// before holds the last records that were not kept, the context of the next match.
+	before []*pb.FilteredRecord
+	// after counts the records still kept after the last match.
+	after int
+	// kept is set once a record was kept, and skipped when a record was dropped since.
+	kept, skipped bool
+}
+
+// NewGrep returns a Grep keeping context records around every match.
+func NewGrep(include, exclude []*regexp.Regexp, context int) *Grep {
+	return &Grep{include: include, exclude: exclude, context: context}
+}
+
+// Enabled reports whether g drops any record.
+func (g *Grep) Enabled() bool {
+	return g != nil && (len(g.include) > 0 || len(g.exclude) > 0)
+}
+
+// Filter returns the records of a batch to print, in order. With a context, a nil record stands
+// between records that are not next to each other in the stream, where grep prints "--".
+func (g *Grep) Filter(records []*pb.FilteredRecord) []*pb.FilteredRecord {
+	if !g.Enabled() {
+		return records
+	}
+
+	var kept []*pb.FilteredRecord
+	for _, record := range records {
+		if matchAny(g.exclude, record.Message) {
+			continue
+		}
+
+		switch {
+		case len(g.include) == 0 || matchAny(g.include, record.Message):
+			if g.context > 0 && g.kept && g.skipped {
+				kept = append(kept, nil)
+			}
+			kept = append(kept, g.before...)
+			kept = append(kept, record)
+			g.before = g.before[:0]
+			g.after = g.context
+			g.kept, g.skipped = true, false
+		case g.after > 0:
+			kept = append(kept, record)
+			g.after--
+		default:
+			g.before = append(g.before, record)
+			if len(g.before) > g.context {
+				g.before = g.before[1:]
+				g.skipped = true
+			}
+		}
+	}
+	return kept
+}
+
+func matchAny(regexps []*regexp.Regexp, text string) bool {
+	for _, re := range regexps {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// highlight marks every span of text matched by one of the highlight patterns of p.
+func (p *Printer) highlight(text string) string {
+	if len(p.highlights) == 0 {
+		return text
+	}
+
+	// marked[i] is set for every byte of text inside a match, so overlapping matches merge.
+	marked := make([]bool, len(text))
+	found := false
+	for _, re := range p.highlights {
+		for _, span := range re.FindAllStringIndex(text, -1) {
+			for i := span[0]; i < span[1]; i++ {
+				marked[i] = true
+				found = true
+			}
+		}
+	}
+	if !found {
+		return text
+	}
+
+	var out []byte
+	for start := 0; start < len(text); {
+		end := start
+		for end < len(text) && marked[end] == marked[start] {
+			end++
+		}
+		if marked[start] {
+			out = append(out, p.cs.Highlight(text[start:end])...)
+		} else {
+			out = append(out, text[start:end]...)
+		}
+		start = end
+	}
+	return string(out)
+}
@@ -0,0 +1,118 @@
+package logformat
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/logfire-sh/cli/pkg/iostreams"
+	pb "github.com/logfire-sh/cli/services/flink-service"
+	"github.com/stretchr/testify/assert"
+)
+
+func messages(records []*pb.FilteredRecord) []string {
+	var out []string
+	for _, record := range records {
+		if record == nil {
+			out = append(out, "--")
+			continue
+		}
+		out = append(out, record.Message)
+	}
+	return out
+}
+
+func stream(lines ...string) []*pb.FilteredRecord {
+	var records []*pb.FilteredRecord
+	for _, line := range lines {
+		records = append(records, &pb.FilteredRecord{Message: line})
+	}
+	return records
+}
+
+func TestGrepFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		context int
+		batches [][]*pb.FilteredRecord
+		wants   []string
+	}{
+		{
+			name:    "disabled",
+			batches: [][]*pb.FilteredRecord{stream("a", "b")},
+			wants:   []string{"a", "b"},
+		},
+		{
+			name:    "exclude",
+			exclude: []string{`^GET /health`},
+			batches: [][]*pb.FilteredRecord{stream("GET /health 200", "GET /orders 500", "GET /health 200")},
+			wants:   []string{"GET /orders 500"},
+		},
+		{
+			name:    "include and exclude",
+			include: []string{`\b5\d\d$`},
+			exclude: []string{`/health`},
+			batches: [][]*pb.FilteredRecord{stream("GET /a 200", "GET /health 503", "GET /b 502")},
+			wants:   []string{"GET /b 502"},
+		},
+		{
+			name:    "context across batches",
+			include: []string{`error`},
+			context: 1,
+			batches: [][]*pb.FilteredRecord{
+				stream("1", "2", "3"),
+				stream("error a", "4", "5", "6"),
+				stream("error b", "error c", "7"),
+			},
+			wants: []string{"3", "error a", "4", "--", "6", "error b", "error c", "7"},
+		},
+		{
+			name:    "adjacent context has no separator",
+			include: []string{`error`},
+			context: 2,
+			batches: [][]*pb.FilteredRecord{stream("error a", "1", "2", "3", "error b")},
+			wants:   []string{"error a", "1", "2", "3", "error b"},
+		},
+		{
+			name:    "excluded records are not context",
+			include: []string{`error`},
+			exclude: []string{`health`},
+			context: 1,
+			batches: [][]*pb.FilteredRecord{stream("1", "health", "error a", "health", "2")},
+			wants:   []string{"1", "error a", "2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			include, err := CompileRegexps("--grep", tt.include)
+			assert.NoError(t, err)
+			exclude, err := CompileRegexps("--exclude", tt.exclude)
+			assert.NoError(t, err)
+
+			grep := NewGrep(include, exclude, tt.context)
+			var got []string
+			for _, batch := range tt.batches {
+				got = append(got, messages(grep.Filter(batch))...)
+			}
+			assert.Equal(t, tt.wants, got)
+		})
+	}
+}
+
+func TestCompileRegexps(t *testing.T) {
+	_, err := CompileRegexps("--grep", []string{"ok", "(unclosed"})
+	assert.EqualError(t, err, "invalid --grep pattern \"(unclosed\": error parsing regexp: missing closing ): `(unclosed`")
+}
+
+func TestPrinterHighlight(t *testing.T) {
+	printer, err := NewPrinter(iostreams.NewColorScheme(true, false, false), FormatRaw, nil, false)
+	assert.NoError(t, err)
+	printer.Highlight([]*regexp.Regexp{regexp.MustCompile(`time`), regexp.MustCompile(`out a`)})
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, printer.Print(out, []*pb.FilteredRecord{{Message: "timeout after 5s"}, nil, {Message: "ok"}}))
+	assert.Equal(t, "\x1b[0;30;43mtimeout a\x1b[0mfter 5s\n\x1b[0;90m--\x1b[0m\nok\n", out.String())
+}
@@ -0,0 +1,273 @@
+package logformat
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/logfire-sh/cli/pkg/cmdutil/grpcutil"
+	"github.com/logfire-sh/cli/pkg/iostreams"
+)
+
+const (
+	SplitLevel  = "level"
+	SplitSource = "source"
+	SplitNone   = "none"
+)
+
+// Splits are the values of logs histogram --by.
+var Splits = []string{SplitLevel, SplitSource, SplitNone}
+
+// minBarWidth is the narrowest bar a Histogram draws, however narrow the terminal.
+const minBarWidth = 10
+
+var (
+	unicodeGlyphs = []string{"█", "▓", "▒", "░"}
+	asciiGlyphs   = []string{"#", "=", "+", ":", "-", "."}
+
+	unicodeSparks = []string{"▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
+	asciiSparks   = []string{".", ":", "-", "=", "+", "*", "#", "@"}
+)
+
+// Histogram draws the volume of logs over time as horizontal bars, one line per bucket, every
+// bar stacked by level or by source. Every part of a bar has its own glyph and colour, so that
+// the chart reads without colours too.
+type Histogram struct {
+	cs    *iostreams.ColorScheme
+	by    string
+	width int
+	ascii bool
+}
+
+// NewHistogram checks the split of the bars, one of Splits. width is the width of the whole
+// lines; ascii draws the bars with ASCII characters instead of Unicode blocks.
+func NewHistogram(cs *iostreams.ColorScheme, by string, width int, ascii bool) (*Histogram, error) {
+	valid := false
+	for _, split := range Splits {
+		valid = valid || by == split
+	}
+	if !valid {
+		return nil, fmt.Errorf("invalid split %q, expected one of: %s", by, strings.Join(Splits, ", "))
+	}
+	return &Histogram{cs: cs, by: by, width: width, ascii: ascii}, nil
+}
+
+// series are the parts of the bars, a level or a source, each with its count in every bucket.
+type series struct {
+	name   string
+	counts []int
+	total  int
+}
+
+// series splits the buckets. Levels are stacked from the most severe one, so that errors start
+// every bar, and sources from the busiest one.
+func (h *Histogram) series(buckets []grpcutil.GraphBucket) []*series {
+	byName := map[string]*series{}
+	var all []*series
+	add := func(i int, name string, count int) {
+		s, ok := byName[name]
+		if !ok {
+			s = &series{name: name, counts: make([]int, len(buckets))}
+			byName[name] = s
+			all = append(all, s)
+		}
+		s.counts[i] += count
+		s.total += count
+	}
+
+	for i, bucket := range buckets {
+		switch h.by {
+		case SplitLevel:
+			for level, count := range bucket.Levels {
+				add(i, level, count)
+			}
+		case SplitSource:
+			for source, count := range bucket.Sources {
+				add(i, source, count)
+			}
+		default:
+			add(i, "logs", bucket.Count)
+		}
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		if h.by == SplitLevel {
+			if ri, rj := severityRank(Severity(all[i].name)), severityRank(Severity(all[j].name)); ri != rj {
+				return ri > rj
+			}
+		} else if all[i].total != all[j].total {
+			return all[i].total > all[j].total
+		}
+		return all[i].name < all[j].name
+	})
+	return all
+}
+
+func (h *Histogram) paint(s *series, index int, text string) string {
+	if h.by == SplitLevel {
+		return paintSeverity(h.cs, s.name, text)
+	}
+	colors := []func(string) string{h.cs.Cyan, h.cs.Magenta, h.cs.Green, h.cs.Blue, h.cs.Yellow}
+	return colors[index%len(colors)](text)
+}
+
+func (h *Histogram) glyph(index int) string {
+	if h.ascii {
+		return asciiGlyphs[index%len(asciiGlyphs)]
+	}
+	return unicodeGlyphs[index%len(unicodeGlyphs)]
+}
+
+// Write draws buckets, oldest first: a summary line and the legend of the parts of the bars,
+// then the bar of every bucket after its local time and its count.
+func (h *Histogram) Write(w io.Writer, buckets []grpcutil.GraphBucket) error {
+	if len(buckets) == 0 {
+		_, err := fmt.Fprintln(w, "No logs in the time range.")
+		return err
+	}
+
+	all := h.series(buckets)
+	total, max := 0, 0
+	for i, bucket := range buckets {
+		count := bucket.Count
+		if h.by != SplitNone {
+			// Servers may count records of no level or source in Count only.
+			parts := 0
+			for _, s := range all {
+				parts += s.counts[i]
+			}
+			if parts > count {
+				count = parts
+			}
+		}
+		total += count
+		if count > max {
+			max = count
+		}
+	}
+
+	step := bucketStep(buckets)
+	layout := timeLayout(buckets, step)
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d logs from %s to %s, at most %d per %s\n",
+		total, buckets[0].Dt.Local().Format(layout), buckets[len(buckets)-1].Dt.Add(step).Local().Format(layout), max, step)
+
+	var legend []string
+	for i, s := range all {
+		name := s.name
+		if name == "" {
+			name = "(none)"
+		}
+		legend = append(legend, h.paint(s, i, h.glyph(i))+" "+name+" "+strconv.Itoa(s.total))
+	}
+	b.WriteString(strings.Join(legend, "  ") + "\n\n")
+
+	countWidth := len(strconv.Itoa(max))
+	barWidth := h.width - len(layout) - countWidth - 2
+	if barWidth < minBarWidth {
+		barWidth = minBarWidth
+	}
+
+	for i, bucket := range buckets {
+		counts := make([]int, len(all))
+		count := 0
+		for j, s := range all {
+			counts[j] = s.counts[i]
+			count += counts[j]
+		}
+		if h.by == SplitNone || bucket.Count > count {
+			count = bucket.Count
+		}
+
+		var bar strings.Builder
+		for j, cells := range stackCells(counts, max, barWidth) {
+			if cells > 0 {
+				bar.WriteString(h.paint(all[j], j, strings.Repeat(h.glyph(j), cells)))
+			}
+		}
+		line := fmt.Sprintf("%s %*d %s", bucket.Dt.Local().Format(layout), countWidth, count, bar.String())
+		b.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// stackCells scales the parts of a bar to the cells of a bar of width cells for max records, by
+// rounding the running total so that the parts add up to the rounded bar. A bar with records
+// keeps at least one cell, for its first part with records.
+func stackCells(counts []int, max, width int) []int {
+	cells := make([]int, len(counts))
+	if max == 0 {
+		return cells
+	}
+
+	sum, drawn := 0, 0
+	for i, count := range counts {
+		sum += count
+		end := (sum*width + max/2) / max
+		cells[i] = end - drawn
+		drawn = end
+	}
+	if drawn == 0 {
+		for i, count := range counts {
+			if count > 0 {
+				cells[i] = 1
+				break
+			}
+		}
+	}
+	return cells
+}
+
+// bucketStep returns the time range of a bucket, from the first two buckets, a minute for a
+// single bucket.
+func bucketStep(buckets []grpcutil.GraphBucket) time.Duration {
+	if len(buckets) < 2 || !buckets[1].Dt.After(buckets[0].Dt) {
+		return time.Minute
+	}
+	return buckets[1].Dt.Sub(buckets[0].Dt)
+}
+
+// timeLayout shows the seconds of buckets shorter than a minute, and the day of buckets over
+// more than a day.
+func timeLayout(buckets []grpcutil.GraphBucket, step time.Duration) string {
+	layout := "15:04"
+	if step < time.Minute {
+		layout = "15:04:05"
+	}
+	first, last := buckets[0].Dt.Local(), buckets[len(buckets)-1].Dt.Local()
+	if first.YearDay() != last.YearDay() || first.Year() != last.Year() {
+		layout = "Jan 02 " + layout
+	}
+	return layout
+}
+
+// Sparkline draws counts on a single line, one character per count scaled to the largest one,
+// and a space for a count of 0.
+func Sparkline(counts []int, ascii bool) string {
+	sparks := unicodeSparks
+	if ascii {
+		sparks = asciiSparks
+	}
+
+	max := 0
+	for _, count := range counts {
+		if count > max {
+			max = count
+		}
+	}
+
+	var b strings.Builder
+	for _, count := range counts {
+		if count <= 0 {
+			b.WriteByte(' ')
+			continue
+		}
+		b.WriteString(sparks[(count*len(sparks)-1)/max])
+	}
+	return b.String()
+}
@@ -0,0 +1,96 @@
+package logformat
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/logfire-sh/cli/pkg/cmdutil/grpcutil"
+	"github.com/logfire-sh/cli/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogram(t *testing.T) {
+	local := time.Local
+	time.Local = time.UTC
+	t.Cleanup(func() { time.Local = local })
+
+	start := time.Date(2024, 5, 1, 22, 0, 0, 0, time.UTC)
+	buckets := []grpcutil.GraphBucket{
+		{Dt: start, Count: 4, Levels: map[string]int{"info": 3, "error": 1}, Sources: map[string]int{"api": 4}},
+		{Dt: start.Add(time.Minute), Count: 0, Levels: map[string]int{}, Sources: map[string]int{}},
+		{Dt: start.Add(2 * time.Minute), Count: 8, Levels: map[string]int{"info": 2, "error": 6}, Sources: map[string]int{"api": 2, "worker": 6}},
+	}
+
+	tests := []struct {
+		by    string
+		ascii bool
+		want  string
+	}{
+		{
+			by: SplitLevel,
+			want: heredoc.Doc(`
+				12 logs from 22:00 to 22:03, at most 8 per 1m0s
+				█ error 7  ▓ info 5
+
+				22:00 4 ██▓▓▓▓▓▓
+				22:01 0
+				22:02 8 ████████████▓▓▓▓
+			`),
+		},
+		{
+			by:    SplitSource,
+			ascii: true,
+			want: heredoc.Doc(`
+				12 logs from 22:00 to 22:03, at most 8 per 1m0s
+				# api 6  = worker 6
+
+				22:00 4 ########
+				22:01 0
+				22:02 8 ####============
+			`),
+		},
+		{
+			by: SplitNone,
+			want: heredoc.Doc(`
+				12 logs from 22:00 to 22:03, at most 8 per 1m0s
+				█ logs 12
+
+				22:00 4 ████████
+				22:01 0
+				22:02 8 ████████████████
+			`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.by, func(t *testing.T) {
+			histogram, err := NewHistogram(iostreams.NewColorScheme(false, false, false), tt.by, 24, tt.ascii)
+			require.NoError(t, err)
+
+			var out bytes.Buffer
+			require.NoError(t, histogram.Write(&out, buckets))
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestHistogramInvalidSplit(t *testing.T) {
+	_, err := NewHistogram(iostreams.NewColorScheme(false, false, false), "host", 80, false)
+	assert.EqualError(t, err, `invalid split "host", expected one of: level, source, none`)
+}
+
+func TestStackCells(t *testing.T) {
+	assert.Equal(t, []int{3, 2}, stackCells([]int{3, 2}, 5, 5))
+	assert.Equal(t, []int{1, 2}, stackCells([]int{1, 1}, 3, 4))
+	// A bar with records keeps a cell, however small.
+	assert.Equal(t, []int{0, 1}, stackCells([]int{0, 1}, 1000, 10))
+	assert.Equal(t, []int{0, 0}, stackCells([]int{0, 0}, 0, 10))
+}
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "▁ ▄█", Sparkline([]int{1, 0, 4, 8}, false))
+	assert.Equal(t, ". =@", Sparkline([]int{1, 0, 4, 8}, true))
+	assert.Equal(t, "  ", Sparkline([]int{0, 0}, false))
+}
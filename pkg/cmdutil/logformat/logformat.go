@@ -0,0 +1,375 @@
+// Package logformat prints the records of tail and the other log commands as lines of text.
+package logformat
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/logfire-sh/cli/internal/text"
+	"github.com/logfire-sh/cli/pkg/cmdutil/grpcutil"
+	"github.com/logfire-sh/cli/pkg/cmdutil/ingest"
+	"github.com/logfire-sh/cli/pkg/iostreams"
+	pb "github.com/logfire-sh/cli/services/flink-service"
+)
+
+const (
+	FormatDefault = "default"
+	FormatShort   = "short"
+	FormatFull    = "full"
+	FormatRaw     = "raw"
+	FormatLogfmt  = "logfmt"
+)
+
+// Formats are the presets accepted by --format besides Go templates.
+var Formats = []string{FormatDefault, FormatShort, FormatFull, FormatRaw, FormatLogfmt}
+
+var presets = map[string]string{
+	FormatDefault: `{{timestamp .Dt}} {{source .Source}} [{{level .Level}}] {{.Message}}`,
+	FormatShort:   `{{timestamp .Clock}} [{{level .Level}}] {{.Message}}`,
+	FormatFull:    `{{timestamp .Dt}} {{source .Source}} {{gray .SourceID}}#{{gray .Offset}} [{{level .Level}}] {{.Message}}`,
+	FormatRaw:     `{{.Message}}`,
+	FormatLogfmt:  `{{.Logfmt}}`,
+}
+
+// maxColumnWidth caps the width of every --fields column but the last, which is never cut.
+const maxColumnWidth = 40
+
+// Record is what a --format template sees of a FilteredRecord.
+type Record struct {
+	Dt       string
+	Level    string
+	Source   string
+	SourceID string
+	Offset   uint64
+	Message  string
+	// Fields are the fields of a JSON or logfmt Message, nil for plain text.
+	Fields map[string]interface{}
+}
+
+// NewRecord parses the message of record into Fields.
+func NewRecord(record *pb.FilteredRecord) *Record {
+	return &Record{
+		Dt:       record.Dt,
+		Level:    record.Level,
+		Source:   record.SourceName,
+		SourceID: record.SourceID,
+		Offset:   record.Offset,
+		Message:  record.Message,
+		Fields:   ingest.ParseFields(record.Message),
+	}
+}
+
+// Field returns the value of key as text. The keys dt, level, source, source_id, offset and message
+// read the record; any other key reads the message, where a dotted key such as user.id reads
+// nested objects. Missing fields are empty.
+func (r *Record) Field(key string) string {
+	switch key {
+	case "dt":
+		return r.Dt
+	case "level":
+		return r.Level
+	case "source":
+		return r.Source
+	case "source_id":
+		return r.SourceID
+	case "offset":
+		return strconv.FormatUint(r.Offset, 10)
+	case "message":
+		if value, ok := r.Fields["message"]; ok {
+			return valueString(value)
+		}
+		if value, ok := r.Fields["msg"]; ok {
+			return valueString(value)
+		}
+		return r.Message
+	}
+
+	if value, ok := r.Fields[key]; ok {
+		return valueString(value)
+	}
+
+	var value interface{} = r.Fields
+	for _, part := range strings.Split(key, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		if value, ok = object[part]; !ok {
+			return ""
+		}
+	}
+	return valueString(value)
+}
+
+// Clock returns the local time of day of Dt, or Dt when it cannot be parsed.
+func (r *Record) Clock() string {
+	t, ok := grpcutil.RecordTime(&pb.FilteredRecord{Dt: r.Dt})
+	if !ok {
+		return r.Dt
+	}
+	return t.Local().Format("15:04:05.000")
+}
+
+// Logfmt formats the record as key=value pairs: dt, level and source, then the fields of the
+// message in name order, or the whole message when it has no fields.
+func (r *Record) Logfmt() string {
+	pairs := []string{"dt=" + logfmtValue(r.Dt), "level=" + logfmtValue(r.Level), "source=" + logfmtValue(r.Source)}
+	if r.Fields == nil {
+		return strings.Join(append(pairs, "message="+logfmtValue(r.Message)), " ")
+	}
+
+	keys := make([]string, 0, len(r.Fields))
+	for key := range r.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+logfmtValue(valueString(r.Fields[key])))
+	}
+	return strings.Join(pairs, " ")
+}
+
+func valueString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
+
+func logfmtValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\n\"=\\") {
+		return value
+	}
+	return strconv.Quote(value)
+}
+
+// Printer writes records as lines of text, with a --format template or as the --fields columns.
+type Printer struct {
+	cs       *iostreams.ColorScheme
+	template *template.Template
+
+	pretty bool
+	// highlights are the patterns whose matches are marked in messages.
+	highlights []*regexp.Regexp
+
+	columns []string
+	widths  []int
+	started bool
+}
+
+// NewPrinter checks the --format, --fields and --pretty flags. format is a preset of Formats or a
+// Go template over Record; fields selects columns instead. Both empty give the default preset.
+// pretty prints the fields of JSON and logfmt messages on indented lines below the formatted line.
+func NewPrinter(cs *iostreams.ColorScheme, format string, fields []string, pretty bool) (*Printer, error) {
+	p := &Printer{cs: cs, pretty: pretty}
+
+	if len(fields) > 0 {
+		if format != "" {
+			return nil, fmt.Errorf("--format and --fields cannot be used together")
+		}
+		if pretty {
+			return nil, fmt.Errorf("--pretty and --fields cannot be used together")
+		}
+		for _, field := range fields {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				return nil, fmt.Errorf("--fields must not contain empty names")
+			}
+			p.columns = append(p.columns, field)
+			p.widths = append(p.widths, text.DisplayWidth(field))
+		}
+		return p, nil
+	}
+
+	if format == "" {
+		format = FormatDefault
+	}
+	if preset, ok := presets[format]; ok {
+		format = preset
+	} else if !strings.Contains(format, "{{") {
+		return nil, fmt.Errorf("invalid format %q, expected a Go template or one of: %s", format, strings.Join(Formats, ", "))
+	}
+
+	t, err := template.New("format").Funcs(p.funcs()).Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid format: %w", err)
+	}
+	// Unknown fields only fail when the template runs, so run it once before any record arrives.
+	if err := t.Execute(io.Discard, &Record{}); err != nil {
+		return nil, fmt.Errorf("invalid format: %w", err)
+	}
+	p.template = t
+	return p, nil
+}
+
+func (p *Printer) funcs() template.FuncMap {
+	color := func(paint func(string) string) func(interface{}) string {
+		return func(value interface{}) string {
+			return paint(fmt.Sprint(value))
+		}
+	}
+	return template.FuncMap{
+		"level": func(level string) string {
+			return p.paintLevel(level, strings.ToUpper(level))
+		},
+		// timestamp and source colour the time and the source with the theme.
+		"timestamp": color(p.cs.Timestamp),
+		"source":    color(p.cs.Source),
+		"upper":     strings.ToUpper,
+		"lower":     strings.ToLower,
+		"yellow":    color(p.cs.Yellow),
+		"green":     color(p.cs.Green),
+		"cyan":      color(p.cs.Cyan),
+		"red":       color(p.cs.Red),
+		"gray":      color(p.cs.Gray),
+		"blue":      color(p.cs.Blue),
+		"magenta":   color(p.cs.Magenta),
+		"bold":      color(p.cs.Bold),
+		"json": func(v interface{}) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}
+}
+
+// Highlight marks the matches of patterns in the messages printed from now on.
+func (p *Printer) Highlight(patterns []*regexp.Regexp) {
+	p.highlights = append(p.highlights, patterns...)
+}
+
+// Print writes one line per record, and a "--" line for a nil record, which Grep.Filter puts
+// between records that are not next to each other.
+func (p *Printer) Print(w io.Writer, records []*pb.FilteredRecord) error {
+	if p.template == nil {
+		return p.printColumns(w, records)
+	}
+
+	for _, record := range records {
+		var err error
+		if record == nil {
+			_, err = io.WriteString(w, p.cs.Gray("--")+"\n")
+		} else {
+			err = p.printTemplate(w, NewRecord(record))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Printer) printTemplate(w io.Writer, record *Record) error {
+	var details []string
+	if p.pretty {
+		record, details = p.expand(record)
+	}
+	record.Message = p.highlight(record.Message)
+
+	var line bytes.Buffer
+	if err := p.template.Execute(&line, record); err != nil {
+		return err
+	}
+	if !bytes.HasSuffix(line.Bytes(), []byte("\n")) {
+		line.WriteByte('\n')
+	}
+	for _, detail := range details {
+		line.WriteString(detail + "\n")
+	}
+	_, err := w.Write(line.Bytes())
+	return err
+}
+
+// printColumns aligns the columns on the widest value printed so far, as a stream cannot know
+// the widest value in advance. A header names the columns before the first batch.
+func (p *Printer) printColumns(w io.Writer, records []*pb.FilteredRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	rows := make([][]string, 0, len(records)+1)
+	if !p.started {
+		p.started = true
+		header := make([]string, len(p.columns))
+		for i, column := range p.columns {
+			header[i] = strings.ToUpper(column)
+		}
+		rows = append(rows, header)
+	}
+	for _, record := range records {
+		if record == nil {
+			rows = append(rows, nil)
+			continue
+		}
+		r := NewRecord(record)
+		values := make([]string, len(p.columns))
+		for i, column := range p.columns {
+			values[i] = r.Field(column)
+		}
+		rows = append(rows, values)
+	}
+
+	// Every column but the last is cut to maxColumnWidth, and widened for the whole batch at once.
+	for _, values := range rows {
+		for i := 0; i < len(values)-1; i++ {
+			values[i] = text.Truncate(maxColumnWidth, values[i])
+			if width := text.DisplayWidth(values[i]); width > p.widths[i] {
+				p.widths[i] = width
+			}
+		}
+	}
+
+	var out strings.Builder
+	for n, values := range rows {
+		if values == nil {
+			out.WriteString(p.cs.Gray("--") + "\n")
+			continue
+		}
+		header := n == 0 && len(rows) > len(records)
+		for i, value := range values {
+			cell := value
+			if !header {
+				cell = p.highlight(value)
+			}
+			if i < len(values)-1 {
+				cell += strings.Repeat(" ", p.widths[i]-text.DisplayWidth(value)+2)
+			}
+			if header {
+				cell = p.cs.Bold(cell)
+			} else {
+				cell = p.paintColumn(p.columns[i], value, cell)
+			}
+			out.WriteString(cell)
+		}
+		out.WriteByte('\n')
+	}
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+func (p *Printer) paintColumn(column, value, cell string) string {
+	switch column {
+	case "dt":
+		return p.cs.Timestamp(cell)
+	case "source":
+		return p.cs.Source(cell)
+	case "level":
+		return p.paintLevel(value, cell)
+	}
+	return cell
+}
@@ -0,0 +1,99 @@
+package logformat
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/logfire-sh/cli/pkg/iostreams"
+	pb "github.com/logfire-sh/cli/services/flink-service"
+	"github.com/stretchr/testify/assert"
+)
+
+var testRecords = []*pb.FilteredRecord{
+	{Offset: 7, Dt: "2024-05-01T12:00:00Z", Level: "error", SourceName: "api", SourceID: "s1",
+		Message: `{"message":"request failed","status":500,"user":{"id":"u-42"}}`},
+	{Offset: 8, Dt: "2024-05-01T12:00:01Z", Level: "info", SourceName: "worker", SourceID: "s2",
+		Message: "job done"},
+}
+
+func TestPrinterFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		wants  string
+	}{
+		{
+			name:  "default",
+			wants: "2024-05-01T12:00:00Z api [ERROR] {\"message\":\"request failed\",\"status\":500,\"user\":{\"id\":\"u-42\"}}\n2024-05-01T12:00:01Z worker [INFO] job done\n",
+		},
+		{
+			name:   "full",
+			format: FormatFull,
+			wants:  "2024-05-01T12:00:00Z api s1#7 [ERROR] {\"message\":\"request failed\",\"status\":500,\"user\":{\"id\":\"u-42\"}}\n2024-05-01T12:00:01Z worker s2#8 [INFO] job done\n",
+		},
+		{
+			name:   "raw",
+			format: FormatRaw,
+			wants:  "{\"message\":\"request failed\",\"status\":500,\"user\":{\"id\":\"u-42\"}}\njob done\n",
+		},
+		{
+			name:   "logfmt",
+			format: FormatLogfmt,
+			wants:  "dt=2024-05-01T12:00:00Z level=error source=api message=\"request failed\" status=500 user=\"{\\\"id\\\":\\\"u-42\\\"}\"\ndt=2024-05-01T12:00:01Z level=info source=worker message=\"job done\"\n",
+		},
+		{
+			name:   "template",
+			format: `{{.Level}} {{.Field "user.id"}} {{.Field "message"}}`,
+			wants:  "error u-42 request failed\ninfo  job done\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			printer, err := NewPrinter(iostreams.NewColorScheme(false, false, false), tt.format, nil, false)
+			assert.NoError(t, err)
+
+			out := &bytes.Buffer{}
+			assert.NoError(t, printer.Print(out, testRecords))
+			assert.Equal(t, tt.wants, out.String())
+		})
+	}
+}
+
+func TestPrinterFields(t *testing.T) {
+	printer, err := NewPrinter(iostreams.NewColorScheme(false, false, false), "", []string{"level", "source", "status", "message"}, false)
+	assert.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, printer.Print(out, testRecords))
+	assert.Equal(t, ""+
+		"LEVEL  SOURCE  STATUS  MESSAGE\n"+
+		"error  api     500     request failed\n"+
+		"info   worker          job done\n", out.String())
+}
+
+func TestNewPrinterErrors(t *testing.T) {
+	cs := iostreams.NewColorScheme(false, false, false)
+
+	tests := []struct {
+		name   string
+		format string
+		fields []string
+		pretty bool
+		errMsg string
+	}{
+		{name: "unknown preset", format: "long", errMsg: `invalid format "long", expected a Go template or one of: default, short, full, raw, logfmt`},
+		{name: "template syntax", format: "{{.Message", errMsg: "invalid format: template: format:1: unclosed action"},
+		{name: "unknown field", format: "{{.Msg}}", errMsg: "invalid format: template: format:1:2: executing \"format\" at <.Msg>: can't evaluate field Msg in type *logformat.Record"},
+		{name: "format and fields", format: FormatRaw, fields: []string{"level"}, errMsg: "--format and --fields cannot be used together"},
+		{name: "pretty and fields", fields: []string{"level"}, pretty: true, errMsg: "--pretty and --fields cannot be used together"},
+		{name: "empty field", fields: []string{"level", " "}, errMsg: "--fields must not contain empty names"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPrinter(cs, tt.format, tt.fields, tt.pretty)
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
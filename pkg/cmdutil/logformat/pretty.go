@@ -0,0 +1,139 @@
+package logformat
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/logfire-sh/cli/pkg/iostreams"
+	pb "github.com/logfire-sh/cli/services/flink-service"
+)
+
+// maxFoldedLines is how many lines of a multi-line value, such as a stack trace, --pretty prints
+// before folding the rest into a count.
+const maxFoldedLines = 8
+
+// severityAliases are the level names that services commonly log instead of a SeverityLevel name.
+var severityAliases = map[string]pb.SeverityLevel{
+	"WARN":      pb.SeverityLevel_WARNING,
+	"ERR":       pb.SeverityLevel_ERROR,
+	"PANIC":     pb.SeverityLevel_FATAL,
+	"CRIT":      pb.SeverityLevel_CRITICAL,
+	"EMERG":     pb.SeverityLevel_ALERT,
+	"EMERGENCY": pb.SeverityLevel_ALERT,
+}
+
+// Severity returns the SeverityLevel of a record level such as "warn" or "ERROR". Unknown levels
+// are INFO, like records that carry no level.
+func Severity(level string) pb.SeverityLevel {
+	name := strings.ToUpper(strings.TrimSpace(level))
+	if severity, ok := severityAliases[name]; ok {
+		return severity
+	}
+	if value, ok := pb.SeverityLevel_value[name]; ok {
+		return pb.SeverityLevel(value)
+	}
+	return pb.SeverityLevel_INFO
+}
+
+// paintLevel colours text by the severity of level with the colours of the theme, from gray for
+// TRACE to bold red for ALERT by default.
+func (p *Printer) paintLevel(level, text string) string {
+	return paintSeverity(p.cs, level, text)
+}
+
+func paintSeverity(cs *iostreams.ColorScheme, level, text string) string {
+	return cs.Level(SeverityName(level), text)
+}
+
+// SeverityName returns the lower case name of the SeverityLevel of a record level, which names
+// its colour in a theme.
+func SeverityName(level string) string {
+	return strings.ToLower(Severity(level).String())
+}
+
+// expand splits record for --pretty into the record of the first line, whose Message is the first
+// line of the message text, and the indented lines that follow it: the rest of the message and
+// every other field of a JSON or logfmt message.
+func (p *Printer) expand(record *Record) (*Record, []string) {
+	first := *record
+	message := record.Field("message")
+
+	lines := strings.Split(strings.TrimRight(message, "\n"), "\n")
+	first.Message = lines[0]
+	details := p.fold(lines[1:], "    ")
+
+	var keys []string
+	for key := range record.Fields {
+		if key == messageKey(record.Fields) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		details = append(details, p.field(key, record.Fields[key], "    ")...)
+	}
+	return &first, details
+}
+
+// messageKey returns the field holding the message text, as read by Record.Field.
+func messageKey(fields map[string]interface{}) string {
+	if _, ok := fields["message"]; ok {
+		return "message"
+	}
+	if _, ok := fields["msg"]; ok {
+		return "msg"
+	}
+	return ""
+}
+
+// field formats one key and value, with objects expanded one level deeper per nesting.
+func (p *Printer) field(key string, value interface{}, indent string) []string {
+	name := indent + p.cs.Blue(key+":")
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return []string{name + " {}"}
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		lines := []string{name}
+		for _, k := range keys {
+			lines = append(lines, p.field(k, v[k], indent+"  ")...)
+		}
+		return lines
+	case string:
+		if strings.Contains(v, "\n") {
+			return append([]string{name}, p.fold(strings.Split(strings.TrimRight(v, "\n"), "\n"), indent+"  ")...)
+		}
+		// Highlighted values are not painted green, as the end of a highlight resets the colour.
+		if highlighted := p.highlight(v); highlighted != v {
+			return []string{name + " " + highlighted}
+		}
+		return []string{name + " " + p.cs.Green(v)}
+	case nil:
+		return []string{name + " " + p.cs.Gray("null")}
+	case float64, bool:
+		return []string{name + " " + p.cs.Magenta(valueString(v))}
+	default:
+		return []string{name + " " + valueString(v)}
+	}
+}
+
+// fold indents lines and replaces those past maxFoldedLines with a count.
+func (p *Printer) fold(lines []string, indent string) []string {
+	var folded []string
+	for i, line := range lines {
+		if i == maxFoldedLines {
+			folded = append(folded, indent+p.cs.Grayf("… %d more lines", len(lines)-i))
+			break
+		}
+		folded = append(folded, indent+p.cs.Gray("| ")+p.highlight(line))
+	}
+	return folded
+}
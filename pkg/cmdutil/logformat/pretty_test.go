@@ -0,0 +1,81 @@
+package logformat
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/logfire-sh/cli/pkg/iostreams"
+	pb "github.com/logfire-sh/cli/services/flink-service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeverity(t *testing.T) {
+	tests := []struct {
+		level string
+		wants pb.SeverityLevel
+	}{
+		{level: "error", wants: pb.SeverityLevel_ERROR},
+		{level: "WARN", wants: pb.SeverityLevel_WARNING},
+		{level: " Critical ", wants: pb.SeverityLevel_CRITICAL},
+		{level: "emerg", wants: pb.SeverityLevel_ALERT},
+		{level: "", wants: pb.SeverityLevel_INFO},
+		{level: "verbose", wants: pb.SeverityLevel_INFO},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			assert.Equal(t, tt.wants, Severity(tt.level))
+		})
+	}
+}
+
+func TestPrinterPretty(t *testing.T) {
+	trace := "panic: boom"
+	for i := 1; i <= 10; i++ {
+		trace += "\n\tframe " + strings.Repeat("x", i)
+	}
+
+	records := []*pb.FilteredRecord{
+		{Dt: "2024-05-01T12:00:00Z", Level: "error", SourceName: "api",
+			Message: `{"msg":"request failed","status":500,"ok":false,"user":{"id":"u-42","tags":null}}`},
+		{Dt: "2024-05-01T12:00:01Z", Level: "fatal", SourceName: "api",
+			Message: `level=fatal msg=crashed stack="` + strings.ReplaceAll(trace, "\n", `\n`) + `"`},
+		{Dt: "2024-05-01T12:00:02Z", Level: "info", SourceName: "worker", Message: "job done"},
+	}
+
+	printer, err := NewPrinter(iostreams.NewColorScheme(false, false, false), "", nil, true)
+	assert.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, printer.Print(out, records))
+	assert.Equal(t, ""+
+		"2024-05-01T12:00:00Z api [ERROR] request failed\n"+
+		"    ok: false\n"+
+		"    status: 500\n"+
+		"    user:\n"+
+		"      id: u-42\n"+
+		"      tags: null\n"+
+		"2024-05-01T12:00:01Z api [FATAL] crashed\n"+
+		"    level: fatal\n"+
+		"    stack:\n"+
+		"      | panic: boom\n"+
+		"      | \tframe x\n"+
+		"      | \tframe xx\n"+
+		"      | \tframe xxx\n"+
+		"      | \tframe xxxx\n"+
+		"      | \tframe xxxxx\n"+
+		"      | \tframe xxxxxx\n"+
+		"      | \tframe xxxxxxx\n"+
+		"      … 3 more lines\n"+
+		"2024-05-01T12:00:02Z worker [INFO] job done\n", out.String())
+}
+
+func TestPrinterLevelColors(t *testing.T) {
+	printer, err := NewPrinter(iostreams.NewColorScheme(true, false, false), "{{level .Level}}", nil, false)
+	assert.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, printer.Print(out, []*pb.FilteredRecord{{Level: "warn"}, {Level: "error"}, {Level: "debug"}}))
+	assert.Equal(t, "\x1b[0;33mWARN\x1b[0m\n\x1b[0;31mERROR\x1b[0m\n\x1b[0;90mDEBUG\x1b[0m\n", out.String())
+}
@@ -0,0 +1,274 @@
+package logformat
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/logfire-sh/cli/internal/text"
+	"github.com/logfire-sh/cli/pkg/cmdutil/grpcutil"
+	pb "github.com/logfire-sh/cli/services/flink-service"
+)
+
+// rateWindow is how many seconds the records per second of a source are averaged over.
+const rateWindow = 5
+
+// Stats counts the records of a stream for tail --stats. It is safe for concurrent use, as the
+// stream and the refresh of the status line run on different goroutines.
+type Stats struct {
+	mu sync.Mutex
+
+	started    time.Time
+	sources    []*sourceStats
+	levels     map[pb.SeverityLevel]int
+	matched    int
+	reconnects int
+}
+
+type sourceStats struct {
+	// key is the grpcutil.SourceKey of the source, as names need not be unique.
+	key   string
+	name  string
+	total int
+	lag   time.Duration
+	// buckets count the records of the last rateWindow seconds, by second.
+	buckets [rateWindow]int
+	seconds [rateWindow]int64
+}
+
+// NewStats starts counting at now. sources are the sources of the stream, so that sources without
+// any record show too.
+func NewStats(sources []*pb.Source, now time.Time) *Stats {
+	s := &Stats{started: now, levels: map[pb.SeverityLevel]int{}}
+	for _, source := range sources {
+		s.source(source.SourceID, source.SourceName)
+	}
+	return s
+}
+
+// source returns the stats of the source sourceID, shown as name.
+func (s *Stats) source(sourceID, name string) *sourceStats {
+	key := grpcutil.SourceKey(sourceID)
+	for _, source := range s.sources {
+		if source.key == key {
+			return source
+		}
+	}
+	source := &sourceStats{key: key, name: name}
+	s.sources = append(s.sources, source)
+	return source
+}
+
+// Add counts the records received at now, of which matched were printed.
+func (s *Stats) Add(records []*pb.FilteredRecord, matched int, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.matched += matched
+	second := now.Unix()
+	for _, record := range records {
+		source := s.source(record.SourceID, record.SourceName)
+		source.total++
+
+		i := second % rateWindow
+		if source.seconds[i] != second {
+			source.seconds[i], source.buckets[i] = second, 0
+		}
+		source.buckets[i]++
+
+		if t, ok := grpcutil.RecordTime(record); ok {
+			source.lag = now.Sub(t)
+		}
+		s.levels[Severity(record.Level)]++
+	}
+}
+
+// Reconnect counts a reconnect of the stream.
+func (s *Stats) Reconnect() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reconnects++
+}
+
+// rate returns the records per second of source over the last rateWindow seconds before now.
+func (source *sourceStats) rate(now time.Time) float64 {
+	count := 0
+	for i, second := range source.seconds {
+		if age := now.Unix() - second; age >= 0 && age < rateWindow {
+			count += source.buckets[i]
+		}
+	}
+	return float64(count) / rateWindow
+}
+
+// Rate returns the records per second of all the sources over the last rateWindow seconds.
+func (s *Stats) Rate(now time.Time) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rate := 0.0
+	for _, source := range s.sources {
+		rate += source.rate(now)
+	}
+	return rate
+}
+
+// levelCounts lists the levels seen, from the least to the most severe.
+func (s *Stats) levelCounts() string {
+	var levels []pb.SeverityLevel
+	for level := range s.levels {
+		levels = append(levels, level)
+	}
+	sort.Slice(levels, func(i, j int) bool { return severityRank(levels[i]) < severityRank(levels[j]) })
+
+	var counts []string
+	for _, level := range levels {
+		counts = append(counts, fmt.Sprintf("%s %d", level, s.levels[level]))
+	}
+	if len(counts) == 0 {
+		return "no logs"
+	}
+	return strings.Join(counts, " ")
+}
+
+// severityRank orders the SeverityLevel values, whose numbers do not follow their severity.
+func severityRank(level pb.SeverityLevel) int {
+	for i, l := range []pb.SeverityLevel{
+		pb.SeverityLevel_TRACE, pb.SeverityLevel_DEBUG, pb.SeverityLevel_INFO, pb.SeverityLevel_INFORMATIONAL,
+		pb.SeverityLevel_NOTICE, pb.SeverityLevel_WARNING, pb.SeverityLevel_ERROR, pb.SeverityLevel_CRITICAL,
+		pb.SeverityLevel_ALERT, pb.SeverityLevel_FATAL,
+	} {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
+
+// Line formats the statistics at now on a single line, for the status line of a terminal.
+func (s *Stats) Line(now time.Time) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rates []string
+	var lag time.Duration
+	for _, source := range s.sources {
+		rates = append(rates, fmt.Sprintf("%s %.1f/s", source.name, source.rate(now)))
+		if source.total > 0 && source.lag > lag {
+			lag = source.lag
+		}
+	}
+
+	return fmt.Sprintf("%s | %s | matched %d | lag %s | reconnects %d",
+		strings.Join(rates, " "), s.levelCounts(), s.matched, formatLag(lag), s.reconnects)
+}
+
+// Summary formats the statistics of the whole stream at now, one source per line.
+func (s *Stats) Summary(now time.Time) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elapsed := now.Sub(s.started)
+	var b strings.Builder
+	fmt.Fprintf(&b, "Stats after %s:\n", elapsed.Round(time.Second))
+
+	width := 0
+	for _, source := range s.sources {
+		if w := text.DisplayWidth(source.name); w > width {
+			width = w
+		}
+	}
+	for _, source := range s.sources {
+		rate := 0.0
+		if elapsed > 0 {
+			rate = float64(source.total) / elapsed.Seconds()
+		}
+		fmt.Fprintf(&b, "  %s%s  %6d logs  %6.1f/s", source.name, strings.Repeat(" ", width-text.DisplayWidth(source.name)), source.total, rate)
+		if source.total > 0 {
+			fmt.Fprintf(&b, "  lag %s", formatLag(source.lag))
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "  levels: %s\n", s.levelCounts())
+	fmt.Fprintf(&b, "  matched %d, reconnects %d\n", s.matched, s.reconnects)
+	return b.String()
+}
+
+func formatLag(lag time.Duration) string {
+	switch {
+	case lag <= 0:
+		return "-"
+	case lag < time.Second:
+		return lag.Round(time.Millisecond).String()
+	default:
+		return lag.Round(100 * time.Millisecond).String()
+	}
+}
+
+// StatusLine keeps a line of text at the bottom of a terminal, below the output printed with Print.
+type StatusLine struct {
+	mu    sync.Mutex
+	w     io.Writer
+	width int
+	text  string
+}
+
+// NewStatusLine draws on w, a terminal width columns wide.
+func NewStatusLine(w io.Writer, width int) *StatusLine {
+	return &StatusLine{w: w, width: width}
+}
+
+// Set replaces the text of the line, cut to the width of the terminal so that it never wraps.
+func (l *StatusLine) Set(line string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.text = text.Truncate(l.width-1, line)
+	fmt.Fprintf(l.w, "\r\x1b[K%s", l.text)
+}
+
+// Print clears the line, runs print and draws the line again below what print wrote.
+func (l *StatusLine) Print(print func() error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	fmt.Fprint(l.w, "\r\x1b[K")
+	err := print()
+	fmt.Fprint(l.w, l.text)
+	return err
+}
+
+// Refresh sets the line to line(now) every interval until stop is called, which also clears it.
+func (l *StatusLine) Refresh(interval time.Duration, line func(now time.Time) string) (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	l.Set(line(time.Now()))
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				l.Set(line(now))
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		fmt.Fprint(l.w, "\r\x1b[K")
+		l.text = ""
+	}
+}
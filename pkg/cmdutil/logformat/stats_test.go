@@ -0,0 +1,89 @@
+package logformat
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/logfire-sh/cli/services/flink-service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+	ids := map[string]string{"api": "1f0c", "worker": "5d80", "cron": "6e31"}
+	record := func(source, level string, dt time.Time) *pb.FilteredRecord {
+		return &pb.FilteredRecord{SourceID: ids[source], SourceName: source, Level: level, Dt: dt.Format(time.RFC3339Nano)}
+	}
+
+	sources := []*pb.Source{{SourceID: "source_topic_1f0c", SourceName: "api"}, {SourceID: "source_topic_5d80", SourceName: "worker"}}
+	stats := NewStats(sources, start)
+	assert.Equal(t, "api 0.0/s worker 0.0/s | no logs | matched 0 | lag - | reconnects 0", stats.Line(start))
+
+	stats.Add([]*pb.FilteredRecord{
+		record("api", "info", at(-500*time.Millisecond)),
+		record("api", "error", at(-500*time.Millisecond)),
+		record("api", "warn", at(-500*time.Millisecond)),
+	}, 2, at(time.Second))
+	stats.Add([]*pb.FilteredRecord{
+		record("api", "info", at(2*time.Second)),
+		record("cron", "debug", at(time.Second)),
+	}, 2, at(2500*time.Millisecond))
+	stats.Reconnect()
+
+	assert.Equal(t, "api 0.8/s worker 0.0/s cron 0.2/s | DEBUG 1 INFO 2 WARNING 1 ERROR 1 | matched 4 | lag 1.5s | reconnects 1",
+		stats.Line(at(3*time.Second)))
+	assert.InDelta(t, 1.0, stats.Rate(at(3*time.Second)), 1e-9)
+	// The records of api fall out of the rate window after rateWindow seconds.
+	assert.Contains(t, stats.Line(at(6*time.Second)), "api 0.2/s")
+
+	assert.Equal(t, strings.Join([]string{
+		"Stats after 10s:",
+		"  api          4 logs     0.4/s  lag 500ms",
+		"  worker       0 logs     0.0/s",
+		"  cron         1 logs     0.1/s  lag 1.5s",
+		"  levels: DEBUG 1 INFO 2 WARNING 1 ERROR 1",
+		"  matched 4, reconnects 1",
+		"",
+	}, "\n"), stats.Summary(at(10*time.Second)))
+}
+
+func TestStatsSameName(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	sources := []*pb.Source{{SourceID: "source_topic_3b19", SourceName: "edge"}, {SourceID: "source_topic_4c22", SourceName: "edge"}}
+	stats := NewStats(sources, start)
+
+	stats.Add([]*pb.FilteredRecord{
+		{SourceID: "source_topic_3b19", SourceName: "edge"},
+		{SourceID: "source_topic_3b19", SourceName: "edge"},
+		{SourceID: "source_topic_4c22", SourceName: "edge"},
+	}, 3, start.Add(time.Second))
+
+	assert.Equal(t, strings.Join([]string{
+		"Stats after 5s:",
+		"  edge       2 logs     0.4/s  lag -",
+		"  edge       1 logs     0.2/s  lag -",
+		"  levels: INFO 3",
+		"  matched 3, reconnects 0",
+		"",
+	}, "\n"), stats.Summary(start.Add(5*time.Second)))
+}
+
+func TestStatusLine(t *testing.T) {
+	var out bytes.Buffer
+	status := NewStatusLine(&out, 12)
+
+	status.Set("api 1.0/s | INFO 3")
+	assert.Equal(t, "\r\x1b[Kapi 1.0/...", out.String())
+
+	out.Reset()
+	err := status.Print(func() error {
+		out.WriteString("a log\n")
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "\r\x1b[Ka log\napi 1.0/...", out.String())
+}
@@ -2,21 +2,19 @@ package pre_defined_prompters
 
 import (
 	"fmt"
-	"net/http"
 	"os"
 
-	"github.com/logfire-sh/cli/internal/config"
+	"github.com/logfire-sh/cli/api"
 	"github.com/logfire-sh/cli/internal/prompter"
 	"github.com/logfire-sh/cli/pkg/cmd/alerts/models"
 	IntegrationModels "github.com/logfire-sh/cli/pkg/cmd/integrations/models"
 	SourceModels "github.com/logfire-sh/cli/pkg/cmd/sources/models"
 	MemberModels "github.com/logfire-sh/cli/pkg/cmd/teams/models"
-	"github.com/logfire-sh/cli/pkg/cmdutil/APICalls"
 	"github.com/logfire-sh/cli/pkg/iostreams"
 )
 
-func AskTeamId(client *http.Client, cfg config.Config, io *iostreams.IOStreams, cs *iostreams.ColorScheme, prompter prompter.Prompter) (string, error) {
-	teamsList, err := APICalls.ListTeams(client, cfg.Get().Token, cfg.Get().EndPoint)
+func AskTeamId(client *api.Client, io *iostreams.IOStreams, cs *iostreams.ColorScheme, prompter prompter.Prompter) (string, error) {
+	teamsList, err := client.ListTeams()
 	if err != nil {
 		fmt.Fprintf(io.ErrOut, "%s %s\n", cs.FailureIcon(), err)
 		os.Exit(1)
@@ -57,12 +55,12 @@ func AskTeamId(client *http.Client, cfg config.Config, io *iostreams.IOStreams,
 	return fullTeamId, nil
 }
 
-func AskAlertIntegrationIds(client *http.Client, cfg config.Config, io *iostreams.IOStreams, cs *iostreams.ColorScheme, prompter prompter.Prompter, teamId string) ([]string, error) {
+func AskAlertIntegrationIds(client *api.Client, io *iostreams.IOStreams, cs *iostreams.ColorScheme, prompter prompter.Prompter, teamId string) ([]string, error) {
 	var integrationsIdNames []string
 	var integrationsList []models.AlertIntegrationBody
 	var idMap map[string]string = make(map[string]string)
 
-	integrationsList, err := APICalls.GetAlertIntegrations(client, cfg.Get().Token, cfg.Get().EndPoint, teamId)
+	integrationsList, err := client.ListAlertIntegrations(teamId)
 	if err != nil {
 		fmt.Fprintf(io.ErrOut, "%s Failed to Get Integrations list\n", cs.FailureIcon())
 		return []string{}, err
@@ -104,8 +102,8 @@ func AskAlertIntegrationIds(client *http.Client, cfg config.Config, io *iostream
 	return fullIntegrationIds, nil
 }
 
-func AskViewId(client *http.Client, cfg config.Config, io *iostreams.IOStreams, cs *iostreams.ColorScheme, prompter prompter.Prompter, teamId string) (string, error) {
-	ViewsList, err := APICalls.ListView(cfg.Get().Token, cfg.Get().EndPoint, teamId)
+func AskViewId(client *api.Client, io *iostreams.IOStreams, cs *iostreams.ColorScheme, prompter prompter.Prompter, teamId string) (string, error) {
+	ViewsList, err := client.ListViews(teamId)
 	if err != nil {
 		fmt.Fprintf(io.ErrOut, "%s Failed to Get Views list\n", cs.FailureIcon())
 		return "", err
@@ -146,12 +144,12 @@ func AskViewId(client *http.Client, cfg config.Config, io *iostreams.IOStreams,
 	return fullViewId, nil
 }
 
-func AskAlertIds(client *http.Client, cfg config.Config, io *iostreams.IOStreams, cs *iostreams.ColorScheme, prompter prompter.Prompter, teamId string) ([]string, error) {
+func AskAlertIds(client *api.Client, io *iostreams.IOStreams, cs *iostreams.ColorScheme, prompter prompter.Prompter, teamId string) ([]string, error) {
 	var alertIdNames []string
 	var alertsList []models.CreateAlertBody
 	var idMap map[string]string = make(map[string]string)
 
-	alertsList, err := APICalls.ListAlert(client, cfg.Get().Token, cfg.Get().EndPoint, teamId)
+	alertsList, err := client.ListAlerts(teamId)
 	if err != nil {
 		fmt.Fprintf(io.ErrOut, "%s Failed to Get Alerts list\n", cs.FailureIcon())
 		return []string{}, err
@@ -193,12 +191,12 @@ func AskAlertIds(client *http.Client, cfg config.Config, io *iostreams.IOStreams
 	return fullAlertIds, nil
 }
 
-func AskAlertId(client *http.Client, cfg config.Config, io *iostreams.IOStreams, cs *iostreams.ColorScheme, prompter prompter.Prompter, teamId string) (string, error) {
+func AskAlertId(client *api.Client, io *iostreams.IOStreams, cs *iostreams.ColorScheme, prompter prompter.Prompter, teamId string) (string, error) {
 	var alertIdNames []string
 	var alertsList []models.CreateAlertBody
 	var idMap map[string]string = make(map[string]string)
 
-	alertsList, err := APICalls.ListAlert(client, cfg.Get().Token, cfg.Get().EndPoint, teamId)
+	alertsList, err := client.ListAlerts(teamId)
 	if err != nil {
 		fmt.Fprintf(io.ErrOut, "%s Failed to Get Alerts list\n", cs.FailureIcon())
 		return "", err
@@ -236,12 +234,12 @@ func AskAlertId(client *http.Client, cfg config.Config, io *iostreams.IOStreams,
 	return fullAlertId, nil
 }
 
-func AskIntegrationId(client *http.Client, cfg config.Config, io *iostreams.IOStreams, cs *iostreams.ColorScheme, prompter prompter.Prompter, teamId string) (string, error) {
+func AskIntegrationId(client *api.Client, io *iostreams.IOStreams, cs *iostreams.ColorScheme, prompter prompter.Prompter, teamId string) (string, error) {
 	var integrationsIdNames []string
 	var integrationsList []IntegrationModels.IntegrationBody
 	var idMap map[string]string = make(map[string]string)
 
-	integrationsList, err := APICalls.GetIntegrationsList(client, cfg.Get().Token, cfg.Get().EndPoint, teamId)
+	integrationsList, err := client.ListIntegrations(teamId)
 	if err != nil {
 		fmt.Fprintf(io.ErrOut, "%s Failed to Get Integrations list\n", cs.FailureIcon())
 		return "", err
@@ -280,13 +278,13 @@ func AskIntegrationId(client *http.Client, cfg config.Config, io *iostreams.IOSt
 
 }
 
-func AskSourceId(client *http.Client, cfg config.Config, io *iostreams.IOStreams, cs *iostreams.ColorScheme, prompter prompter.Prompter, teamId string) (string, error) {
+func AskSourceId(client *api.Client, io *iostreams.IOStreams, cs *iostreams.ColorScheme, prompter prompter.Prompter, teamId string) (string, error) {
 	idMap := make(map[string]string)
 
 	var sourceIdNames []string
 	var sourceList []SourceModels.Source
 
-	sourceList, err := APICalls.GetAllSources(client, cfg.Get().Token, cfg.Get().EndPoint, teamId)
+	sourceList, err := client.ListSources(teamId)
 	if err != nil {
 		fmt.Fprintf(io.ErrOut, "%s Failed to Get Alerts list\n", cs.FailureIcon())
 		return "", err
@@ -323,12 +321,12 @@ func AskSourceId(client *http.Client, cfg config.Config, io *iostreams.IOStreams
 	return fullID, nil
 }
 
-func AskSourceIds(client *http.Client, cfg config.Config, io *iostreams.IOStreams, cs *iostreams.ColorScheme, prompter prompter.Prompter, teamId string) ([]string, error) {
+func AskSourceIds(client *api.Client, io *iostreams.IOStreams, cs *iostreams.ColorScheme, prompter prompter.Prompter, teamId string) ([]string, error) {
 	var sourceIdNames []string
 	var sourceList []SourceModels.Source
 	var idMap map[string]string = make(map[string]string)
 
-	sourceList, err := APICalls.GetAllSources(client, cfg.Get().Token, cfg.Get().EndPoint, teamId)
+	sourceList, err := client.ListSources(teamId)
 	if err != nil {
 		fmt.Fprintf(io.ErrOut, "%s Failed to Get Alerts list\n", cs.FailureIcon())
 		return []string{}, err
@@ -370,12 +368,12 @@ func AskSourceIds(client *http.Client, cfg config.Config, io *iostreams.IOStream
 	return fullSourceIds, nil
 }
 
-func AskMemberId(client *http.Client, cfg config.Config, io *iostreams.IOStreams, cs *iostreams.ColorScheme, prompter prompter.Prompter, teamId string) (string, error) {
+func AskMemberId(client *api.Client, io *iostreams.IOStreams, cs *iostreams.ColorScheme, prompter prompter.Prompter, teamId string) (string, error) {
 	var MemberIdNames []string
 	var membersList []MemberModels.TeamMemberRes
 	var idMap map[string]string = make(map[string]string)
 
-	membersList, err := APICalls.MembersList(client, cfg.Get().Token, cfg.Get().EndPoint, teamId)
+	membersList, err := client.ListMembers(teamId)
 	if err != nil {
 		fmt.Fprintf(io.ErrOut, "%s Failed to Get Alerts list\n", cs.FailureIcon())
 		return "", err
@@ -0,0 +1,191 @@
+// Package sourceref resolves the sources given to flags such as --source-id by ID, name, glob or
+// platform, and completes them in the shell.
+package sourceref
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/logfire-sh/cli/api"
+	"github.com/logfire-sh/cli/pkg/cmd/sources/models"
+	"github.com/logfire-sh/cli/pkg/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+// maxListed caps the sources named in an error, so that large teams do not flood the terminal.
+const maxListed = 10
+
+// Help describes the forms of a reference, for the help of the flags taking sources.
+const Help = "given by ID, name, glob such as 'api-*' or selector such as platform=nginx"
+
+// Resolve returns the sources of a team selected by refs, in order and without duplicates. A ref is
+// the ID or the name of a source, a glob such as api-* matching names, or platform=<platform>
+// selecting every source of a platform. A name shared by several sources is ambiguous, as is a ref
+// selecting nothing.
+func Resolve(sources []models.Source, refs []string) ([]models.Source, error) {
+	var resolved []models.Source
+	seen := map[string]bool{}
+	for _, ref := range refs {
+		matches, err := match(sources, ref)
+		if err != nil {
+			return nil, err
+		}
+		for _, source := range matches {
+			if !seen[source.ID] {
+				seen[source.ID] = true
+				resolved = append(resolved, source)
+			}
+		}
+	}
+	return resolved, nil
+}
+
+// ResolveOne returns the single source selected by ref, for commands acting on one source.
+func ResolveOne(sources []models.Source, ref string) (models.Source, error) {
+	matches, err := match(sources, ref)
+	if err != nil {
+		return models.Source{}, err
+	}
+	if len(matches) > 1 {
+		return models.Source{}, fmt.Errorf("%q matches %d sources: %s; give a single source", ref, len(matches), list(matches))
+	}
+	return matches[0], nil
+}
+
+func match(sources []models.Source, ref string) ([]models.Source, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, fmt.Errorf("source must not be empty")
+	}
+
+	for _, source := range sources {
+		if source.ID == ref {
+			return []models.Source{source}, nil
+		}
+	}
+
+	var matches []models.Source
+	switch {
+	case strings.Contains(ref, "="):
+		key, value, _ := strings.Cut(ref, "=")
+		if strings.TrimSpace(key) != "platform" {
+			return nil, fmt.Errorf("invalid source selector %q, expected platform=<platform>", ref)
+		}
+		for _, source := range sources {
+			if strings.EqualFold(source.Platform, strings.TrimSpace(value)) {
+				matches = append(matches, source)
+			}
+		}
+	case strings.ContainsAny(ref, "*?["):
+		if _, err := path.Match(ref, ""); err != nil {
+			return nil, fmt.Errorf("invalid source pattern %q: %w", ref, err)
+		}
+		for _, source := range sources {
+			if ok, _ := path.Match(ref, source.Name); ok {
+				matches = append(matches, source)
+			}
+		}
+	default:
+		for _, source := range sources {
+			if source.Name == ref {
+				matches = append(matches, source)
+			}
+		}
+		if len(matches) > 1 {
+			return nil, fmt.Errorf("source name %q is ambiguous, give one of the IDs instead: %s", ref, list(matches))
+		}
+	}
+
+	if len(matches) == 0 {
+		if len(sources) == 0 {
+			return nil, fmt.Errorf("no source matches %q, the team has no sources", ref)
+		}
+		return nil, fmt.Errorf("no source matches %q, the team has: %s", ref, names(sources))
+	}
+	return matches, nil
+}
+
+// list names sources with their IDs and platforms, to tell apart sources of the same name.
+func list(sources []models.Source) string {
+	var items []string
+	for i, source := range sources {
+		if i == maxListed {
+			items = append(items, fmt.Sprintf("and %d more", len(sources)-i))
+			break
+		}
+		items = append(items, fmt.Sprintf("%s (%s, %s)", source.Name, source.ID, source.Platform))
+	}
+	return strings.Join(items, ", ")
+}
+
+func names(sources []models.Source) string {
+	var items []string
+	for i, source := range sources {
+		if i == maxListed {
+			items = append(items, fmt.Sprintf("and %d more", len(sources)-i))
+			break
+		}
+		items = append(items, source.Name)
+	}
+	return strings.Join(items, ", ")
+}
+
+// Candidates returns the completions of toComplete: the names of sources, with their platform as
+// description, and the platform selectors.
+func Candidates(sources []models.Source, toComplete string) []string {
+	var candidates []string
+	platforms := map[string]bool{}
+	for _, source := range sources {
+		if strings.HasPrefix(source.Name, toComplete) {
+			candidates = append(candidates, source.Name+"\t"+source.Platform)
+		}
+		platforms[source.Platform] = true
+	}
+
+	var selectors []string
+	for platform := range platforms {
+		if selector := "platform=" + platform; platform != "" && strings.HasPrefix(selector, toComplete) {
+			selectors = append(selectors, selector)
+		}
+	}
+	sort.Strings(selectors)
+	return append(candidates, selectors...)
+}
+
+// CompletionFunc completes the sources of the team given to teamFlag, by name or ID, or else of
+// the team of the config, for cobra.Command.RegisterFlagCompletionFunc. Nothing is completed
+// without a team.
+func CompletionFunc(f *cmdutil.Factory, teamFlag string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		cfg, err := f.Config()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		team, _ := cmd.Flags().GetString(teamFlag)
+		if team == "" {
+			team = cfg.Get().TeamId
+		}
+		if team == "" {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		client := api.NewClientFromConfig(f.HttpClient(), cfg)
+
+		teams, err := client.ListTeams()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		for _, t := range teams {
+			if t.ID == team || t.Name == team {
+				sources, err := client.ListSources(t.ID)
+				if err != nil {
+					return nil, cobra.ShellCompDirectiveError
+				}
+				return Candidates(sources, toComplete), cobra.ShellCompDirectiveNoFileComp
+			}
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
@@ -0,0 +1,140 @@
+package sourceref
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/logfire-sh/cli/internal/config"
+	"github.com/logfire-sh/cli/pkg/cmd/sources/models"
+	"github.com/logfire-sh/cli/pkg/cmdutil"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var teamSources = []models.Source{
+	{ID: "1f0c", Name: "api-eu", Platform: "go"},
+	{ID: "2a7d", Name: "api-us", Platform: "go"},
+	{ID: "3b19", Name: "edge", Platform: "nginx"},
+	{ID: "4c22", Name: "edge", Platform: "nginx"},
+	{ID: "5d80", Name: "worker", Platform: "kubernetes"},
+}
+
+func ids(sources []models.Source) []string {
+	var ids []string
+	for _, source := range sources {
+		ids = append(ids, source.ID)
+	}
+	return ids
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name    string
+		refs    []string
+		ids     []string
+		wantErr string
+	}{
+		{name: "id", refs: []string{"5d80"}, ids: []string{"5d80"}},
+		{name: "name", refs: []string{"worker"}, ids: []string{"5d80"}},
+		{name: "glob", refs: []string{"api-*"}, ids: []string{"1f0c", "2a7d"}},
+		{name: "platform", refs: []string{"platform=NGINX"}, ids: []string{"3b19", "4c22"}},
+		{name: "without duplicates", refs: []string{"api-eu", "api-*", "1f0c"}, ids: []string{"1f0c", "2a7d"}},
+		{name: "id of a shared name", refs: []string{"4c22"}, ids: []string{"4c22"}},
+		{
+			name:    "ambiguous name",
+			refs:    []string{"edge"},
+			wantErr: `source name "edge" is ambiguous, give one of the IDs instead: edge (3b19, nginx), edge (4c22, nginx)`,
+		},
+		{
+			name:    "no match",
+			refs:    []string{"db-*"},
+			wantErr: `no source matches "db-*", the team has: api-eu, api-us, edge, edge, worker`,
+		},
+		{name: "unknown selector", refs: []string{"team=ops"}, wantErr: `invalid source selector "team=ops", expected platform=<platform>`},
+		{name: "invalid glob", refs: []string{"api-["}, wantErr: `invalid source pattern "api-["`},
+		{name: "empty", refs: []string{" "}, wantErr: "source must not be empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sources, err := Resolve(teamSources, tt.refs)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.ids, ids(sources))
+		})
+	}
+}
+
+func TestResolveOne(t *testing.T) {
+	source, err := ResolveOne(teamSources, "api-e*")
+	require.NoError(t, err)
+	assert.Equal(t, "1f0c", source.ID)
+
+	_, err = ResolveOne(teamSources, "platform=go")
+	assert.EqualError(t, err, `"platform=go" matches 2 sources: api-eu (1f0c, go), api-us (2a7d, go); give a single source`)
+
+	_, err = ResolveOne(nil, "api")
+	assert.EqualError(t, err, `no source matches "api", the team has no sources`)
+}
+
+func TestCandidates(t *testing.T) {
+	assert.Equal(t, []string{"api-eu\tgo", "api-us\tgo"}, Candidates(teamSources, "api"))
+	assert.Equal(t, []string{"platform=go", "platform=kubernetes", "platform=nginx"}, Candidates(teamSources, "pl"))
+	assert.Len(t, Candidates(teamSources, ""), 8)
+}
+
+func TestCompletionFunc(t *testing.T) {
+	tests := []struct {
+		name       string
+		teamFlag   string
+		configTeam string
+		want       []string
+	}{
+		{name: "team flag", teamFlag: "backend", want: []string{"api-eu\tgo", "api-us\tgo"}},
+		{name: "team of the config", configTeam: "t-1", want: []string{"api-eu\tgo", "api-us\tgo"}},
+		{name: "team flag over the config", teamFlag: "frontend", configTeam: "t-1"},
+		{name: "no team"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/team":
+					fmt.Fprint(w, `{"isSuccessful":true,"data":[{"id":"t-1","name":"backend"},{"id":"t-2","name":"frontend"}]}`)
+				case "/api/team/t-1/source":
+					data, _ := json.Marshal(teamSources)
+					fmt.Fprintf(w, `{"isSuccessful":true,"data":%s}`, data)
+				default:
+					fmt.Fprint(w, `{"isSuccessful":true,"data":[]}`)
+				}
+			}))
+			defer server.Close()
+
+			cfg, err := config.Load(config.Options{Path: filepath.Join(t.TempDir(), ".logfire")})
+			require.NoError(t, err)
+			require.NoError(t, cfg.UpdateConfig(func(c *config.AuthConfig) {
+				c.EndPoint = server.URL + "/"
+				c.TeamId = tt.configTeam
+			}))
+			f := &cmdutil.Factory{
+				HttpClient: func() *http.Client { return server.Client() },
+				Config:     func() (config.Config, error) { return cfg, nil },
+			}
+			cmd := &cobra.Command{}
+			cmd.Flags().String("team-name", tt.teamFlag, "")
+
+			candidates, directive := CompletionFunc(f, "team-name")(cmd, nil, "api")
+			assert.Equal(t, tt.want, candidates)
+			assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/gdamore/tcell/v2"
+	"github.com/logfire-sh/cli/internal/theme"
 	"github.com/mgutz/ansi"
 )
 
@@ -19,10 +21,13 @@ var (
 	bold      = ansi.ColorFunc("default+b")
 	cyanBold  = ansi.ColorFunc("cyan+b")
 	greenBold = ansi.ColorFunc("green+b")
+	highlight = ansi.ColorFunc("black:yellow")
 
 	gray256 = func(t string) string {
 		return fmt.Sprintf("\x1b[%d;5;%dm%s\x1b[m", 38, 242, t)
 	}
+
+	defaultPalette, _ = theme.Builtin(theme.Dark)
 )
 
 func NewColorScheme(enabled, is256enabled bool, trueColor bool) *ColorScheme {
@@ -37,6 +42,8 @@ type ColorScheme struct {
 	enabled      bool
 	is256enabled bool
 	hasTrueColor bool
+	// palette colours the parts of log lines, the built-in dark palette when nil.
+	palette *theme.Palette
 }
 
 func (c *ColorScheme) Bold(t string) string {
@@ -144,6 +151,14 @@ func (c *ColorScheme) Bluef(t string, args ...interface{}) string {
 	return c.Blue(fmt.Sprintf(t, args...))
 }
 
+// Highlight marks text matched by a search, such as the --grep patterns of tail.
+func (c *ColorScheme) Highlight(t string) string {
+	if !c.enabled {
+		return t
+	}
+	return highlight(t)
+}
+
 func (c *ColorScheme) SuccessIcon() string {
 	return c.SuccessIconWithColor(c.Green)
 }
@@ -216,3 +231,98 @@ func (c *ColorScheme) HexToRGB(hex string, x string) string {
 	b, _ := strconv.ParseInt(hex[4:6], 16, 64)
 	return fmt.Sprintf("\033[38;2;%d;%d;%dm%s\033[0m", r, g, b, x)
 }
+
+func (c *ColorScheme) getPalette() *theme.Palette {
+	if c.palette == nil {
+		return defaultPalette
+	}
+	return c.palette
+}
+
+// Timestamp colours the time of a log line with the palette of the theme.
+func (c *ColorScheme) Timestamp(t string) string {
+	return c.Paint(c.getPalette().Timestamp, t)
+}
+
+// Source colours the source name of a log line with the palette of the theme.
+func (c *ColorScheme) Source(t string) string {
+	return c.Paint(c.getPalette().Source, t)
+}
+
+// Level colours t with the colour of a severity, such as "warning", in the palette of the theme.
+func (c *ColorScheme) Level(severity, t string) string {
+	return c.Paint(c.getPalette().Level(severity), t)
+}
+
+// Paint colours t with a colour of a theme. The basic colours are those of the other methods;
+// any other colour needs a terminal supporting true colours, or 256 colours for the colours of
+// the xterm palette, and is left out otherwise.
+func (c *ColorScheme) Paint(color theme.Color, t string) string {
+	if !c.enabled {
+		return t
+	}
+
+	paint := func(s string) string { return s }
+	switch name := color.Name(); name {
+	case "":
+	case "red":
+		paint = c.Red
+	case "yellow":
+		paint = c.Yellow
+	case "green":
+		paint = c.Green
+	case "gray", "grey":
+		paint = c.Gray
+	case "magenta":
+		paint = c.Magenta
+	case "cyan":
+		paint = c.Cyan
+	case "blue":
+		paint = c.Blue
+	default:
+		paint = c.paintTCell(color.TCell())
+	}
+
+	if color.Bold() {
+		return c.Bold(paint(t))
+	}
+	return paint(t)
+}
+
+func (c *ColorScheme) paintTCell(color tcell.Color) func(string) string {
+	index := -1
+	if color.Valid() && !color.IsRGB() {
+		index = int(color - tcell.ColorValid)
+	}
+	if color.Valid() && (index < 0 || index > 255) && c.is256enabled {
+		// The colours of the W3C lie past the xterm palette in tcell: use the nearest of its colours.
+		index = int(tcell.FindColor(color, xtermPalette) - tcell.ColorValid)
+	}
+
+	var code string
+	switch {
+	case c.hasTrueColor && color.Valid():
+		r, g, b := color.RGB()
+		code = fmt.Sprintf("38;2;%d;%d;%d", r, g, b)
+	case index >= 0 && index < 8:
+		code = strconv.Itoa(30 + index)
+	case index >= 8 && index < 16:
+		code = strconv.Itoa(90 + index - 8)
+	case index >= 0 && index < 256 && c.is256enabled:
+		code = fmt.Sprintf("38;5;%d", index)
+	default:
+		return func(s string) string { return s }
+	}
+	return func(s string) string {
+		return fmt.Sprintf("\x1b[%sm%s\x1b[0m", code, s)
+	}
+}
+
+// xtermPalette are the 256 colours of xterm, past the 16 colours that terminals may change.
+var xtermPalette = func() []tcell.Color {
+	palette := make([]tcell.Color, 0, 240)
+	for i := 16; i < 256; i++ {
+		palette = append(palette, tcell.PaletteColor(i))
+	}
+	return palette
+}()
@@ -3,6 +3,8 @@ package iostreams
 import (
 	"testing"
 
+	"github.com/logfire-sh/cli/internal/theme"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -93,3 +95,67 @@ func TestHexToRGB(t *testing.T) {
 		assert.Equal(t, tt.wants, output)
 	}
 }
+
+func TestPaint(t *testing.T) {
+	tests := []struct {
+		name  string
+		color theme.Color
+		wants string
+		cs    *ColorScheme
+	}{
+		{
+			name:  "basic",
+			color: "red",
+			wants: "\033[0;31mtext\033[0m",
+			cs:    NewColorScheme(true, false, false),
+		},
+		{
+			name:  "bold",
+			color: "bold red",
+			wants: "\033[0;1;39m\033[0;31mtext\033[0m\033[0m",
+			cs:    NewColorScheme(true, false, false),
+		},
+		{
+			name:  "bright",
+			color: "fuchsia",
+			wants: "\033[95mtext\033[0m",
+			cs:    NewColorScheme(true, false, false),
+		},
+		{
+			name:  "256 colors",
+			color: "darkorange",
+			wants: "\033[38;5;208mtext\033[0m",
+			cs:    NewColorScheme(true, true, false),
+		},
+		{
+			name:  "256 colors unsupported",
+			color: "darkorange",
+			wants: "text",
+			cs:    NewColorScheme(true, false, false),
+		},
+		{
+			name:  "truecolor",
+			color: "#ff8700",
+			wants: "\033[38;2;255;135;0mtext\033[0m",
+			cs:    NewColorScheme(true, true, true),
+		},
+		{
+			name:  "default",
+			color: "",
+			wants: "text",
+			cs:    NewColorScheme(true, true, true),
+		},
+		{
+			name:  "no color",
+			color: "bold red",
+			wants: "text",
+			cs:    NewColorScheme(false, false, false),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wants, tt.cs.Paint(tt.color, "text"))
+		})
+	}
+}
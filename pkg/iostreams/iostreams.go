@@ -15,6 +15,7 @@ import (
 	"github.com/briandowns/spinner"
 	"github.com/cli/safeexec"
 	"github.com/google/shlex"
+	"github.com/logfire-sh/cli/internal/theme"
 	logfireTerm "github.com/logfire-sh/cli/utils/term"
 	"github.com/mattn/go-colorable"
 	"github.com/mattn/go-isatty"
@@ -55,6 +56,10 @@ type IOStreams struct {
 
 	terminalTheme string
 
+	loadPalette func() *theme.Palette
+	paletteOnce sync.Once
+	palette     *theme.Palette
+
 	progressIndicatorEnabled bool
 	progressIndicator        *spinner.Spinner
 	progressIndicatorMu      sync.Mutex
@@ -375,7 +380,18 @@ func (s *IOStreams) TerminalWidth() int {
 }
 
 func (s *IOStreams) ColorScheme() *ColorScheme {
-	return NewColorScheme(s.ColorEnabled(), s.ColorSupport256(), s.HasTrueColor())
+	cs := NewColorScheme(s.ColorEnabled(), s.ColorSupport256(), s.HasTrueColor())
+	if s.loadPalette != nil {
+		s.paletteOnce.Do(func() { s.palette = s.loadPalette() })
+		cs.palette = s.palette
+	}
+	return cs
+}
+
+// SetPalette sets how the colour schemes load the palette of log lines. load runs once, when
+// the first colour scheme is made, so that it can read the config selected by the flags.
+func (s *IOStreams) SetPalette(load func() *theme.Palette) {
+	s.loadPalette = load
 }
 
 func (s *IOStreams) ReadUserFile(fn string) ([]byte, error) {